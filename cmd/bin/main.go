@@ -20,6 +20,8 @@ func main() {
 	consumerCmd := flag.NewFlagSet("consumer", flag.ExitOnError)
 	wsCmd := flag.NewFlagSet("ws", flag.ExitOnError)
 	cronjobCmd := flag.NewFlagSet("cronjob", flag.ExitOnError)
+	generateRegistrationsCmd := flag.NewFlagSet("generate-registrations", flag.ExitOnError)
+	recomputeFeesCmd := flag.NewFlagSet("recompute-fees", flag.ExitOnError)
 
 	if len(os.Args) < 2 {
 		log.Info().Msg("No command provided, defaulting to 'server'")
@@ -36,6 +38,10 @@ func main() {
 		cmd.RunServer(serverCmd, os.Args[2:])
 	case "cronjob":
 		cmd.RunCronjob(cronjobCmd, os.Args[2:])
+	case "generate-registrations":
+		cmd.RunGenerateRegistrations(generateRegistrationsCmd, os.Args[2:])
+	case "recompute-fees":
+		cmd.RunRecomputeFees(recomputeFeesCmd, os.Args[2:])
 	case "ws":
 		cmd.RunWebsocket(wsCmd, os.Args[2:])
 	default:
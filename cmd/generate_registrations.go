@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/internal/module/report/repository"
+	"codebase-app/internal/module/report/service"
+	"codebase-app/pkg/validator"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunGenerateRegistrations generates the billing period's registrations from
+// every active, finance-complete template and writes a run report as JSON.
+// The billing period defaults to this month.
+// It is meant to be scheduled monthly, e.g. from crontab:
+//
+//	0 1 1 * * ./kpf-app generate-registrations -user_id=<ULID>
+func RunGenerateRegistrations(cmd *flag.FlagSet, args []string) {
+	var (
+		userId        = cmd.String("user_id", "", "user id recorded as the creator of generated registrations")
+		billingPeriod = cmd.String("billing_period", "", "billing period to generate (YYYY-MM), defaults to this month")
+		reportDir     = cmd.String("report_dir", "./storage/private/generate-registrations", "directory for the run report")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithPostgres(),
+		adapter.WithValidator(validator.NewValidator()),
+	)
+
+	// the connection is closed before exiting, a deferred close would not run
+	// after os.Exit
+	err := generateRegistrations(*userId, *billingPeriod, *reportDir)
+
+	if errUnsync := adapter.Adapters.Unsync(); errUnsync != nil {
+		log.Error().Err(errUnsync).Msg("Error while closing database connection")
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// generateRegistrations runs RunGenerateRegistrations once the adapters are
+// synced, errors are logged before they are returned
+func generateRegistrations(userId, billingPeriod, reportDir string) error {
+	req := &entity.GenerateRegistrationsReq{UserId: userId, BillingPeriod: billingPeriod}
+	req.SetDefault()
+	if err := adapter.Adapters.Validator.Validate(req); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("Invalid user_id or billing_period flag")
+		return err
+	}
+
+	svc := service.NewReportService(repository.NewReportRepository())
+
+	resp, err := svc.GenerateRegistrations(context.Background(), req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to generate registrations")
+		return err
+	}
+
+	log.Info().
+		Str("period", resp.Period).
+		Int("created", len(resp.Created)).
+		Int("skipped", len(resp.Skipped)).
+		Int("failed", len(resp.Failed)).
+		Msg("Registrations generated")
+
+	reportFile, err := writeGenerateRegistrationsReport(reportDir, resp)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write run report")
+		return err
+	}
+
+	log.Info().Str("report", reportFile).Msg("Run report written")
+
+	return nil
+}
+
+// writeGenerateRegistrationsReport stores the run report and returns its path
+func writeGenerateRegistrationsReport(dir string, resp *entity.GenerateRegistrationsResp) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	report, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("generate_registrations_%s_%s.json", resp.Period, time.Now().UTC().Format("20060102150405")))
+	if err := os.WriteFile(filename, report, 0600); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return filename, nil
+}
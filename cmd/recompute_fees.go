@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/internal/module/report/repository"
+	"codebase-app/internal/module/report/service"
+	"codebase-app/pkg/validator"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// RunRecomputeFees checks the values stored from the fees of every registration,
+// or those of one billing period, and writes the ones that disagree as a JSON report.
+// Nothing is updated, the report is meant to be reviewed by an admin.
+//
+//	./kpf-app recompute-fees -billing_period=2026-10
+func RunRecomputeFees(cmd *flag.FlagSet, args []string) {
+	var (
+		billingPeriod = cmd.String("billing_period", "", "billing period to check (YYYY-MM), defaults to every period")
+		reportDir     = cmd.String("report_dir", "./storage/private/recompute-fees", "directory for the report")
+	)
+
+	if err := cmd.Parse(args); err != nil {
+		log.Fatal().Err(err).Msg("Error while parsing flags")
+	}
+
+	adapter.Adapters.Sync(
+		adapter.WithPostgres(),
+		adapter.WithValidator(validator.NewValidator()),
+	)
+
+	// the connection is closed before exiting, a deferred close would not run
+	// after os.Exit
+	err := recomputeFees(*billingPeriod, *reportDir)
+
+	if errUnsync := adapter.Adapters.Unsync(); errUnsync != nil {
+		log.Error().Err(errUnsync).Msg("Error while closing database connection")
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+// recomputeFees runs RunRecomputeFees once the adapters are synced, errors are
+// logged before they are returned
+func recomputeFees(billingPeriod, reportDir string) error {
+	req := &entity.RecomputeFeesReq{BillingPeriod: billingPeriod}
+	if err := adapter.Adapters.Validator.Validate(req); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("Invalid billing_period flag")
+		return err
+	}
+
+	svc := service.NewReportService(repository.NewReportRepository())
+
+	resp, err := svc.RecomputeFees(context.Background(), req)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to recompute fees")
+		return err
+	}
+
+	log.Info().
+		Int("checked", resp.Checked).
+		Int("mismatches", len(resp.Mismatches)).
+		Msg("Fees recomputed")
+
+	reportFile, err := writeRecomputeFeesReport(reportDir, resp)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to write report")
+		return err
+	}
+
+	log.Info().Str("report", reportFile).Msg("Report written")
+
+	return nil
+}
+
+// writeRecomputeFeesReport stores the report and returns its path
+func writeRecomputeFeesReport(dir string, resp *entity.RecomputeFeesResp) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	report, err := json.MarshalIndent(resp, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	period := resp.BillingPeriod
+	if period == "" {
+		period = "all"
+	}
+
+	filename := filepath.Join(dir, fmt.Sprintf("recompute_fees_%s_%s.json", period, time.Now().UTC().Format("20060102150405")))
+	if err := os.WriteFile(filename, report, 0600); err != nil {
+		return "", fmt.Errorf("failed to write report: %w", err)
+	}
+
+	return filename, nil
+}
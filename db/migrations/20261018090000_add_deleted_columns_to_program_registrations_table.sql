@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    ADD COLUMN IF NOT EXISTS deleted_by CHAR(26),
+    ADD COLUMN IF NOT EXISTS deleted_reason VARCHAR(255),
+    ADD CONSTRAINT program_registrations_deleted_by_fkey FOREIGN KEY (deleted_by) REFERENCES users (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    DROP CONSTRAINT IF EXISTS program_registrations_deleted_by_fkey,
+    DROP COLUMN IF EXISTS deleted_reason,
+    DROP COLUMN IF EXISTS deleted_by;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    ADD COLUMN IF NOT EXISTS billing_period DATE;
+
+-- billing period is the first day of the month the registration is billed for,
+-- existing rows take the month they were started (or created) in Asia/Makassar
+UPDATE program_registrations
+SET billing_period = DATE_TRUNC('month', COALESCE(started_at, created_at) AT TIME ZONE 'Asia/Makassar')::DATE
+WHERE billing_period IS NULL;
+
+ALTER TABLE program_registrations
+    ALTER COLUMN billing_period SET NOT NULL,
+    ADD CONSTRAINT program_registrations_billing_period_check CHECK (billing_period = DATE_TRUNC('month', billing_period)::DATE);
+
+CREATE INDEX IF NOT EXISTS program_registrations_billing_period_idx
+    ON program_registrations (billing_period, program_id, student_id, lecturer_id)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS program_registrations_billing_period_idx;
+
+ALTER TABLE program_registrations
+    DROP CONSTRAINT IF EXISTS program_registrations_billing_period_check,
+    DROP COLUMN IF EXISTS billing_period;
+-- +goose StatementEnd
@@ -0,0 +1,56 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS registration_payments (
+    id CHAR(26) PRIMARY KEY,
+    registration_id CHAR(26) NOT NULL,
+    user_id CHAR(26),
+    amount DECIMAL(19, 4) NOT NULL CHECK (amount > 0),
+    method VARCHAR(50) NOT NULL,
+    reference VARCHAR(255),
+    notes VARCHAR(255),
+    paid_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    deleted_by CHAR(26),
+
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (deleted_by) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS registration_payments_registration_id_idx
+    ON registration_payments (registration_id)
+    WHERE deleted_at IS NULL;
+
+-- existing registrations were considered fully paid at paid_at, keep them that way
+-- by recording a single legacy payment. The registration id is reused as the
+-- payment id since every registration gets at most one legacy payment.
+INSERT INTO registration_payments (id, registration_id, user_id, amount, method, notes, paid_at)
+SELECT
+    pr.id,
+    pr.id,
+    pr.user_id,
+    COALESCE(pr.administration_fee, 0)
+    + COALESCE(pr.program_fee, 0)
+    + COALESCE(pr.overpayment_fee, 0)
+    + COALESCE(pr.night_learning_fee, 0)
+    + COALESCE(pr.foreign_learning_fee, 0),
+    'legacy',
+    'Pembayaran sebelum pencatatan cicilan',
+    pr.paid_at
+FROM
+    program_registrations pr
+WHERE
+    COALESCE(pr.administration_fee, 0)
+    + COALESCE(pr.program_fee, 0)
+    + COALESCE(pr.overpayment_fee, 0)
+    + COALESCE(pr.night_learning_fee, 0)
+    + COALESCE(pr.foreign_learning_fee, 0) > 0
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS registration_payments;
+-- +goose StatementEnd
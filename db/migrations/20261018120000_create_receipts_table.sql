@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS receipt_sequences (
+    year INT PRIMARY KEY,
+    last_number INT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS receipts (
+    id CHAR(26) PRIMARY KEY,
+    registration_id CHAR(26) NOT NULL UNIQUE,
+    receipt_number VARCHAR(50) NOT NULL UNIQUE,
+    year INT NOT NULL,
+    sequence INT NOT NULL,
+    amount DECIMAL(19, 4) NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    issued_by CHAR(26) NOT NULL,
+    issued_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    reissue_count INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    UNIQUE (year, sequence),
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id),
+    FOREIGN KEY (issued_by) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS receipt_reissues (
+    id CHAR(26) PRIMARY KEY,
+    receipt_id CHAR(26) NOT NULL,
+    user_id CHAR(26) NOT NULL,
+    reason VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (receipt_id) REFERENCES receipts (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS receipt_reissues;
+DROP TABLE IF EXISTS receipts;
+DROP TABLE IF EXISTS receipt_sequences;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS commission_payout_batches (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    billing_period DATE NOT NULL,
+    paid_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reference VARCHAR(255) NOT NULL,
+    notes VARCHAR(255),
+    total_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    unlocked_at TIMESTAMP WITH TIME ZONE,
+    unlocked_by CHAR(26),
+    unlocked_reason VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT commission_payout_batches_billing_period_check CHECK (billing_period = DATE_TRUNC('month', billing_period)::DATE),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (unlocked_by) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS commission_payout_items (
+    id CHAR(26) PRIMARY KEY,
+    batch_id CHAR(26) NOT NULL,
+    registration_id CHAR(26) NOT NULL,
+    marketer_id CHAR(26) NOT NULL,
+    marketer_commission_fee DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    marketer_gifts_fee DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    -- copied from the batch so a registration can only be in one locked batch
+    unlocked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (batch_id) REFERENCES commission_payout_batches (id) ON DELETE CASCADE,
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id),
+    FOREIGN KEY (marketer_id) REFERENCES marketers (id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS commission_payout_items_registration_locked_unique
+    ON commission_payout_items (registration_id)
+    WHERE unlocked_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS commission_payout_items_batch_id_idx ON commission_payout_items (batch_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS commission_payout_items;
+DROP TABLE IF EXISTS commission_payout_batches;
+-- +goose StatementEnd
@@ -0,0 +1,77 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE lecturers
+    ADD COLUMN IF NOT EXISTS bank_name VARCHAR(100),
+    ADD COLUMN IF NOT EXISTS bank_account_number VARCHAR(50),
+    ADD COLUMN IF NOT EXISTS bank_account_name VARCHAR(255);
+
+CREATE TABLE IF NOT EXISTS lecturer_payout_batches (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    billing_period DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    notes VARCHAR(255),
+    total_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    approved_by CHAR(26),
+    paid_at TIMESTAMP WITH TIME ZONE,
+    paid_by CHAR(26),
+    reference VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT lecturer_payout_batches_status_check CHECK (status IN ('draft', 'approved', 'paid')),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (approved_by) REFERENCES users (id),
+    FOREIGN KEY (paid_by) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS lecturer_payout_statements (
+    id CHAR(26) PRIMARY KEY,
+    lecturer_id CHAR(26) NOT NULL,
+    billing_period DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    batch_id CHAR(26),
+    total_hr_fee DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    total_amount DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    approved_at TIMESTAMP WITH TIME ZONE,
+    approved_by CHAR(26),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT lecturer_payout_statements_status_check CHECK (status IN ('draft', 'approved')),
+    CONSTRAINT lecturer_payout_statements_lecturer_period_unique UNIQUE (lecturer_id, billing_period),
+    FOREIGN KEY (lecturer_id) REFERENCES lecturers (id),
+    FOREIGN KEY (batch_id) REFERENCES lecturer_payout_batches (id) ON DELETE SET NULL,
+    FOREIGN KEY (approved_by) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS lecturer_payout_statement_items (
+    id CHAR(26) PRIMARY KEY,
+    statement_id CHAR(26) NOT NULL,
+    registration_id CHAR(26) NOT NULL,
+    hr_fee_for_lecturer DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    used_amount DECIMAL(19, 4),
+    notes VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT lecturer_payout_statement_items_registration_unique UNIQUE (registration_id),
+    FOREIGN KEY (statement_id) REFERENCES lecturer_payout_statements (id) ON DELETE CASCADE,
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id)
+);
+
+CREATE INDEX IF NOT EXISTS lecturer_payout_statements_batch_id_idx ON lecturer_payout_statements (batch_id);
+CREATE INDEX IF NOT EXISTS lecturer_payout_statement_items_statement_id_idx ON lecturer_payout_statement_items (statement_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS lecturer_payout_statement_items;
+DROP TABLE IF EXISTS lecturer_payout_statements;
+DROP TABLE IF EXISTS lecturer_payout_batches;
+
+ALTER TABLE lecturers
+    DROP COLUMN IF EXISTS bank_name,
+    DROP COLUMN IF EXISTS bank_account_number,
+    DROP COLUMN IF EXISTS bank_account_name;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS hr_fee_split_rules (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26) NOT NULL,
+    scope VARCHAR(20) NOT NULL,
+    program_id CHAR(26),
+    lecturer_id CHAR(26),
+    split_type VARCHAR(20) NOT NULL,
+    mentor_value DECIMAL(19, 4) NOT NULL,
+    notes VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+
+    CONSTRAINT hr_fee_split_rules_scope_check CHECK (
+        (scope = 'global' AND program_id IS NULL AND lecturer_id IS NULL)
+        OR (scope = 'program' AND program_id IS NOT NULL AND lecturer_id IS NULL)
+        OR (scope = 'lecturer' AND lecturer_id IS NOT NULL AND program_id IS NULL)
+    ),
+    CONSTRAINT hr_fee_split_rules_split_type_check CHECK (split_type IN ('percentage', 'fixed')),
+    CONSTRAINT hr_fee_split_rules_mentor_value_check CHECK (
+        mentor_value >= 0 AND (split_type = 'fixed' OR mentor_value <= 100)
+    ),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (program_id) REFERENCES programs (id),
+    FOREIGN KEY (lecturer_id) REFERENCES lecturers (id)
+);
+
+-- one active rule per scope target
+CREATE UNIQUE INDEX IF NOT EXISTS hr_fee_split_rules_global_unique
+    ON hr_fee_split_rules (scope)
+    WHERE scope = 'global' AND deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS hr_fee_split_rules_program_unique
+    ON hr_fee_split_rules (program_id)
+    WHERE scope = 'program' AND deleted_at IS NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS hr_fee_split_rules_lecturer_unique
+    ON hr_fee_split_rules (lecturer_id)
+    WHERE scope = 'lecturer' AND deleted_at IS NULL;
+
+ALTER TABLE program_registrations
+    ADD COLUMN IF NOT EXISTS hr_fee_split_rule_id CHAR(26) REFERENCES hr_fee_split_rules (id),
+    ADD COLUMN IF NOT EXISTS hr_fee_split_manual BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- splits typed in before the rules existed are kept as manual overrides
+UPDATE program_registrations
+SET hr_fee_split_manual = TRUE
+WHERE mentor_detail_fee IS NOT NULL OR hr_detail_fee IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    DROP COLUMN IF EXISTS hr_fee_split_rule_id,
+    DROP COLUMN IF EXISTS hr_fee_split_manual;
+
+DROP TABLE IF EXISTS hr_fee_split_rules;
+-- +goose StatementEnd
@@ -0,0 +1,50 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS mentor_fee_usages (
+    id CHAR(26) PRIMARY KEY,
+    registration_id CHAR(26) NOT NULL,
+    user_id CHAR(26),
+    amount DECIMAL(19, 4) NOT NULL CHECK (amount <> 0),
+    notes VARCHAR(255),
+    used_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    reversal_of CHAR(26),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    -- a draw is positive, its reversal is the negated amount of the draw
+    CONSTRAINT mentor_fee_usages_amount_check CHECK (
+        (reversal_of IS NULL AND amount > 0) OR (reversal_of IS NOT NULL AND amount < 0)
+    ),
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (reversal_of) REFERENCES mentor_fee_usages (id)
+);
+
+CREATE INDEX IF NOT EXISTS mentor_fee_usages_registration_id_idx
+    ON mentor_fee_usages (registration_id);
+
+-- a draw can only be reversed once
+CREATE UNIQUE INDEX IF NOT EXISTS mentor_fee_usages_reversal_of_unique
+    ON mentor_fee_usages (reversal_of)
+    WHERE reversal_of IS NOT NULL;
+
+-- the single used amount of existing registrations becomes their first draw. The
+-- registration id is reused as the usage id since there is at most one per registration.
+INSERT INTO mentor_fee_usages (id, registration_id, user_id, amount, notes, used_at)
+SELECT
+    pr.id,
+    pr.id,
+    pr.user_id,
+    pr.mentor_detail_fee_used,
+    pr.notes_for_fund_distributions,
+    COALESCE(pr.used_at, pr.updated_at, pr.created_at)
+FROM
+    program_registrations pr
+WHERE
+    pr.mentor_detail_fee_used > 0
+ON CONFLICT (id) DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS mentor_fee_usages;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id CHAR(26) PRIMARY KEY,
+    user_id CHAR(26),
+    ip VARCHAR(45),
+    entity VARCHAR(50) NOT NULL,
+    entity_id CHAR(26) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT audit_logs_action_check CHECK (action IN ('create', 'update', 'delete', 'restore'))
+);
+
+CREATE INDEX IF NOT EXISTS audit_logs_entity_idx
+    ON audit_logs (entity, entity_id, created_at);
+
+CREATE INDEX IF NOT EXISTS audit_logs_user_id_idx
+    ON audit_logs (user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS audit_logs_created_at_idx
+    ON audit_logs (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_logs;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a billing period without a row is open, the row is kept when it is reopened
+-- so the last closing snapshot stays available
+CREATE TABLE IF NOT EXISTS accounting_periods (
+    id CHAR(26) PRIMARY KEY,
+    period DATE NOT NULL,
+    status VARCHAR(10) NOT NULL,
+    closed_at TIMESTAMP WITH TIME ZONE,
+    closed_by CHAR(26),
+    close_reason VARCHAR(255),
+    reopened_at TIMESTAMP WITH TIME ZONE,
+    reopened_by CHAR(26),
+    reopen_reason VARCHAR(255),
+    summary JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT accounting_periods_period_unique UNIQUE (period),
+    CONSTRAINT accounting_periods_period_check CHECK (period = DATE_TRUNC('month', period)::DATE),
+    CONSTRAINT accounting_periods_status_check CHECK (status IN ('open', 'closed')),
+    FOREIGN KEY (closed_by) REFERENCES users (id),
+    FOREIGN KEY (reopened_by) REFERENCES users (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS accounting_periods;
+-- +goose StatementEnd
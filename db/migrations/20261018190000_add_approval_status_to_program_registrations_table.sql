@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    ADD COLUMN IF NOT EXISTS approval_status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    ADD COLUMN IF NOT EXISTS submitted_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS submitted_by CHAR(26),
+    ADD COLUMN IF NOT EXISTS reviewed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS reviewed_by CHAR(26),
+    ADD COLUMN IF NOT EXISTS review_comment VARCHAR(255),
+    ADD CONSTRAINT program_registrations_approval_status_check CHECK (approval_status IN ('draft', 'submitted', 'approved', 'rejected')),
+    ADD CONSTRAINT program_registrations_submitted_by_fkey FOREIGN KEY (submitted_by) REFERENCES users (id),
+    ADD CONSTRAINT program_registrations_reviewed_by_fkey FOREIGN KEY (reviewed_by) REFERENCES users (id);
+
+-- existing registrations are already reported, they stay in the reports
+UPDATE program_registrations
+SET approval_status = 'approved';
+
+CREATE INDEX IF NOT EXISTS program_registrations_approval_status_idx
+    ON program_registrations (approval_status)
+    WHERE deleted_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS program_registrations_approval_status_idx;
+
+ALTER TABLE program_registrations
+    DROP CONSTRAINT IF EXISTS program_registrations_reviewed_by_fkey,
+    DROP CONSTRAINT IF EXISTS program_registrations_submitted_by_fkey,
+    DROP CONSTRAINT IF EXISTS program_registrations_approval_status_check,
+    DROP COLUMN IF EXISTS review_comment,
+    DROP COLUMN IF EXISTS reviewed_by,
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS submitted_by,
+    DROP COLUMN IF EXISTS submitted_at,
+    DROP COLUMN IF EXISTS approval_status;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- fee edits of a template wait here until an approver accepts them, the fees
+-- are the proposed values of the template columns with the same names
+CREATE TABLE IF NOT EXISTS template_fee_changes (
+    id CHAR(26) PRIMARY KEY,
+    template_id CHAR(26) NOT NULL,
+    user_id CHAR(26) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    program_fee DECIMAL(19, 4),
+    administration_fee DECIMAL(19, 4),
+    foreign_learning_fee DECIMAL(19, 4),
+    night_learning_fee DECIMAL(19, 4),
+    marketer_commission_fee DECIMAL(19, 4),
+    overpayment_fee DECIMAL(19, 4),
+    hr_fee DECIMAL(19, 4),
+    marketer_gifts_fee DECIMAL(19, 4),
+    closing_fee_for_office DECIMAL(19, 4),
+    closing_fee_for_reward DECIMAL(19, 4),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    reviewed_by CHAR(26),
+    review_comment VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT template_fee_changes_status_check CHECK (status IN ('pending', 'approved', 'rejected')),
+    FOREIGN KEY (template_id) REFERENCES program_registration_templates (id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    FOREIGN KEY (reviewed_by) REFERENCES users (id)
+);
+
+-- a newer edit replaces the pending change of the template
+CREATE UNIQUE INDEX IF NOT EXISTS template_fee_changes_pending_unique
+    ON template_fee_changes (template_id)
+    WHERE status = 'pending';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS template_fee_changes;
+-- +goose StatementEnd
@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS registration_meetings (
+    id CHAR(26) PRIMARY KEY,
+    registration_id CHAR(26) NOT NULL,
+    lecturer_id CHAR(26),
+    user_id CHAR(26) NOT NULL,
+    met_on DATE NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    notes VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    FOREIGN KEY (registration_id) REFERENCES program_registrations (id) ON DELETE CASCADE,
+    FOREIGN KEY (lecturer_id) REFERENCES lecturers (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX IF NOT EXISTS registration_meetings_registration_id_idx
+    ON registration_meetings (registration_id, met_on);
+
+-- the attendee name is kept because additional students of a registration are
+-- recreated whenever the registration is edited
+CREATE TABLE IF NOT EXISTS registration_meeting_attendees (
+    id CHAR(26) PRIMARY KEY,
+    meeting_id CHAR(26) NOT NULL,
+    student_id CHAR(26),
+    name VARCHAR(255) NOT NULL,
+
+    FOREIGN KEY (meeting_id) REFERENCES registration_meetings (id) ON DELETE CASCADE,
+    FOREIGN KEY (student_id) REFERENCES students (id)
+);
+
+CREATE INDEX IF NOT EXISTS registration_meeting_attendees_meeting_id_idx
+    ON registration_meeting_attendees (meeting_id);
+
+-- the planned meetings are the weekdays of the registration in its billing month,
+-- days holds ISO weekdays where 1 is monday
+UPDATE program_registrations pr
+SET program_meetings = (
+    SELECT
+        COUNT(*)
+    FROM
+        GENERATE_SERIES(pr.billing_period, pr.billing_period + INTERVAL '1 month' - INTERVAL '1 day', INTERVAL '1 day') d
+    WHERE
+        EXTRACT(ISODOW FROM d)::INT = ANY(pr.days)
+)
+WHERE pr.program_meetings = 0;
+
+-- lecturer accounts are matched to their lecturer by email
+INSERT INTO roles (id, name)
+SELECT '01JA9YF3M0S1QX7V2K8N4D6PRT', 'lecturer'
+WHERE NOT EXISTS (SELECT 1 FROM roles WHERE name = 'lecturer');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS registration_meeting_attendees;
+DROP TABLE IF EXISTS registration_meetings;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- price changes of a program, the price on programs is the initial price that
+-- applies until the first change. A change applies to the billing periods from
+-- effective_from on, which is always the first day of a month.
+CREATE TABLE IF NOT EXISTS program_prices (
+    id CHAR(26) PRIMARY KEY,
+    program_id CHAR(26) NOT NULL,
+    user_id CHAR(26),
+    effective_from DATE NOT NULL,
+    price DECIMAL(19, 4) NOT NULL,
+    commission_fee DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    lecturer_fee DECIMAL(19, 4) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP NOT NULL,
+
+    CONSTRAINT program_prices_effective_from_check CHECK (EXTRACT(DAY FROM effective_from) = 1),
+    CONSTRAINT program_prices_fees_check CHECK (price >= 0 AND commission_fee >= 0 AND lecturer_fee >= 0),
+    CONSTRAINT program_prices_program_effective_from_unique UNIQUE (program_id, effective_from),
+    FOREIGN KEY (program_id) REFERENCES programs (id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS program_prices;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the calendar feed link of a lecturer is signed with its version, resetting
+-- the link bumps the version so links handed out before stop working
+ALTER TABLE lecturers ADD COLUMN IF NOT EXISTS calendar_version INT NOT NULL DEFAULT 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE lecturers DROP COLUMN IF EXISTS calendar_version;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every program keeps its initial price in program_prices, effective from the
+-- month it was created. The price on programs mirrors the price in effect in
+-- the billing period it was last saved in, prices are read from program_prices.
+INSERT INTO program_prices (
+    id,
+    program_id,
+    effective_from,
+    price,
+    commission_fee,
+    lecturer_fee,
+    created_at,
+    updated_at
+)
+SELECT
+    p.id,
+    p.id,
+    DATE_TRUNC('month', p.created_at)::DATE,
+    p.price,
+    p.commission_fee,
+    p.lecturer_fee,
+    p.created_at,
+    p.created_at
+FROM
+    programs p
+WHERE
+    NOT EXISTS (
+        SELECT
+            1
+        FROM
+            program_prices pp
+        WHERE
+            pp.program_id = p.id
+            AND pp.effective_from <= DATE_TRUNC('month', p.created_at)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM program_prices WHERE id = program_id;
+-- +goose StatementEnd
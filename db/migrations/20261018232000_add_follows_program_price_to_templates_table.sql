@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a template that follows the program price takes the program fee, marketer
+-- commission and hr fee of its program in effect in each billing period it is
+-- registered for, the fees of other templates were negotiated and are kept
+ALTER TABLE program_registration_templates
+    ADD COLUMN IF NOT EXISTS follows_program_price BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- templates with the fees of the price in effect now are taken as following it
+UPDATE
+    program_registration_templates prt
+SET
+    follows_program_price = TRUE
+WHERE
+    EXISTS (
+        SELECT
+            1
+        FROM (
+            SELECT
+                pp.price,
+                pp.commission_fee,
+                pp.lecturer_fee
+            FROM
+                program_prices pp
+            WHERE
+                pp.program_id = prt.program_id
+                AND pp.effective_from <= NOW()
+            ORDER BY
+                pp.effective_from DESC
+            LIMIT 1
+        ) cp
+        WHERE
+            (prt.program_fee, prt.marketer_commission_fee, prt.hr_fee)
+                IS NOT DISTINCT FROM (cp.price, cp.commission_fee, cp.lecturer_fee)
+    );
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE program_registration_templates DROP COLUMN IF EXISTS follows_program_price;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a change queued by propagating the program price makes the template follow
+-- the program price once it is approved
+ALTER TABLE template_fee_changes
+    ADD COLUMN IF NOT EXISTS follows_program_price BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE template_fee_changes DROP COLUMN IF EXISTS follows_program_price;
+-- +goose StatementEnd
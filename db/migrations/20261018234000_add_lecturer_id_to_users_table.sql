@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+-- binds a lecturer account to its lecturer, accounts made before are bound by
+-- their exact email
+ALTER TABLE users ADD COLUMN IF NOT EXISTS lecturer_id CHAR(26) REFERENCES lecturers (id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_lecturer_id_unique
+    ON users (lecturer_id)
+    WHERE lecturer_id IS NOT NULL;
+
+UPDATE users u
+SET lecturer_id = l.id
+FROM lecturers l, roles r
+WHERE
+    r.id = u.role_id
+    AND r.name = 'lecturer'
+    AND l.email = u.email
+    AND l.deleted_at IS NULL
+    AND u.lecturer_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS users_lecturer_id_unique;
+ALTER TABLE users DROP COLUMN IF EXISTS lecturer_id;
+-- +goose StatementEnd
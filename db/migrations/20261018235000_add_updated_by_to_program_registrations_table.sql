@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the last user who edited a registration is a maker too and can not review it
+ALTER TABLE program_registrations
+    ADD COLUMN IF NOT EXISTS updated_by CHAR(26),
+    ADD CONSTRAINT program_registrations_updated_by_fkey FOREIGN KEY (updated_by) REFERENCES users (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE program_registrations
+    DROP CONSTRAINT IF EXISTS program_registrations_updated_by_fkey,
+    DROP COLUMN IF EXISTS updated_by;
+-- +goose StatementEnd
@@ -289,6 +289,7 @@ func (s *Seed) rolesSeed() {
 		{"id": "01J3VHA25R8KTG9MQX43KBZ9MW", "name": "admin"},
 		{"id": "01J3VHA25R8KTG9MQX45R8F3V7", "name": "service_advisor"},
 		{"id": "01J3VHA25R8KTG9MQX47GRF4KW", "name": "technician"},
+		{"id": "01JA9YF3M0S1QX7V2K8N4D6PRT", "name": "lecturer"},
 	}
 
 	tx, err := s.db.BeginTxx(context.Background(), nil)
@@ -77,6 +77,13 @@ type Config struct {
 			RedirectURL  string `env:"GOOGLE_REDIRECT_URL"`
 		}
 	}
+	Receipt struct {
+		NumberPrefix   string `env:"RECEIPT_NUMBER_PREFIX" env-default:"KW"`  // KW/2026/10/0001
+		SequenceDigits int    `env:"RECEIPT_SEQUENCE_DIGITS" env-default:"4"` // sequence resets every year
+	}
+	Calendar struct {
+		SigningKey string `env:"CALENDAR_SIGNING_KEY"` // signs the lecturer calendar feed links
+	}
 	Dropbox struct {
 		AccessToken string `env:"DROPBOX_ACCESS_TOKEN"`
 	}
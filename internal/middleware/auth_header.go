@@ -2,12 +2,28 @@ package middleware
 
 import (
 	"codebase-app/pkg/jwthandler"
+	"codebase-app/pkg/types"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 )
 
+// limitedRoles only reach the routes registered with AuthBearerLecturer, every
+// other route is closed to them
+var limitedRoles = []string{types.RoleLecturer}
+
+// AuthBearer admits every role but limitedRoles
 func AuthBearer(c *fiber.Ctx) error {
+	return authBearer(c, false)
+}
+
+// AuthBearerLecturer is AuthBearer for the few routes lecturers may use too,
+// the handlers behind it scope the data to the lecturer of the user
+func AuthBearerLecturer(c *fiber.Ctx) error {
+	return authBearer(c, true)
+}
+
+func authBearer(c *fiber.Ctx, allowLimited bool) error {
 	AccessToken := c.Get("Authorization")
 	unauthorizedResponse := fiber.Map{
 		"message": "Unauthorized",
@@ -32,8 +48,21 @@ func AuthBearer(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(unauthorizedResponse)
 	}
 
+	if !allowLimited {
+		for _, role := range limitedRoles {
+			if claims.Role == role {
+				log.Warn().Str("user_id", claims.UserId).Str("role", claims.Role).Msg("middleware::AuthMiddleware - Forbidden [Route closed to role]")
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+					"message": "Terlarang: role anda tidak diizinkan untuk mengakses resource ini",
+					"success": false,
+				})
+			}
+		}
+	}
+
 	c.Locals("user_id", claims.UserId)
 	c.Locals("role", claims.Role)
+	c.Locals("ip", c.IP()) // read by the audit log together with user_id
 
 	// If the token is valid, pass the request to the next handler
 	return c.Next()
@@ -0,0 +1,69 @@
+package entity
+
+import (
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"encoding/json"
+	"slices"
+)
+
+type GetAuditLogsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Entity   string `query:"entity" validate:"omitempty"`
+	EntityId string `query:"entity_id" validate:"omitempty,ulid"`
+	ActorId  string `query:"user_id" validate:"omitempty,ulid"`
+	Action   string `query:"action" validate:"omitempty,oneof=create update delete restore"`
+	From     string `query:"from" validate:"omitempty,datetime=2006-01-02"`
+	To       string `query:"to" validate:"omitempty,datetime=2006-01-02"`
+	Timezone string `query:"timezone" validate:"required,timezone"`
+
+	types.MetaQuery
+}
+
+func (r *GetAuditLogsReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+}
+
+func (r *GetAuditLogsReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	if r.Entity != "" && !slices.Contains(audit.Entities, r.Entity) {
+		err.Add("entity", "entity is not audited")
+	}
+
+	if r.From != "" && r.To != "" && r.From > r.To {
+		err.Add("to", "to must be after from")
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+type GetAuditLogsResp struct {
+	Items []AuditLog `json:"items"`
+	Meta  types.Meta `json:"meta"`
+}
+
+// AuditLog is one change of a row. Before and after only hold the changed
+// columns of an update, the whole row of a creation or a hard deletion.
+type AuditLog struct {
+	Id        string          `json:"id" db:"id"`
+	UserId    *string         `json:"user_id" db:"user_id"`
+	UserName  *string         `json:"user_name" db:"user_name"`
+	Ip        *string         `json:"ip" db:"ip"`
+	Entity    string          `json:"entity" db:"entity"`
+	EntityId  string          `json:"entity_id" db:"entity_id"`
+	Action    string          `json:"action" db:"action"`
+	Before    json.RawMessage `json:"before" db:"before"`
+	After     json.RawMessage `json:"after" db:"after"`
+	CreatedAt string          `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	m "codebase-app/internal/middleware"
+	"codebase-app/internal/module/audit/entity"
+	"codebase-app/internal/module/audit/ports"
+	"codebase-app/internal/module/audit/repository"
+	"codebase-app/internal/module/audit/service"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"codebase-app/pkg/types"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// auditRoles may read the audit log, it holds fees, bank accounts and the
+// addresses of every actor
+var auditRoles = []string{types.RoleAdmin}
+
+type auditHandler struct {
+	service ports.AuditService
+}
+
+func NewAuditHandler() *auditHandler {
+	var (
+		repo    = repository.NewAuditRepository()
+		svc     = service.NewAuditService(repo)
+		handler = new(auditHandler)
+	)
+	handler.service = svc
+
+	return handler
+}
+
+func (h *auditHandler) Register(router fiber.Router) {
+	router.Get("/", m.AuthBearer, m.AuthRole(auditRoles), h.getAuditLogs)
+}
+
+func (h *auditHandler) getAuditLogs(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetAuditLogsReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getAuditLogs - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getAuditLogs - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getAuditLogs - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetAuditLogs(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
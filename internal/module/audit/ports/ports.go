@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"codebase-app/internal/module/audit/entity"
+	"context"
+)
+
+type AuditRepository interface {
+	GetAuditLogs(ctx context.Context, req *entity.GetAuditLogsReq) (*entity.GetAuditLogsResp, error)
+}
+
+type AuditService interface {
+	GetAuditLogs(ctx context.Context, req *entity.GetAuditLogsReq) (*entity.GetAuditLogsResp, error)
+}
@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/audit/entity"
+	"codebase-app/internal/module/audit/ports"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var _ ports.AuditRepository = &auditRepo{}
+
+type auditRepo struct {
+	db *sqlx.DB
+}
+
+func NewAuditRepository() *auditRepo {
+	return &auditRepo{
+		db: adapter.Adapters.Postgres,
+	}
+}
+
+func (r *auditRepo) GetAuditLogs(ctx context.Context, req *entity.GetAuditLogsReq) (*entity.GetAuditLogsResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.AuditLog
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetAuditLogsResp)
+		args = make([]any, 0, 10)
+	)
+	resp.Items = make([]entity.AuditLog, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			al.id,
+			al.user_id,
+			u.name AS user_name,
+			al.ip,
+			al.entity,
+			al.entity_id,
+			al.action,
+			al.before,
+			al.after,
+			al.created_at
+		FROM
+			audit_logs al
+		LEFT JOIN
+			users u
+			ON al.user_id = u.id
+		WHERE
+			1 = 1
+	`
+
+	if req.Entity != "" {
+		query += ` AND al.entity = ?`
+		args = append(args, req.Entity)
+	}
+
+	if req.EntityId != "" {
+		query += ` AND al.entity_id = ?`
+		args = append(args, req.EntityId)
+	}
+
+	if req.ActorId != "" {
+		query += ` AND al.user_id = ?`
+		args = append(args, req.ActorId)
+	}
+
+	if req.Action != "" {
+		query += ` AND al.action = ?`
+		args = append(args, req.Action)
+	}
+
+	if req.From != "" {
+		query += ` AND al.created_at >= TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP AT TIME ZONE ?`
+		args = append(args, req.From, req.Timezone)
+	}
+
+	if req.To != "" {
+		query += ` AND al.created_at < (TO_DATE(?, 'YYYY-MM-DD') + 1)::TIMESTAMP AT TIME ZONE ?`
+		args = append(args, req.To, req.Timezone)
+	}
+
+	query += ` ORDER BY al.created_at DESC, al.id DESC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetAuditLogs - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.AuditLog)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"codebase-app/internal/module/audit/entity"
+	"codebase-app/internal/module/audit/ports"
+	"context"
+)
+
+var _ ports.AuditService = &auditService{}
+
+type auditService struct {
+	repo ports.AuditRepository
+}
+
+func NewAuditService(repo ports.AuditRepository) *auditService {
+	return &auditService{
+		repo: repo,
+	}
+}
+
+func (s *auditService) GetAuditLogs(ctx context.Context, req *entity.GetAuditLogsReq) (*entity.GetAuditLogsResp, error) {
+	return s.repo.GetAuditLogs(ctx, req)
+}
@@ -15,6 +15,14 @@ type Lecturer struct {
 	Common
 	Phone        *string `json:"phone" db:"phone"`
 	RegisteredAt *string `json:"registered_at" db:"registered_at"`
+	LecturerBankAccount
+}
+
+// LecturerBankAccount is where lecturer payouts are transferred to
+type LecturerBankAccount struct {
+	BankName          *string `json:"bank_name" db:"bank_name" validate:"omitempty,max=100"`
+	BankAccountNumber *string `json:"bank_account_number" db:"bank_account_number" validate:"omitempty,numeric,max=50"`
+	BankAccountName   *string `json:"bank_account_name" db:"bank_account_name" validate:"omitempty,max=255"`
 }
 
 type GetLecturersResp struct {
@@ -38,6 +46,7 @@ type CreateLecturerReq struct {
 	Name         string  `json:"name" validate:"required,min=3"`
 	Phone        *string `json:"phone" validate:"omitempty,min=9"`
 	RegisteredAt *string `json:"registered_at" validate:"omitempty,datetime=2006-01-02"`
+	LecturerBankAccount
 }
 
 type CreateLecturerResp struct {
@@ -51,6 +60,7 @@ type UpdateLecturerReq struct {
 	Name         string  `json:"name" validate:"required,min=3"`
 	Phone        *string `json:"phone" validate:"omitempty,min=9"`
 	RegisteredAt *string `json:"registered_at" validate:"omitempty,datetime=2006-01-02"`
+	LecturerBankAccount
 }
 
 type DeleteLecturerReq struct {
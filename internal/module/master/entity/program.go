@@ -1,11 +1,19 @@
 package entity
 
 import (
+	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/types"
+	"time"
 
 	"github.com/lib/pq"
 )
 
+// CurrentBillingPeriod is the month prices are looked up for when no billing
+// period is given
+func CurrentBillingPeriod() string {
+	return time.Now().In(time.FixedZone("Asia/Makassar", 8*3600)).Format("2006-01")
+}
+
 type GetProgramsReq struct {
 	UserId string `validate:"ulid"`
 
@@ -17,6 +25,7 @@ func (r *GetProgramsReq) SetDefault() {
 	r.MetaQuery.SetDefault()
 }
 
+// Program holds the price in effect in the current billing period
 type Program struct {
 	Common
 	Detail        *string       `json:"detail" db:"detail"`
@@ -39,6 +48,20 @@ type GetProgramReq struct {
 
 type GetProgramResp struct {
 	Program
+	Prices []ProgramPrice `json:"prices"`
+}
+
+// ProgramPrice is one entry of the price timeline of a program, the first one
+// is the initial price which also applies to the billing periods before it
+type ProgramPrice struct {
+	Id            string  `json:"id" db:"id"`
+	EffectiveFrom string  `json:"effective_from" db:"effective_from"` // first billing period the price applies to
+	Price         float64 `json:"price" db:"price"`
+	CommissionFee float64 `json:"commission_fee" db:"commission_fee"`
+	LecturerFee   float64 `json:"lecturer_fee" db:"lecturer_fee"`
+	IsCurrent     bool    `json:"is_current" db:"is_current"`
+	UserName      *string `json:"user_name" db:"user_name"`
+	CreatedAt     string  `json:"created_at" db:"created_at"`
 }
 
 type CreateProgramReq struct {
@@ -67,11 +90,56 @@ type UpdateProgramReq struct {
 	CommissionFee float64 `json:"commission_fee" validate:"required,gte=0"`
 	LecturerFee   float64 `json:"lecturer_fee" validate:"required,gte=0"`
 	Days          []int64 `json:"days" validate:"required,min=1,dive,min=1,max=7"`
+	EffectiveFrom string  `json:"effective_from" validate:"datetime=2006-01"` // billing period the price applies from, a future one schedules it
+}
+
+func (r *UpdateProgramReq) SetDefault() {
+	if r.EffectiveFrom == "" {
+		r.EffectiveFrom = CurrentBillingPeriod()
+	}
+}
+
+// Validate keeps the prices of past billing periods, registrations may already
+// have been billed with them
+func (r *UpdateProgramReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	if r.EffectiveFrom < CurrentBillingPeriod() {
+		err.Add("effective_from", "effective_from tidak boleh sebelum periode berjalan")
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+// ChangesPrice reports whether the fees of the request differ from the fees in
+// effect in req.EffectiveFrom, an unchanged price is not recorded again
+func (r *UpdateProgramReq) ChangesPrice(inEffect ProgramFees) bool {
+	return r.Price != inEffect.Price ||
+		r.CommissionFee != inEffect.CommissionFee ||
+		r.LecturerFee != inEffect.LecturerFee
+}
+
+// AppliesNow reports whether the price applies from the current billing
+// period, a later one schedules it
+func (r *UpdateProgramReq) AppliesNow() bool {
+	return r.EffectiveFrom <= CurrentBillingPeriod()
+}
+
+// ProgramFees are the fees of a price of a program
+type ProgramFees struct {
+	Price         float64 `db:"price"`
+	CommissionFee float64 `db:"commission_fee"`
+	LecturerFee   float64 `db:"lecturer_fee"`
 }
 
 type UpdateProgramResp struct {
-	UserId string `validate:"ulid"`
-	Id     string `json:"id"`
+	UserId        string `validate:"ulid"`
+	Id            string `json:"id"`
+	EffectiveFrom string `json:"effective_from"`
 }
 
 type DeleteProgramReq struct {
@@ -82,3 +150,12 @@ type DeleteProgramReq struct {
 type DeleteProgramResp struct {
 	Id string `json:"id"`
 }
+
+// DeleteProgramPriceReq cancels a scheduled price, prices already in effect are
+// kept
+type DeleteProgramPriceReq struct {
+	UserId string `validate:"ulid"`
+
+	ProgramId string `params:"id" validate:"required,ulid"`
+	Id        string `params:"price_id" validate:"required,ulid"`
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// period returns the billing period months away from the current one
+func period(months int) string {
+	now := time.Now().In(time.FixedZone("Asia/Makassar", 8*3600))
+	return time.Date(now.Year(), now.Month()+time.Month(months), 1, 0, 0, 0, 0, now.Location()).Format("2006-01")
+}
+
+func TestUpdateProgramReqValidate(t *testing.T) {
+	tests := []struct {
+		name          string
+		effectiveFrom string
+		wantErr       bool
+	}{
+		{"current period", period(0), false},
+		{"scheduled", period(2), false},
+		{"past period", period(-1), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &UpdateProgramReq{EffectiveFrom: tt.effectiveFrom}
+
+			err := req.Validate()
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestUpdateProgramReqSetDefault(t *testing.T) {
+	req := new(UpdateProgramReq)
+	req.SetDefault()
+
+	assert.Equal(t, CurrentBillingPeriod(), req.EffectiveFrom)
+	assert.True(t, req.AppliesNow())
+	assert.NoError(t, req.Validate())
+}
+
+func TestUpdateProgramReqChangesPrice(t *testing.T) {
+	inEffect := ProgramFees{Price: 500000, CommissionFee: 50000, LecturerFee: 200000}
+
+	tests := []struct {
+		name string
+		req  UpdateProgramReq
+		want bool
+	}{
+		{"same fees", UpdateProgramReq{Price: 500000, CommissionFee: 50000, LecturerFee: 200000}, false},
+		{"price", UpdateProgramReq{Price: 550000, CommissionFee: 50000, LecturerFee: 200000}, true},
+		{"commission fee", UpdateProgramReq{Price: 500000, CommissionFee: 60000, LecturerFee: 200000}, true},
+		{"lecturer fee", UpdateProgramReq{Price: 500000, CommissionFee: 50000, LecturerFee: 210000}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.req.ChangesPrice(inEffect))
+		})
+	}
+}
+
+func TestUpdateProgramReqAppliesNow(t *testing.T) {
+	assert.True(t, (&UpdateProgramReq{EffectiveFrom: period(0)}).AppliesNow())
+	assert.False(t, (&UpdateProgramReq{EffectiveFrom: period(1)}).AppliesNow())
+	assert.False(t, (&UpdateProgramReq{EffectiveFrom: period(13)}).AppliesNow())
+}
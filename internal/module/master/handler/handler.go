@@ -54,4 +54,5 @@ func (h *masterHandler) Register(router fiber.Router) {
 	router.Get("/programs/:id", m.AuthBearer, h.getProgram)
 	router.Put("/programs/:id", m.AuthBearer, h.updateProgram)
 	router.Delete("/programs/:id", m.AuthBearer, h.deleteProgram)
+	router.Delete("/programs/:id/prices/:price_id", m.AuthBearer, m.AuthRole(programPriceRoles), h.deleteProgramPrice)
 }
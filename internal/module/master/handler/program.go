@@ -6,11 +6,15 @@ import (
 	"codebase-app/internal/module/master/entity"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
+	"codebase-app/pkg/types"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 )
 
+// programPriceRoles may cancel a scheduled program price
+var programPriceRoles = []string{types.RoleAdmin}
+
 func (h *masterHandler) getPrograms(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetProgramsReq)
@@ -111,12 +115,20 @@ func (h *masterHandler) updateProgram(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
 	}
 
+	req.SetDefault()
+
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("req", req).Msg("handler::updateProgram - invalid request")
 		code, errs := errmsg.Errors(err, req)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::updateProgram - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
 	resp, err := h.service.UpdateProgram(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
@@ -150,3 +162,29 @@ func (h *masterHandler) deleteProgram(c *fiber.Ctx) error {
 
 	return c.JSON(response.Success(nil, ""))
 }
+
+func (h *masterHandler) deleteProgramPrice(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteProgramPriceReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.ProgramId = c.Params("id")
+	req.Id = c.Params("price_id")
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::deleteProgramPrice - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteProgramPrice(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.JSON(response.Success(nil, ""))
+}
@@ -35,6 +35,7 @@ type MasterRepository interface {
 	GetProgram(ctx context.Context, req *entity.GetProgramReq) (*entity.GetProgramResp, error)
 	UpdateProgram(ctx context.Context, req *entity.UpdateProgramReq) (*entity.UpdateProgramResp, error)
 	DeleteProgram(ctx context.Context, req *entity.DeleteProgramReq) error
+	DeleteProgramPrice(ctx context.Context, req *entity.DeleteProgramPriceReq) error
 }
 
 type MasterService interface {
@@ -67,4 +68,5 @@ type MasterService interface {
 	GetProgram(ctx context.Context, req *entity.GetProgramReq) (*entity.GetProgramResp, error)
 	UpdateProgram(ctx context.Context, req *entity.UpdateProgramReq) (*entity.UpdateProgramResp, error)
 	DeleteProgram(ctx context.Context, req *entity.DeleteProgramReq) error
+	DeleteProgramPrice(ctx context.Context, req *entity.DeleteProgramPriceReq) error
 }
@@ -2,10 +2,12 @@ package repository
 
 import (
 	"codebase-app/internal/module/master/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
 )
@@ -32,7 +34,10 @@ func (r *masterRepo) GetLecturers(ctx context.Context, req *entity.GetLecturersR
 			CASE
 				WHEN registered_at IS NOT NULL THEN TO_CHAR(registered_at, 'YYYY-MM-DD')
 				ELSE NULL
-			END AS registered_at
+			END AS registered_at,
+			bank_name,
+			bank_account_number,
+			bank_account_name
 		FROM
 			lecturers
 		WHERE
@@ -72,8 +77,11 @@ func (r *masterRepo) CreateLecturer(ctx context.Context, req *entity.CreateLectu
 			id,
 			name,
 			phone,
-			registered_at
-		) VALUES (?, ?, ?, ?)
+			registered_at,
+			bank_name,
+			bank_account_number,
+			bank_account_name
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var (
@@ -81,8 +89,12 @@ func (r *masterRepo) CreateLecturer(ctx context.Context, req *entity.CreateLectu
 		resp = new(entity.CreateLecturerResp)
 	)
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		Id, req.Name, req.Phone, req.RegisteredAt); err != nil {
+	err := r.withAudit(ctx, audit.Lecturer, Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), Id, req.Name, req.Phone, req.RegisteredAt,
+			req.BankName, req.BankAccountNumber, req.BankAccountName)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturer - failed to create lecturer")
 		return nil, err
 	}
@@ -106,7 +118,10 @@ func (r *masterRepo) GetLecturer(ctx context.Context, req *entity.GetLecturerReq
 			CASE
 				WHEN registered_at IS NOT NULL THEN TO_CHAR(registered_at, 'YYYY-MM-DD')
 				ELSE NULL
-			END AS registered_at
+			END AS registered_at,
+			bank_name,
+			bank_account_number,
+			bank_account_name
 		FROM
 			lecturers
 		WHERE
@@ -135,14 +150,21 @@ func (r *masterRepo) UpdateLecturer(ctx context.Context, req *entity.UpdateLectu
 			name = ?,
 			phone = ?,
 			registered_at = ?,
+			bank_name = ?,
+			bank_account_number = ?,
+			bank_account_name = ?,
 			updated_at = NOW()
 		WHERE
 			id = ?
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		req.Name, req.Phone, req.RegisteredAt, req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Lecturer, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Name, req.Phone, req.RegisteredAt,
+			req.BankName, req.BankAccountNumber, req.BankAccountName, req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateLecturer - failed to update lecturer")
 		return err
 	}
@@ -160,7 +182,11 @@ func (r *masterRepo) DeleteLecturer(ctx context.Context, req *entity.DeleteLectu
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Lecturer, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DeleteLecturer - failed to delete lecturer")
 		return err
 	}
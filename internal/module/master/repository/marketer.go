@@ -2,10 +2,12 @@ package repository
 
 import (
 	"codebase-app/internal/module/master/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
 )
@@ -122,7 +124,11 @@ func (r *masterRepo) CreateMarketer(ctx context.Context, req *entity.CreateMarke
 		resp = new(entity.CreateMarketerResp)
 	)
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), Id, req.StudentManagerId, req.Name, req.Email, req.Phone); err != nil {
+	err := r.withAudit(ctx, audit.Marketer, Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), Id, req.StudentManagerId, req.Name, req.Email, req.Phone)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::CreateMarketer - failed to create marketer")
 		return nil, err
 	}
@@ -146,7 +152,11 @@ func (r *masterRepo) UpdateMarketer(ctx context.Context, req *entity.UpdateMarke
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.StudentManagerId, req.Name, req.Email, req.Phone, req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Marketer, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.StudentManagerId, req.Name, req.Email, req.Phone, req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateMarketer - failed to update marketer")
 		return err
 	}
@@ -164,7 +174,11 @@ func (r *masterRepo) DeleteMarketer(ctx context.Context, req *entity.DeleteMarke
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Marketer, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DeleteMarketer - failed to delete marketer")
 		return err
 	}
@@ -2,16 +2,28 @@ package repository
 
 import (
 	"codebase-app/internal/module/master/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/programprice"
 	"context"
 	"database/sql"
 	"net/http"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
 )
 
+// programPriceColumnsSQL are the fees of the program aliased as p in effect,
+// they need programprice.JoinSQL
+const programPriceColumnsSQL = `
+	` + programprice.PriceSQL + ` AS price,
+	` + programprice.LecturerFeeSQL + ` AS lecturer_fee,
+	` + programprice.CommissionFeeSQL + ` AS commission_fee,
+	` + programprice.PriceSQL + ` - ` + programprice.LecturerFeeSQL + ` - ` + programprice.CommissionFeeSQL + ` AS profit
+`
+
 func (r *masterRepo) GetPrograms(ctx context.Context, req *entity.GetProgramsReq) (*entity.GetProgramsResp, error) {
 	type dao struct {
 		TotalData int `db:"total_data"`
@@ -21,29 +33,28 @@ func (r *masterRepo) GetPrograms(ctx context.Context, req *entity.GetProgramsReq
 	var (
 		resp = new(entity.GetProgramsResp)
 		data = make([]dao, 0)
-		args = make([]any, 0, 3)
+		args = make([]any, 0, 4)
 	)
 	resp.Items = make([]entity.Program, 0)
 
 	query := `
 		SELECT
 			COUNT (*) OVER() AS total_data,
-			id,
-			name,
-			detail,
-			price,
-			days,
-			lecturer_fee,
-			commission_fee,
-			price - lecturer_fee - commission_fee AS profit
+			p.id,
+			p.name,
+			p.detail,
+			p.days,
+			` + programPriceColumnsSQL + `
 		FROM
-			programs
+			programs p
+		` + programprice.JoinSQL + `
 		WHERE
-			deleted_at IS NULL
+			p.deleted_at IS NULL
 		`
+	args = append(args, entity.CurrentBillingPeriod())
 
 	if req.Q != "" {
-		query += ` AND name ILIKE '%' || ? || '%'`
+		query += ` AND p.name ILIKE '%' || ? || '%'`
 		args = append(args, req.Q)
 	}
 
@@ -69,26 +80,27 @@ func (r *masterRepo) GetPrograms(ctx context.Context, req *entity.GetProgramsReq
 
 func (r *masterRepo) GetProgram(ctx context.Context, req *entity.GetProgramReq) (*entity.GetProgramResp, error) {
 	var (
-		resp = new(entity.GetProgramResp)
+		resp   = new(entity.GetProgramResp)
+		period = entity.CurrentBillingPeriod()
 	)
+	resp.Prices = make([]entity.ProgramPrice, 0)
+
 	query := `
 		SELECT
-			id,
-			name,
-			detail,
-			price,
-			days,
-			lecturer_fee,
-			commission_fee,
-			price - lecturer_fee - commission_fee AS profit
+			p.id,
+			p.name,
+			p.detail,
+			p.days,
+			` + programPriceColumnsSQL + `
 		FROM
-			programs
+			programs p
+		` + programprice.JoinSQL + `
 		WHERE
-			id = ?
-			AND deleted_at IS NULL
+			p.id = ?
+			AND p.deleted_at IS NULL
 	`
 
-	if err := r.db.GetContext(ctx, resp, r.db.Rebind(query), req.Id); err != nil {
+	if err := r.db.GetContext(ctx, resp, r.db.Rebind(query), period, req.Id); err != nil {
 		if err == sql.ErrNoRows {
 			log.Warn().Err(err).Any("req", req).Msg("repo::GetProgram - program not found")
 			return nil, errmsg.NewCustomErrors(404).SetMessage("Program tidak ditemukan")
@@ -97,6 +109,44 @@ func (r *masterRepo) GetProgram(ctx context.Context, req *entity.GetProgramReq)
 		return nil, err
 	}
 
+	// the initial price followed by its changes, scheduled changes are the ones
+	// after the current price
+	query = `
+		WITH current_price AS (
+			SELECT
+				pp.id
+			FROM
+				programs p
+			` + programprice.JoinSQL + `
+			WHERE
+				p.id = ?
+		)
+		SELECT
+			pp.id,
+			TO_CHAR(pp.effective_from, 'YYYY-MM') AS effective_from,
+			pp.price,
+			pp.commission_fee,
+			pp.lecturer_fee,
+			COALESCE(pp.id = (SELECT id FROM current_price), FALSE) AS is_current,
+			u.name AS user_name,
+			pp.created_at
+		FROM
+			program_prices pp
+		LEFT JOIN
+			users u
+			ON pp.user_id = u.id
+		WHERE
+			pp.program_id = ?
+		ORDER BY
+			pp.effective_from
+	`
+
+	err := r.db.SelectContext(ctx, &resp.Prices, r.db.Rebind(query), period, req.Id, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetProgram - failed to query prices")
+		return nil, err
+	}
+
 	return resp, nil
 }
 
@@ -138,15 +188,50 @@ func (r *masterRepo) CreateProgram(ctx context.Context, req *entity.CreateProgra
 		) VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		id,
-		req.Name,
-		req.Detail,
-		req.Price,
-		pq.Array(req.Days),
-		req.LecturerFee,
-		req.CommissionFee,
-	)
+	// the initial price applies from the current billing period, and to the
+	// periods before it
+	queryPrice := `
+		INSERT INTO program_prices (
+			id,
+			program_id,
+			user_id,
+			effective_from,
+			price,
+			commission_fee,
+			lecturer_fee
+		) VALUES (?, ?, ?, TO_DATE(?, 'YYYY-MM'), ?, ?, ?)
+	`
+
+	err := r.withAudit(ctx, audit.Program, id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query),
+			id,
+			req.Name,
+			req.Detail,
+			req.Price,
+			pq.Array(req.Days),
+			req.LecturerFee,
+			req.CommissionFee,
+		)
+		if err != nil {
+			return err
+		}
+
+		priceId := ulid.Make().String()
+		_, err = tx.ExecContext(ctx, tx.Rebind(queryPrice),
+			priceId,
+			id,
+			req.UserId,
+			entity.CurrentBillingPeriod(),
+			req.Price,
+			req.CommissionFee,
+			req.LecturerFee,
+		)
+		if err != nil {
+			return err
+		}
+
+		return audit.Created(ctx, tx, audit.ProgramPrice, priceId)
+	})
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::CreateProgram - failed to insert program")
 		return nil, err
@@ -157,63 +242,167 @@ func (r *masterRepo) CreateProgram(ctx context.Context, req *entity.CreateProgra
 	return resp, nil
 }
 
+// UpdateProgram records a price that differs from the one in effect in
+// req.EffectiveFrom as a price change from that billing period on, a change
+// already recorded for the period is replaced. The price on programs follows
+// the changes that apply now.
 func (r *masterRepo) UpdateProgram(ctx context.Context, req *entity.UpdateProgramReq) (*entity.UpdateProgramResp, error) {
 	var (
-		resp    = new(entity.UpdateProgramResp)
-		isExist bool
+		resp     = new(entity.UpdateProgramResp)
+		inEffect entity.ProgramFees
 	)
 
-	queryExist := `
-		SELECT EXISTS (
-			SELECT 1
-			FROM programs
-			WHERE id = ?
-			AND deleted_at IS NULL
-		)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateProgram - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the program is locked so concurrent updates compare against each other
+	query := `
+		SELECT
+			` + programprice.PriceSQL + ` AS price,
+			` + programprice.CommissionFeeSQL + ` AS commission_fee,
+			` + programprice.LecturerFeeSQL + ` AS lecturer_fee
+		FROM
+			programs p
+		` + programprice.JoinSQL + `
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NULL
+		FOR UPDATE OF p
 	`
 
-	if err := r.db.GetContext(ctx, &isExist, r.db.Rebind(queryExist), req.Id); err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::UpdateProgram - failed to check program exist")
+	err = tx.GetContext(ctx, &inEffect, tx.Rebind(query), req.EffectiveFrom, req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::UpdateProgram - program not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Program tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateProgram - failed to fetch price")
 		return nil, err
 	}
 
-	if !isExist {
-		log.Warn().Any("req", req).Msg("repo::UpdateProgram - program not found")
-		return nil, errmsg.NewCustomErrors(404).SetMessage("Program tidak ditemukan")
+	programTrail, err := audit.Track(ctx, tx, audit.Program, req.Id)
+	if err != nil {
+		return nil, err
 	}
 
-	query := `
+	query = `
 		UPDATE programs
 		SET
 			name = ?,
 			detail = ?,
-			price = ?,
-			days = ?,
-			lecturer_fee = ?,
-			commission_fee = ?
+			days = ?
 		WHERE
 			id = ?
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		req.Name,
-		req.Detail,
-		req.Price,
-		pq.Array(req.Days),
-		req.LecturerFee,
-		req.CommissionFee,
-		req.Id,
-	)
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Name, req.Detail, pq.Array(req.Days), req.Id)
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateProgram - failed to update program")
 		return nil, err
 	}
 
+	if req.ChangesPrice(inEffect) {
+		if err = r.saveProgramPrice(ctx, tx, req); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = programTrail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateProgram - failed to commit transaction")
+		return nil, err
+	}
+
 	resp.Id = req.Id
+	resp.EffectiveFrom = req.EffectiveFrom
 
 	return resp, nil
 }
 
+// saveProgramPrice upserts the price change of req.EffectiveFrom, and copies it
+// onto programs when it applies now
+func (r *masterRepo) saveProgramPrice(ctx context.Context, tx *sqlx.Tx, req *entity.UpdateProgramReq) error {
+	var priceId string
+	query := `
+		SELECT
+			id
+		FROM
+			program_prices
+		WHERE
+			program_id = ?
+			AND effective_from = TO_DATE(?, 'YYYY-MM')
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &priceId, tx.Rebind(query), req.Id, req.EffectiveFrom)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Any("req", req).Msg("repo::saveProgramPrice - failed to fetch price")
+		return err
+	}
+
+	if priceId == "" {
+		priceId = ulid.Make().String()
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.ProgramPrice, priceId)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		INSERT INTO program_prices (
+			id,
+			program_id,
+			user_id,
+			effective_from,
+			price,
+			commission_fee,
+			lecturer_fee
+		) VALUES (?, ?, ?, TO_DATE(?, 'YYYY-MM'), ?, ?, ?)
+		ON CONFLICT (program_id, effective_from) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			price = EXCLUDED.price,
+			commission_fee = EXCLUDED.commission_fee,
+			lecturer_fee = EXCLUDED.lecturer_fee,
+			updated_at = NOW()
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		priceId, req.Id, req.UserId, req.EffectiveFrom, req.Price, req.CommissionFee, req.LecturerFee,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::saveProgramPrice - failed to save price")
+		return err
+	}
+
+	if req.AppliesNow() {
+		query = `
+			UPDATE programs
+			SET
+				price = ?,
+				commission_fee = ?,
+				lecturer_fee = ?
+			WHERE
+				id = ?
+		`
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Price, req.CommissionFee, req.LecturerFee, req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::saveProgramPrice - failed to update program price")
+			return err
+		}
+	}
+
+	return trail.Save(ctx, tx)
+}
+
 func (r *masterRepo) DeleteProgram(ctx context.Context, req *entity.DeleteProgramReq) error {
 	query := `
 		UPDATE programs
@@ -224,7 +413,10 @@ func (r *masterRepo) DeleteProgram(ctx context.Context, req *entity.DeleteProgra
 			AND deleted_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id)
+	err := r.withAudit(ctx, audit.Program, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		return err
+	})
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DeleteProgram - failed to delete program")
 		return err
@@ -232,3 +424,41 @@ func (r *masterRepo) DeleteProgram(ctx context.Context, req *entity.DeleteProgra
 
 	return nil
 }
+
+// DeleteProgramPrice cancels a scheduled price of the program, the check and
+// the delete run in one transaction so a price that came into effect is kept
+func (r *masterRepo) DeleteProgramPrice(ctx context.Context, req *entity.DeleteProgramPriceReq) error {
+	query := `
+		DELETE FROM program_prices
+		WHERE
+			id = ?
+			AND program_id = ?
+			AND effective_from > TO_DATE(?, 'YYYY-MM')
+	`
+
+	err := r.withAudit(ctx, audit.ProgramPrice, req.Id, func(tx *sqlx.Tx) error {
+		result, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.ProgramId, entity.CurrentBillingPeriod())
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::DeleteProgramPrice - failed to delete price")
+			return err
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::DeleteProgramPrice - failed to get affected rows")
+			return err
+		}
+
+		if affected == 0 {
+			log.Warn().Any("req", req).Msg("repo::DeleteProgramPrice - scheduled price not found")
+			return errmsg.NewCustomErrors(404).SetMessage("Harga terjadwal tidak ditemukan atau sudah berlaku")
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -3,8 +3,11 @@ package repository
 import (
 	"codebase-app/internal/adapter"
 	"codebase-app/internal/module/master/ports"
+	"codebase-app/pkg/audit"
+	"context"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
 )
 
 var _ ports.MasterRepository = &masterRepo{}
@@ -18,3 +21,34 @@ func NewMasterRepository() *masterRepo {
 		db: adapter.Adapters.Postgres,
 	}
 }
+
+// withAudit runs fn in a transaction and records what it changed in the row of
+// entity with the given id, so a change is never saved without its audit log
+func (r *masterRepo) withAudit(ctx context.Context, entity audit.Entity, id string, fn func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("entity", entity.Name).Str("id", id).Msg("repo::withAudit - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, entity, id)
+	if err != nil {
+		return err
+	}
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Str("entity", entity.Name).Str("id", id).Msg("repo::withAudit - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
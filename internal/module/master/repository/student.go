@@ -2,6 +2,7 @@ package repository
 
 import (
 	"codebase-app/internal/module/master/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
@@ -141,8 +142,11 @@ func (r *masterRepo) CreateStudent(ctx context.Context, req *entity.CreateStuden
 		resp = new(entity.CreateStudentResp)
 	)
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		Id, req.Identifier, req.Name, req.RegisteredAt); err != nil {
+	err := r.withAudit(ctx, audit.Student, Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), Id, req.Identifier, req.Name, req.RegisteredAt)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::CreateStudent - failed to create student")
 		return nil, err
 	}
@@ -203,8 +207,11 @@ func (r *masterRepo) UpdateStudent(ctx context.Context, req *entity.UpdateStuden
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query),
-		req.Identifier, req.Name, req.RegisteredAt, req.IsActive, req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Student, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Identifier, req.Name, req.RegisteredAt, req.IsActive, req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateStudent - failed to update student")
 		return err
 	}
@@ -222,7 +229,11 @@ func (r *masterRepo) DeleteStudent(ctx context.Context, req *entity.DeleteStuden
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id); err != nil {
+	err := r.withAudit(ctx, audit.Student, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DeleteStudent - failed to delete student")
 		return err
 	}
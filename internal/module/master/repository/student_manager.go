@@ -2,10 +2,12 @@ package repository
 
 import (
 	"codebase-app/internal/module/master/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
 )
@@ -62,7 +64,11 @@ func (r *masterRepo) CreateStudentManager(ctx context.Context, req *entity.Creat
 		resp = new(entity.CreateStudentManagerResp)
 	)
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), Id, req.Name); err != nil {
+	err := r.withAudit(ctx, audit.StudentManager, Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), Id, req.Name)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::CreateStudentManager - failed to create student manager")
 		return nil, err
 	}
@@ -114,7 +120,11 @@ func (r *masterRepo) UpdateStudentManager(ctx context.Context, req *entity.Updat
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Name, req.Id); err != nil {
+	err := r.withAudit(ctx, audit.StudentManager, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Name, req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateStudentManager - failed to update student manager")
 		return err
 	}
@@ -132,7 +142,11 @@ func (r *masterRepo) DeleteStudentManager(ctx context.Context, req *entity.Delet
 			AND deleted_at IS NULL
 	`
 
-	if _, err := r.db.ExecContext(ctx, r.db.Rebind(query), req.Id); err != nil {
+	err := r.withAudit(ctx, audit.StudentManager, req.Id, func(tx *sqlx.Tx) error {
+		_, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		return err
+	})
+	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DeleteStudentManager - failed to delete student manager")
 		return err
 	}
@@ -24,3 +24,7 @@ func (s *masterService) UpdateProgram(ctx context.Context, req *entity.UpdatePro
 func (s *masterService) DeleteProgram(ctx context.Context, req *entity.DeleteProgramReq) error {
 	return s.repo.DeleteProgram(ctx, req)
 }
+
+func (s *masterService) DeleteProgramPrice(ctx context.Context, req *entity.DeleteProgramPriceReq) error {
+	return s.repo.DeleteProgramPrice(ctx, req)
+}
@@ -0,0 +1,83 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"encoding/json"
+)
+
+const (
+	AccountingPeriodStatusOpen   = "open"
+	AccountingPeriodStatusClosed = "closed"
+)
+
+type GetAccountingPeriodsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Status string `query:"status" validate:"omitempty,oneof=open closed"`
+
+	types.MetaQuery
+}
+
+func (r *GetAccountingPeriodsReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetAccountingPeriodsResp struct {
+	Items []AccountingPeriod `json:"items"`
+	Meta  types.Meta         `json:"meta"`
+}
+
+// AccountingPeriod is a billing period that has been closed at least once,
+// registrations of a closed period can not be changed
+type AccountingPeriod struct {
+	Id             *string `json:"id" db:"id"`
+	Period         string  `json:"period" db:"period"`
+	Status         string  `json:"status" db:"status"`
+	ClosedAt       *string `json:"closed_at" db:"closed_at"`
+	ClosedBy       *string `json:"closed_by" db:"closed_by"`
+	ClosedByName   *string `json:"closed_by_name" db:"closed_by_name"`
+	CloseReason    *string `json:"close_reason" db:"close_reason"`
+	ReopenedAt     *string `json:"reopened_at" db:"reopened_at"`
+	ReopenedBy     *string `json:"reopened_by" db:"reopened_by"`
+	ReopenedByName *string `json:"reopened_by_name" db:"reopened_by_name"`
+	ReopenReason   *string `json:"reopen_reason" db:"reopen_reason"`
+}
+
+type GetAccountingPeriodReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Period string `params:"period" validate:"datetime=2006-01"`
+}
+
+type GetAccountingPeriodResp struct {
+	AccountingPeriod
+	// the GetSummaries response of the period at its last closing
+	Summary json.RawMessage `json:"summary" db:"summary"`
+}
+
+type CloseAccountingPeriodReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Period string `params:"period" validate:"datetime=2006-01"`
+	Reason string `json:"reason" validate:"required,max=255"`
+
+	// filled by the service, completes the summary taken while closing
+	Summarize func(*GetSummariesResp) `json:"-" validate:"-"`
+}
+
+// SummariesReq returns the summary request of the period snapshot
+func (r *CloseAccountingPeriodReq) SummariesReq() *GetSummariesReq {
+	return &GetSummariesReq{
+		UserId:            r.UserId,
+		BillingPeriodFrom: r.Period,
+		BillingPeriodTo:   r.Period,
+		Timezone:          "Asia/Makassar",
+	}
+}
+
+type ReopenAccountingPeriodReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Period string `params:"period" validate:"datetime=2006-01"`
+	Reason string `json:"reason" validate:"required,max=255"`
+}
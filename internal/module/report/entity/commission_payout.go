@@ -0,0 +1,174 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CommissionPayoutStatusPaid   = "paid"
+	CommissionPayoutStatusUnpaid = "unpaid"
+)
+
+type GetCommissionStatementsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod    string `query:"billing_period" validate:"required,datetime=2006-01"`
+	StudentManagerId string `query:"student_manager_id" validate:"omitempty,ulid"`
+	MarketerId       string `query:"marketer_id" validate:"omitempty,ulid"`
+	PayoutStatus     string `query:"payout_status" validate:"omitempty,oneof=paid unpaid"`
+}
+
+type GetCommissionStatementsResp struct {
+	BillingPeriod string `json:"billing_period"`
+	CommissionStatementTotals
+	StudentManagers []StudentManagerCommissionStatement `json:"student_managers"`
+}
+
+// CommissionStatementTotals is the commission owed to marketers split by
+// whether it is already in an active payout batch.
+type CommissionStatementTotals struct {
+	TotalCommissionFee decimal.Decimal `json:"total_commission_fee"`
+	TotalGiftsFee      decimal.Decimal `json:"total_gifts_fee"`
+	TotalAmount        decimal.Decimal `json:"total_amount"`
+	TotalPaid          decimal.Decimal `json:"total_paid"`
+	TotalUnpaid        decimal.Decimal `json:"total_unpaid"`
+}
+
+func (t *CommissionStatementTotals) Add(item CommissionStatementItem) {
+	t.TotalCommissionFee = t.TotalCommissionFee.Add(item.MarketerCommissionFee)
+	t.TotalGiftsFee = t.TotalGiftsFee.Add(item.MarketerGiftsFee)
+	t.TotalAmount = t.TotalAmount.Add(item.Amount)
+
+	if item.PayoutStatus == CommissionPayoutStatusPaid {
+		t.TotalPaid = t.TotalPaid.Add(item.Amount)
+	} else {
+		t.TotalUnpaid = t.TotalUnpaid.Add(item.Amount)
+	}
+}
+
+type StudentManagerCommissionStatement struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	CommissionStatementTotals
+	Marketers []MarketerCommissionStatement `json:"marketers"`
+}
+
+type MarketerCommissionStatement struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	CommissionStatementTotals
+	Items []CommissionStatementItem `json:"items"`
+}
+
+type CommissionStatementItem struct {
+	RegistrationId        string          `json:"registration_id" db:"registration_id"`
+	StudentId             string          `json:"student_id" db:"student_id"`
+	StudentName           string          `json:"student_name" db:"student_name"`
+	ProgramName           string          `json:"program_name" db:"program_name"`
+	PaidAt                string          `json:"paid_at" db:"paid_at"`
+	MarketerCommissionFee decimal.Decimal `json:"marketer_commission_fee" db:"marketer_commission_fee"`
+	MarketerGiftsFee      decimal.Decimal `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
+	Amount                decimal.Decimal `json:"amount" db:"amount"`
+	PayoutStatus          string          `json:"payout_status" db:"payout_status"`
+	PayoutBatchId         *string         `json:"payout_batch_id" db:"payout_batch_id"`
+	PayoutPaidAt          *string         `json:"payout_paid_at" db:"payout_paid_at"`
+}
+
+// CommissionStatementRow is one registration of a statement with the marketer
+// and student manager it is grouped under.
+type CommissionStatementRow struct {
+	StudentManagerId   string `db:"student_manager_id"`
+	StudentManagerName string `db:"student_manager_name"`
+	MarketerId         string `db:"marketer_id"`
+	MarketerName       string `db:"marketer_name"`
+	CommissionStatementItem
+}
+
+type CreateCommissionPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string   `json:"billing_period" validate:"required,datetime=2006-01"`
+	MarketerIds   []string `json:"marketer_ids" validate:"required,min=1,dive,ulid"`
+	PaidAt        string   `json:"paid_at" validate:"required,datetime=2006-01-02"`
+	Reference     string   `json:"reference" validate:"required,max=255"`
+	Notes         *string  `json:"notes" validate:"omitempty,max=255"`
+	Timezone      string   `json:"timezone" validate:"required,timezone"`
+}
+
+func (r *CreateCommissionPayoutBatchReq) SetDefault() {
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+}
+
+type CreateCommissionPayoutBatchResp struct {
+	Id          string          `json:"id"`
+	TotalItems  int             `json:"total_items"`
+	TotalAmount decimal.Decimal `json:"total_amount"`
+}
+
+type GetCommissionPayoutBatchesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string `query:"billing_period" validate:"omitempty,datetime=2006-01"`
+
+	types.MetaQuery
+}
+
+func (r *GetCommissionPayoutBatchesReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetCommissionPayoutBatchesResp struct {
+	Items []CommissionPayoutBatch `json:"items"`
+	Meta  types.Meta              `json:"meta"`
+}
+
+type CommissionPayoutBatch struct {
+	Id             string          `json:"id" db:"id"`
+	UserId         string          `json:"user_id" db:"user_id"`
+	UserName       *string         `json:"user_name" db:"user_name"`
+	BillingPeriod  string          `json:"billing_period" db:"billing_period"`
+	PaidAt         string          `json:"paid_at" db:"paid_at"`
+	Reference      string          `json:"reference" db:"reference"`
+	Notes          *string         `json:"notes" db:"notes"`
+	TotalItems     int             `json:"total_items" db:"total_items"`
+	TotalAmount    decimal.Decimal `json:"total_amount" db:"total_amount"`
+	UnlockedAt     *string         `json:"unlocked_at" db:"unlocked_at"`
+	UnlockedBy     *string         `json:"unlocked_by" db:"unlocked_by"`
+	UnlockedByName *string         `json:"unlocked_by_name" db:"unlocked_by_name"`
+	UnlockedReason *string         `json:"unlocked_reason" db:"unlocked_reason"`
+	CreatedAt      string          `json:"created_at" db:"created_at"`
+}
+
+type GetCommissionPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetCommissionPayoutBatchResp struct {
+	CommissionPayoutBatch
+	Items []CommissionPayoutBatchItem `json:"items"`
+}
+
+type CommissionPayoutBatchItem struct {
+	Id                    string          `json:"id" db:"id"`
+	RegistrationId        string          `json:"registration_id" db:"registration_id"`
+	MarketerId            string          `json:"marketer_id" db:"marketer_id"`
+	MarketerName          string          `json:"marketer_name" db:"marketer_name"`
+	StudentName           string          `json:"student_name" db:"student_name"`
+	ProgramName           string          `json:"program_name" db:"program_name"`
+	MarketerCommissionFee decimal.Decimal `json:"marketer_commission_fee" db:"marketer_commission_fee"`
+	MarketerGiftsFee      decimal.Decimal `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
+	Amount                decimal.Decimal `json:"amount" db:"amount"`
+}
+
+type UnlockCommissionPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id     string `params:"id" validate:"ulid"`
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
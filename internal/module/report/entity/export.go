@@ -0,0 +1,19 @@
+package entity
+
+const (
+	ExportFormatJSON = "json"
+	ExportFormatXLSX = "xlsx"
+	ExportFormatCSV  = "csv"
+)
+
+// ExportFile is a rendered report sent as an attachment
+type ExportFile struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// IsExportFormat reports whether format asks for a file instead of json
+func IsExportFormat(format string) bool {
+	return format == ExportFormatXLSX || format == ExportFormatCSV
+}
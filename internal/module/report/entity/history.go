@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+	"encoding/json"
+)
+
+// GetHistoryReq lists the audit log of one registration or template, Entity is
+// set by the handler from the route
+type GetHistoryReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Entity string `validate:"required"`
+	Id     string `params:"id" validate:"ulid"`
+
+	types.MetaQuery
+}
+
+func (r *GetHistoryReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetHistoryResp struct {
+	Items []HistoryItem `json:"items"`
+	Meta  types.Meta    `json:"meta"`
+}
+
+// HistoryItem is one change, before and after only hold the changed columns of
+// an update
+type HistoryItem struct {
+	Id        string          `json:"id" db:"id"`
+	UserId    *string         `json:"user_id" db:"user_id"`
+	UserName  *string         `json:"user_name" db:"user_name"`
+	Ip        *string         `json:"ip" db:"ip"`
+	Action    string          `json:"action" db:"action"`
+	Before    json.RawMessage `json:"before" db:"before"`
+	After     json.RawMessage `json:"after" db:"after"`
+	CreatedAt string          `json:"created_at" db:"created_at"`
+}
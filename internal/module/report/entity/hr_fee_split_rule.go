@@ -0,0 +1,173 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	HRFeeSplitScopeGlobal   = "global"
+	HRFeeSplitScopeProgram  = "program"
+	HRFeeSplitScopeLecturer = "lecturer"
+
+	HRFeeSplitTypePercentage = "percentage"
+	HRFeeSplitTypeFixed      = "fixed"
+)
+
+type GetHRFeeSplitRulesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Scope      string `query:"scope" validate:"omitempty,oneof=global program lecturer"`
+	ProgramId  string `query:"program_id" validate:"omitempty,ulid"`
+	LecturerId string `query:"lecturer_id" validate:"omitempty,ulid"`
+
+	types.MetaQuery
+}
+
+func (r *GetHRFeeSplitRulesReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetHRFeeSplitRulesResp struct {
+	Items []HRFeeSplitRule `json:"items"`
+	Meta  types.Meta       `json:"meta"`
+}
+
+type HRFeeSplitRule struct {
+	Id           string          `json:"id" db:"id"`
+	UserId       string          `json:"user_id" db:"user_id"`
+	UserName     *string         `json:"user_name" db:"user_name"`
+	Scope        string          `json:"scope" db:"scope"`
+	ProgramId    *string         `json:"program_id" db:"program_id"`
+	ProgramName  *string         `json:"program_name" db:"program_name"`
+	LecturerId   *string         `json:"lecturer_id" db:"lecturer_id"`
+	LecturerName *string         `json:"lecturer_name" db:"lecturer_name"`
+	SplitType    string          `json:"split_type" db:"split_type"`
+	MentorValue  decimal.Decimal `json:"mentor_value" db:"mentor_value"`
+	Notes        *string         `json:"notes" db:"notes"`
+	CreatedAt    string          `json:"created_at" db:"created_at"`
+	UpdatedAt    string          `json:"updated_at" db:"updated_at"`
+}
+
+// HRFeeSplitRuleValue is the share of the hr fee given to the mentor, either a
+// percentage of the hr fee or a fixed amount, the rest goes to hr
+type HRFeeSplitRuleValue struct {
+	SplitType   string          `json:"split_type" validate:"required,oneof=percentage fixed"`
+	MentorValue decimal.Decimal `json:"mentor_value"`
+	Notes       *string         `json:"notes" validate:"omitempty,max=255"`
+}
+
+func (r *HRFeeSplitRuleValue) validate(err *errmsg.CustomError) {
+	if r.MentorValue.IsNegative() {
+		err.Add("mentor_value", "mentor value must be greater than or equal to 0")
+	}
+
+	if r.SplitType == HRFeeSplitTypePercentage && r.MentorValue.GreaterThan(decimal.NewFromInt(100)) {
+		err.Add("mentor_value", "percentage must be less than or equal to 100")
+	}
+}
+
+// MentorFee is the mentor share of hrFee rounded to whole rupiah, it never
+// exceeds the hr fee so the hr share is never negative
+func (r *HRFeeSplitRuleValue) MentorFee(hrFee decimal.Decimal) decimal.Decimal {
+	fee := r.MentorValue
+	if r.SplitType == HRFeeSplitTypePercentage {
+		fee = hrFee.Mul(r.MentorValue).Div(decimal.NewFromInt(100))
+	}
+
+	return decimal.Min(hrFee, fee.Round(0))
+}
+
+type CreateHRFeeSplitRuleReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Scope      string  `json:"scope" validate:"required,oneof=global program lecturer"`
+	ProgramId  *string `json:"program_id" validate:"omitempty,ulid"`
+	LecturerId *string `json:"lecturer_id" validate:"omitempty,ulid"`
+	HRFeeSplitRuleValue
+}
+
+func (r *CreateHRFeeSplitRuleReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	switch r.Scope {
+	case HRFeeSplitScopeGlobal:
+		if r.ProgramId != nil {
+			err.Add("program_id", "program id must be empty for a global rule")
+		}
+		if r.LecturerId != nil {
+			err.Add("lecturer_id", "lecturer id must be empty for a global rule")
+		}
+	case HRFeeSplitScopeProgram:
+		if r.ProgramId == nil {
+			err.Add("program_id", "program id is required for a program rule")
+		}
+		if r.LecturerId != nil {
+			err.Add("lecturer_id", "lecturer id must be empty for a program rule")
+		}
+	case HRFeeSplitScopeLecturer:
+		if r.LecturerId == nil {
+			err.Add("lecturer_id", "lecturer id is required for a lecturer rule")
+		}
+		if r.ProgramId != nil {
+			err.Add("program_id", "program id must be empty for a lecturer rule")
+		}
+	}
+
+	r.HRFeeSplitRuleValue.validate(err)
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+type CreateHRFeeSplitRuleResp struct {
+	Id string `json:"id"`
+}
+
+// UpdateHRFeeSplitRuleReq only changes the split, the scope of a rule is fixed
+type UpdateHRFeeSplitRuleReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+	HRFeeSplitRuleValue
+}
+
+func (r *UpdateHRFeeSplitRuleReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	r.HRFeeSplitRuleValue.validate(err)
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+type DeleteHRFeeSplitRuleReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type ApplyHRFeeSplitRulesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod   string `json:"billing_period" validate:"required,datetime=2006-01"`
+	ProgramId       string `json:"program_id" validate:"omitempty,ulid"`
+	LecturerId      string `json:"lecturer_id" validate:"omitempty,ulid"`
+	OverwriteManual bool   `json:"overwrite_manual"` // manual overrides are kept unless set
+}
+
+type ApplyHRFeeSplitRulesResp struct {
+	BillingPeriod string `json:"billing_period"`
+	TotalUpdated  int    `json:"total_updated"`
+	TotalManual   int    `json:"total_manual" db:"total_manual"`   // manual overrides that were kept
+	TotalLocked   int    `json:"total_locked" db:"total_locked"`   // registrations in a payout that can not be changed
+	TotalNoRule   int    `json:"total_no_rule" db:"total_no_rule"` // registrations no rule applies to
+}
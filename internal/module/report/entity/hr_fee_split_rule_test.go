@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHRFeeSplitRuleValueMentorFee(t *testing.T) {
+	tests := []struct {
+		name      string
+		splitType string
+		value     string
+		hrFee     string
+		want      string
+	}{
+		{"percentage", HRFeeSplitTypePercentage, "60", "250000", "150000"},
+		{"percentage rounds half up", HRFeeSplitTypePercentage, "50", "100001", "50001"},
+		{"percentage rounds down", HRFeeSplitTypePercentage, "33.33", "100001", "33330"},
+		{"fractional percentage", HRFeeSplitTypePercentage, "33.333", "100000", "33333"},
+		{"whole percentage", HRFeeSplitTypePercentage, "100", "175000", "175000"},
+		{"zero percentage", HRFeeSplitTypePercentage, "0", "175000", "0"},
+		{"fixed", HRFeeSplitTypeFixed, "100000", "250000", "100000"},
+		{"fixed rounds to rupiah", HRFeeSplitTypeFixed, "100000.5", "250000", "100001"},
+		{"fixed capped at hr fee", HRFeeSplitTypeFixed, "300000", "250000", "250000"},
+		{"no hr fee", HRFeeSplitTypeFixed, "100000", "0", "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := HRFeeSplitRuleValue{SplitType: tt.splitType, MentorValue: decimal.RequireFromString(tt.value)}
+
+			got := rule.MentorFee(decimal.RequireFromString(tt.hrFee))
+			assert.True(t, got.Equal(decimal.RequireFromString(tt.want)), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestCreateHRFeeSplitRuleReqValidate(t *testing.T) {
+	id := "01HSCOPE"
+
+	tests := []struct {
+		name       string
+		req        CreateHRFeeSplitRuleReq
+		wantFields []string
+	}{
+		{
+			name: "global",
+			req:  CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeGlobal, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypePercentage, MentorValue: decimal.NewFromInt(60)}},
+		},
+		{
+			name:       "global with program",
+			req:        CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeGlobal, ProgramId: &id, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypeFixed}},
+			wantFields: []string{"program_id"},
+		},
+		{
+			name:       "program without program",
+			req:        CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeProgram, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypeFixed}},
+			wantFields: []string{"program_id"},
+		},
+		{
+			name:       "lecturer with program",
+			req:        CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeLecturer, LecturerId: &id, ProgramId: &id, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypeFixed}},
+			wantFields: []string{"program_id"},
+		},
+		{
+			name:       "percentage above 100",
+			req:        CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeGlobal, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypePercentage, MentorValue: decimal.NewFromInt(101)}},
+			wantFields: []string{"mentor_value"},
+		},
+		{
+			name:       "negative fixed",
+			req:        CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeGlobal, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypeFixed, MentorValue: decimal.NewFromInt(-1)}},
+			wantFields: []string{"mentor_value"},
+		},
+		{
+			name: "fixed above 100",
+			req:  CreateHRFeeSplitRuleReq{Scope: HRFeeSplitScopeGlobal, HRFeeSplitRuleValue: HRFeeSplitRuleValue{SplitType: HRFeeSplitTypeFixed, MentorValue: decimal.NewFromInt(150000)}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			cerr, ok := err.(*errmsg.CustomError)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, cerr.Code)
+				assert.Len(t, cerr.Errors, len(tt.wantFields))
+				for _, field := range tt.wantFields {
+					assert.Contains(t, cerr.Errors, field)
+				}
+			}
+		})
+	}
+}
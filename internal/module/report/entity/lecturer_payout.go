@@ -0,0 +1,250 @@
+package entity
+
+import (
+	"codebase-app/pkg/types"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	LecturerPayoutStatusDraft    = "draft"
+	LecturerPayoutStatusApproved = "approved"
+	LecturerPayoutStatusPaid     = "paid"
+)
+
+type GenerateLecturerStatementsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string `json:"billing_period" validate:"required,datetime=2006-01"`
+	LecturerId    string `json:"lecturer_id" validate:"omitempty,ulid"`
+}
+
+type GenerateLecturerStatementsResp struct {
+	BillingPeriod string                     `json:"billing_period"`
+	Generated     int                        `json:"generated"`
+	Skipped       []LecturerStatementSkipped `json:"skipped"` // approved statements are kept as they are
+}
+
+type LecturerStatementSkipped struct {
+	StatementId  string `json:"statement_id" db:"id"`
+	LecturerId   string `json:"lecturer_id" db:"lecturer_id"`
+	LecturerName string `json:"lecturer_name" db:"lecturer_name"`
+	Status       string `json:"status" db:"status"`
+}
+
+// LecturerStatementSource is a registration with a distributed lecturer fee
+// that goes into the statement of its lecturer
+type LecturerStatementSource struct {
+	RegistrationId   string           `db:"registration_id"`
+	LecturerId       string           `db:"lecturer_id"`
+	HRFeeForLecturer decimal.Decimal  `db:"hr_fee_for_lecturer"`
+	UsedAmount       *decimal.Decimal `db:"used_amount"`
+	Notes            *string          `db:"notes"`
+}
+
+type GetLecturerStatementsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string `query:"billing_period" validate:"omitempty,datetime=2006-01"`
+	LecturerId    string `query:"lecturer_id" validate:"omitempty,ulid"`
+	Status        string `query:"status" validate:"omitempty,oneof=draft approved"`
+	Unbatched     bool   `query:"unbatched"` // only statements that are not in a payout batch yet
+
+	types.MetaQuery
+}
+
+func (r *GetLecturerStatementsReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetLecturerStatementsResp struct {
+	Items []LecturerStatement `json:"items"`
+	Meta  types.Meta          `json:"meta"`
+}
+
+type LecturerStatement struct {
+	Id             string          `json:"id" db:"id"`
+	LecturerId     string          `json:"lecturer_id" db:"lecturer_id"`
+	LecturerName   string          `json:"lecturer_name" db:"lecturer_name"`
+	BillingPeriod  string          `json:"billing_period" db:"billing_period"`
+	Status         string          `json:"status" db:"status"`
+	BatchId        *string         `json:"batch_id" db:"batch_id"`
+	BatchStatus    *string         `json:"batch_status" db:"batch_status"`
+	TotalItems     int             `json:"total_items" db:"total_items"`
+	TotalHRFee     decimal.Decimal `json:"total_hr_fee" db:"total_hr_fee"`
+	TotalAmount    decimal.Decimal `json:"total_amount" db:"total_amount"`
+	ApprovedAt     *string         `json:"approved_at" db:"approved_at"`
+	ApprovedBy     *string         `json:"approved_by" db:"approved_by"`
+	ApprovedByName *string         `json:"approved_by_name" db:"approved_by_name"`
+	CreatedAt      string          `json:"created_at" db:"created_at"`
+	UpdatedAt      string          `json:"updated_at" db:"updated_at"`
+}
+
+type GetLecturerStatementReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetLecturerStatementResp struct {
+	LecturerStatement
+	LecturerBankAccount
+	Items []LecturerStatementItem `json:"items"`
+}
+
+type LecturerBankAccount struct {
+	BankName          *string `json:"bank_name" db:"bank_name"`
+	BankAccountNumber *string `json:"bank_account_number" db:"bank_account_number"`
+	BankAccountName   *string `json:"bank_account_name" db:"bank_account_name"`
+}
+
+// LecturerStatementItem is one student and program of a statement, the same
+// breakdown as a month of the registration per lecturer report
+type LecturerStatementItem struct {
+	Id               string           `json:"id" db:"id"`
+	RegistrationId   string           `json:"registration_id" db:"registration_id"`
+	StudentId        string           `json:"student_id" db:"student_id"`
+	StudentName      string           `json:"student_name" db:"student_name"`
+	ProgramId        string           `json:"program_id" db:"program_id"`
+	ProgramName      string           `json:"program_name" db:"program_name"`
+	IsFL             bool             `json:"is_fl" db:"is_fl"`
+	IsNL             bool             `json:"is_nl" db:"is_nl"`
+	HRFeeForLecturer decimal.Decimal  `json:"hr_fee_for_lecturer" db:"hr_fee_for_lecturer"`
+	UsedAmount       *decimal.Decimal `json:"used_amount" db:"used_amount"`
+	Amount           decimal.Decimal  `json:"amount" db:"amount"` // the used amount is what is paid out to the lecturer
+	Notes            *string          `json:"notes" db:"notes"`
+}
+
+type ApproveLecturerStatementReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type ReopenLecturerStatementReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetLecturerPayslipReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type LecturerPayslipResp struct {
+	StatementId string `json:"statement_id"`
+	Filename    string `json:"filename"`
+	Link        string `json:"link"`
+	Expires     int64  `json:"expires"`
+}
+
+type CreateLecturerPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string   `json:"billing_period" validate:"required,datetime=2006-01"`
+	StatementIds  []string `json:"statement_ids" validate:"omitempty,unique,dive,ulid"` // every approved statement of the period when empty
+	Notes         *string  `json:"notes" validate:"omitempty,max=255"`
+}
+
+type CreateLecturerPayoutBatchResp struct {
+	Id              string          `json:"id"`
+	TotalStatements int             `json:"total_statements"`
+	TotalAmount     decimal.Decimal `json:"total_amount"`
+}
+
+type GetLecturerPayoutBatchesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string `query:"billing_period" validate:"omitempty,datetime=2006-01"`
+	Status        string `query:"status" validate:"omitempty,oneof=draft approved paid"`
+
+	types.MetaQuery
+}
+
+func (r *GetLecturerPayoutBatchesReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetLecturerPayoutBatchesResp struct {
+	Items []LecturerPayoutBatch `json:"items"`
+	Meta  types.Meta            `json:"meta"`
+}
+
+type LecturerPayoutBatch struct {
+	Id              string          `json:"id" db:"id"`
+	UserId          string          `json:"user_id" db:"user_id"`
+	UserName        *string         `json:"user_name" db:"user_name"`
+	BillingPeriod   string          `json:"billing_period" db:"billing_period"`
+	Status          string          `json:"status" db:"status"`
+	Notes           *string         `json:"notes" db:"notes"`
+	TotalStatements int             `json:"total_statements" db:"total_statements"`
+	TotalAmount     decimal.Decimal `json:"total_amount" db:"total_amount"`
+	ApprovedAt      *string         `json:"approved_at" db:"approved_at"`
+	ApprovedBy      *string         `json:"approved_by" db:"approved_by"`
+	ApprovedByName  *string         `json:"approved_by_name" db:"approved_by_name"`
+	PaidAt          *string         `json:"paid_at" db:"paid_at"`
+	PaidBy          *string         `json:"paid_by" db:"paid_by"`
+	PaidByName      *string         `json:"paid_by_name" db:"paid_by_name"`
+	Reference       *string         `json:"reference" db:"reference"`
+	CreatedAt       string          `json:"created_at" db:"created_at"`
+}
+
+type GetLecturerPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetLecturerPayoutBatchResp struct {
+	LecturerPayoutBatch
+	Statements []LecturerStatement `json:"statements"`
+}
+
+type ApproveLecturerPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type PayLecturerPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id        string `params:"id" validate:"ulid"`
+	PaidAt    string `json:"paid_at" validate:"required,datetime=2006-01-02"`
+	Reference string `json:"reference" validate:"required,max=255"`
+	Timezone  string `json:"timezone" validate:"required,timezone"`
+}
+
+func (r *PayLecturerPayoutBatchReq) SetDefault() {
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+}
+
+type DeleteLecturerPayoutBatchReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetLecturerPayoutBankFileReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+// LecturerPayoutTransfer is one bank transfer line of a payout batch
+type LecturerPayoutTransfer struct {
+	StatementId  string          `db:"statement_id"`
+	LecturerId   string          `db:"lecturer_id"`
+	LecturerName string          `db:"lecturer_name"`
+	TotalAmount  decimal.Decimal `db:"total_amount"`
+	LecturerBankAccount
+}
+
+type LecturerPayoutBankFile struct {
+	Batch     LecturerPayoutBatch
+	Transfers []LecturerPayoutTransfer
+}
@@ -0,0 +1,69 @@
+package entity
+
+import "codebase-app/pkg/errmsg"
+
+// a registration only counts in the reports once it is approved, an edit sends
+// it back to draft so the change is reviewed again
+const (
+	RegistrationStatusDraft     = "draft"
+	RegistrationStatusSubmitted = "submitted"
+	RegistrationStatusApproved  = "approved"
+	RegistrationStatusRejected  = "rejected"
+)
+
+// RegistrationApproval is the approval state of a registration
+type RegistrationApproval struct {
+	Status      string  `db:"approval_status"`
+	UserId      string  `db:"user_id"`    // the user who created the registration
+	UpdatedBy   *string `db:"updated_by"` // the user who last edited the registration
+	SubmittedBy *string `db:"submitted_by"`
+}
+
+// CanSubmit reports whether the registration may be submitted for approval,
+// a rejected registration is submitted again after it is fixed
+func (a *RegistrationApproval) CanSubmit() bool {
+	return a.Status == RegistrationStatusDraft || a.Status == RegistrationStatusRejected
+}
+
+// CanReview reports whether the registration awaits a review
+func (a *RegistrationApproval) CanReview() bool {
+	return a.Status == RegistrationStatusSubmitted
+}
+
+// IsMaker reports whether the user created, last edited or submitted the
+// registration, a maker can not review it
+func (a *RegistrationApproval) IsMaker(userId string) bool {
+	return a.UserId == userId ||
+		(a.UpdatedBy != nil && *a.UpdatedBy == userId) ||
+		(a.SubmittedBy != nil && *a.SubmittedBy == userId)
+}
+
+type SubmitRegistrationReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+// ReviewRegistrationReq approves or rejects a submitted registration, Approve
+// is set by the handler from the route
+type ReviewRegistrationReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id      string  `params:"id" validate:"ulid"`
+	Approve bool    `json:"-"`
+	Comment *string `json:"comment" validate:"omitempty,max=255"`
+}
+
+func (r *ReviewRegistrationReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	if !r.Approve && (r.Comment == nil || *r.Comment == "") {
+		err.Add("comment", "alasan penolakan harus diisi")
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistrationApprovalTransitions(t *testing.T) {
+	tests := []struct {
+		status     string
+		wantSubmit bool
+		wantReview bool
+	}{
+		{RegistrationStatusDraft, true, false},
+		{RegistrationStatusSubmitted, false, true},
+		{RegistrationStatusApproved, false, false},
+		{RegistrationStatusRejected, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			approval := &RegistrationApproval{Status: tt.status}
+
+			assert.Equal(t, tt.wantSubmit, approval.CanSubmit())
+			assert.Equal(t, tt.wantReview, approval.CanReview())
+		})
+	}
+}
+
+func TestRegistrationApprovalIsMaker(t *testing.T) {
+	editor := "01HEDITOR"
+	submitter := "01HSUBMITTER"
+	approval := &RegistrationApproval{UserId: "01HCREATOR", UpdatedBy: &editor, SubmittedBy: &submitter}
+
+	assert.True(t, approval.IsMaker("01HCREATOR"))
+	assert.True(t, approval.IsMaker("01HEDITOR"))
+	assert.True(t, approval.IsMaker("01HSUBMITTER"))
+	assert.False(t, approval.IsMaker("01HREVIEWER"))
+
+	// a registration created before approvals has no submitter
+	assert.True(t, (&RegistrationApproval{UserId: "01HCREATOR"}).IsMaker("01HCREATOR"))
+	assert.False(t, (&RegistrationApproval{UserId: "01HCREATOR"}).IsMaker("01HREVIEWER"))
+}
+
+func TestTemplateFeeChangeApproval(t *testing.T) {
+	change := &TemplateFeeChangeApproval{
+		TemplateUserId: "01HCREATOR",
+		UserId:         "01HPROPOSER",
+		Status:         TemplateFeeChangeStatusPending,
+	}
+
+	assert.True(t, change.CanReview())
+	assert.True(t, change.IsMaker("01HCREATOR"))
+	assert.True(t, change.IsMaker("01HPROPOSER"))
+	assert.False(t, change.IsMaker("01HREVIEWER"))
+
+	for _, status := range []string{TemplateFeeChangeStatusApproved, TemplateFeeChangeStatusRejected} {
+		assert.False(t, (&TemplateFeeChangeApproval{Status: status}).CanReview(), status)
+	}
+}
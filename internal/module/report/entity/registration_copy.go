@@ -2,9 +2,21 @@ package entity
 
 type CopyRegistrationsReq struct {
 	UserId        string          `json:"user_id" validate:"required,ulid"`
+	Mode          string          `query:"mode" validate:"oneof=all partial"`
+	BillingPeriod string          `query:"billing_period" validate:"datetime=2006-01"`
 	Registrations []CopyRegisItem `validate:"required,dive"`
 }
 
+func (r *CopyRegistrationsReq) SetDefault() {
+	if r.Mode == "" {
+		r.Mode = RegistrationBatchModeAll
+	}
+
+	if r.BillingPeriod == "" {
+		r.BillingPeriod = CurrentBillingPeriod()
+	}
+}
+
 type CopyRegisItem struct {
 	RegisId string `json:"registration_id" validate:"required,ulid"`
 }
@@ -1,11 +1,79 @@
 package entity
 
+import "time"
+
+const (
+	RegistrationBatchModeAll     = "all"
+	RegistrationBatchModePartial = "partial"
+
+	RegistrationBatchStatusCreated          = "created"
+	RegistrationBatchStatusSkippedDuplicate = "skipped_duplicate"
+	RegistrationBatchStatusFailed           = "failed"
+)
+
 type CreateRegistrationsReq struct {
 	UserId        string             `json:"user_id" validate:"required,ulid"`
+	Mode          string             `query:"mode" validate:"oneof=all partial"`
+	BillingPeriod string             `query:"billing_period" validate:"datetime=2006-01"` // default for items without billing_period
 	Registrations []RegistrationItem `validate:"required,dive"`
 }
 
+func (r *CreateRegistrationsReq) SetDefault() {
+	if r.Mode == "" {
+		r.Mode = RegistrationBatchModeAll
+	}
+
+	if r.BillingPeriod == "" {
+		r.BillingPeriod = CurrentBillingPeriod()
+	}
+
+	for i := range r.Registrations {
+		if r.Registrations[i].BillingPeriod == "" {
+			r.Registrations[i].BillingPeriod = r.BillingPeriod
+		}
+	}
+}
+
 type RegistrationItem struct {
 	TemplateId          string `json:"template_id" validate:"required,ulid"`
 	IsFirstRegistration bool   `json:"is_first_registration"`
+	BillingPeriod       string `json:"billing_period" validate:"datetime=2006-01"`
+}
+
+// CurrentBillingPeriod returns this month (YYYY-MM) in Asia/Makassar
+func CurrentBillingPeriod() string {
+	return time.Now().In(time.FixedZone("Asia/Makassar", 8*3600)).Format("2006-01")
+}
+
+// RegistrationBatchResp is the per-item result of CreateRegistrations and
+// CopyRegistrations. In "all" mode every item is created or nothing is saved,
+// in "partial" mode each item is saved on its own.
+type RegistrationBatchResp struct {
+	Mode         string                  `json:"mode"`
+	TotalCreated int                     `json:"total_created"`
+	TotalSkipped int                     `json:"total_skipped"`
+	TotalFailed  int                     `json:"total_failed"`
+	Items        []RegistrationBatchItem `json:"items"`
+}
+
+type RegistrationBatchItem struct {
+	Index          int     `json:"index"`
+	TemplateId     string  `json:"template_id,omitempty"`
+	RegistrationId string  `json:"registration_id,omitempty"` // source registration when copying
+	Status         string  `json:"status"`
+	Id             *string `json:"id"` // created registration id
+	Message        *string `json:"message"`
+}
+
+func (r *RegistrationBatchResp) Add(item RegistrationBatchItem) {
+	switch item.Status {
+	case RegistrationBatchStatusCreated:
+		r.TotalCreated++
+	case RegistrationBatchStatusSkippedDuplicate:
+		r.TotalSkipped++
+	case RegistrationBatchStatusFailed:
+		r.TotalFailed++
+	}
+
+	r.Items = append(r.Items, item)
 }
@@ -0,0 +1,59 @@
+package entity
+
+import "codebase-app/pkg/types"
+
+type DeleteRegistrationReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id     string `params:"id" validate:"ulid"`
+	Reason string `json:"reason" validate:"required,min=3,max=255"`
+}
+
+type RestoreRegistrationReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetDeletedRegistrationsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Q string `query:"q" validate:"omitempty,min=3"` // search by student name or program name
+
+	SortType string `query:"sort_type" validate:"omitempty,oneof=asc desc"`
+
+	types.MetaQuery
+}
+
+func (r *GetDeletedRegistrationsReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+
+	if r.SortType == "" {
+		r.SortType = "desc"
+	}
+}
+
+type GetDeletedRegistrationsResp struct {
+	Items []DeletedRegisItem `json:"items"`
+	Meta  types.Meta         `json:"meta"`
+}
+
+type DeletedRegisItem struct {
+	Id            string  `json:"id" db:"id"`
+	ProgramId     string  `json:"program_id" db:"program_id"`
+	MarketerId    string  `json:"marketer_id" db:"marketer_id"`
+	LecturerId    *string `json:"lecturer_id" db:"lecturer_id"`
+	StudentId     string  `json:"student_id" db:"student_id"`
+	ProgramName   string  `json:"program_name" db:"program_name"`
+	LecturerName  *string `json:"lecturer_name" db:"lecturer_name"`
+	MarketerName  string  `json:"marketer_name" db:"marketer_name"`
+	StudentName   string  `json:"student_name" db:"student_name"`
+	ProgramFee    float64 `json:"program_fee" db:"program_fee"`
+	DeletedBy     *string `json:"deleted_by" db:"deleted_by"`
+	DeletedByName *string `json:"deleted_by_name" db:"deleted_by_name"`
+	DeletedReason *string `json:"deleted_reason" db:"deleted_reason"`
+	BillingPeriod string  `json:"billing_period" db:"billing_period"`
+	PaidAt        string  `json:"paid_at" db:"paid_at"`
+	CreatedAt     string  `json:"created_at" db:"created_at"`
+	DeletedAt     string  `json:"deleted_at" db:"deleted_at"`
+}
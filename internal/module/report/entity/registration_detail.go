@@ -30,13 +30,26 @@ type GetRegistrationResp struct {
 	HRFee                 float64       `json:"hr_fee" db:"hr_fee"`
 	HRFeeForMentor        *float64      `json:"hr_fee_for_mentor" db:"hr_fee_for_mentor"`
 	HRFeeForHR            *float64      `json:"hr_fee_for_hr" db:"hr_fee_for_hr"`
+	HRFeeSplitRuleId      *string       `json:"hr_fee_split_rule_id" db:"hr_fee_split_rule_id"`
+	HRFeeSplitManual      bool          `json:"hr_fee_split_manual" db:"hr_fee_split_manual"` // the split was typed in instead of following a rule
 	MarketerGiftsFee      float64       `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
 	ClosingFeeForOffice   *float64      `json:"closing_fee_for_office" db:"closing_fee_for_office"`
 	ClosingFeeForReward   *float64      `json:"closing_fee_for_reward" db:"closing_fee_for_reward"`
-	Profit                float64       `json:"profit" db:"profit"`
+	Profit                float64       `json:"profit" db:"-"`
 	Notes                 *string       `json:"notes" db:"notes"`
 	Students              []AddStudent  `json:"additional_students"`
 	Days                  pq.Int64Array `json:"days" db:"days"`
+	BillingPeriod         string        `json:"billing_period" db:"billing_period"`
+	TotalBill             float64       `json:"total_bill" db:"total_bill"`
+	TotalPaid             float64       `json:"total_paid" db:"total_paid"`
+	OutstandingBalance    float64       `json:"outstanding_balance" db:"outstanding_balance"`
+	PaymentStatus         string        `json:"payment_status" db:"payment_status"`
+	ApprovalStatus        string        `json:"approval_status" db:"approval_status"`
+	SubmittedAt           *string       `json:"submitted_at" db:"submitted_at"`
+	SubmittedBy           *string       `json:"submitted_by" db:"submitted_by"`
+	ReviewedAt            *string       `json:"reviewed_at" db:"reviewed_at"`
+	ReviewedBy            *string       `json:"reviewed_by" db:"reviewed_by"`
+	ReviewComment         *string       `json:"review_comment" db:"review_comment"`
 	CreatedAt             string        `json:"created_at" db:"created_at"`
 	UpdatedAt             string        `json:"updated_at" db:"updated_at"`
 }
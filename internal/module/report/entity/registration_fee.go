@@ -0,0 +1,154 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// RegistrationFees are the fee fields of a registration, empty nullable fees are zero
+type RegistrationFees struct {
+	ProgramFee            decimal.Decimal `db:"program_fee"`
+	AdministrationFee     decimal.Decimal `db:"administration_fee"`
+	FLFee                 decimal.Decimal `db:"foreign_learning_fee"`
+	NLFee                 decimal.Decimal `db:"night_learning_fee"`
+	OverpaymentFee        decimal.Decimal `db:"overpayment_fee"`
+	MarketerCommissionFee decimal.Decimal `db:"marketer_commission_fee"`
+	MarketerGiftsFee      decimal.Decimal `db:"marketer_gifts_fee"`
+	HRFee                 decimal.Decimal `db:"hr_fee"`
+	ClosingFeeForOffice   decimal.Decimal `db:"closing_fee_for_office"`
+	ClosingFeeForReward   decimal.Decimal `db:"closing_fee_for_reward"`
+}
+
+// billedFees are the fees billed to the student by their column, their sum is
+// the total bill of a registration and its gross revenue
+var billedFees = []struct {
+	column string
+	value  func(f RegistrationFees) decimal.Decimal
+}{
+	{"program_fee", func(f RegistrationFees) decimal.Decimal { return f.ProgramFee }},
+	{"administration_fee", func(f RegistrationFees) decimal.Decimal { return f.AdministrationFee }},
+	{"foreign_learning_fee", func(f RegistrationFees) decimal.Decimal { return f.FLFee }},
+	{"night_learning_fee", func(f RegistrationFees) decimal.Decimal { return f.NLFee }},
+	{"overpayment_fee", func(f RegistrationFees) decimal.Decimal { return f.OverpaymentFee }},
+}
+
+// BilledFeeColumns are the program_registrations columns of the billed fees,
+// queries that need the total bill sum them
+func BilledFeeColumns() []string {
+	columns := make([]string, 0, len(billedFees))
+	for _, fee := range billedFees {
+		columns = append(columns, fee.column)
+	}
+
+	return columns
+}
+
+// TotalBill sums the billed fees
+func (f RegistrationFees) TotalBill() decimal.Decimal {
+	total := decimal.Zero
+	for _, fee := range billedFees {
+		total = total.Add(fee.value(f))
+	}
+
+	return total
+}
+
+// FeeBreakdown is the result of the fee calculator
+type FeeBreakdown struct {
+	Gross     decimal.Decimal
+	TotalCost decimal.Decimal
+	Profit    decimal.Decimal
+}
+
+func (i *RegisItem) Fees() RegistrationFees {
+	return RegistrationFees{
+		ProgramFee:            decimal.NewFromFloat(i.ProgramFee),
+		AdministrationFee:     decimalFromFloatPtr(i.AdministrationFee),
+		FLFee:                 decimalFromFloatPtr(i.FLFee),
+		NLFee:                 decimalFromFloatPtr(i.NLFee),
+		OverpaymentFee:        decimalFromFloatPtr(i.OverpaymentFee),
+		MarketerCommissionFee: decimal.NewFromFloat(i.MarketerCommissionFee),
+		MarketerGiftsFee:      decimal.NewFromFloat(i.MarketerGiftsFee),
+		HRFee:                 decimal.NewFromFloat(i.HRFee),
+		ClosingFeeForOffice:   decimalFromFloatPtr(i.ClosingFeeForOffice),
+		ClosingFeeForReward:   decimalFromFloatPtr(i.ClosingFeeForReward),
+	}
+}
+
+func (r *GetRegistrationResp) Fees() RegistrationFees {
+	return RegistrationFees{
+		ProgramFee:            decimal.NewFromFloat(r.ProgramFee),
+		AdministrationFee:     decimalFromFloatPtr(r.AdministrationFee),
+		FLFee:                 decimalFromFloatPtr(r.FLFee),
+		NLFee:                 decimalFromFloatPtr(r.NLFee),
+		OverpaymentFee:        decimalFromFloatPtr(r.OverpaymentFee),
+		MarketerCommissionFee: decimal.NewFromFloat(r.MarketerCommissionFee),
+		MarketerGiftsFee:      decimal.NewFromFloat(r.MarketerGiftsFee),
+		HRFee:                 decimal.NewFromFloat(r.HRFee),
+		ClosingFeeForOffice:   decimalFromFloatPtr(r.ClosingFeeForOffice),
+		ClosingFeeForReward:   decimalFromFloatPtr(r.ClosingFeeForReward),
+	}
+}
+
+func (t *SummaryTotals) Fees() RegistrationFees {
+	return RegistrationFees{
+		ProgramFee:            t.TotalProgramFee,
+		AdministrationFee:     t.TotalAdministrationFee,
+		FLFee:                 t.TotalFLFee,
+		NLFee:                 t.TotalNLFee,
+		OverpaymentFee:        t.TotalOverpaymentFee,
+		MarketerCommissionFee: t.TotalMarketerCommission,
+		MarketerGiftsFee:      t.TotalMarketerGifts,
+		HRFee:                 t.TotalHrFee,
+		ClosingFeeForOffice:   t.TotalClosingFeeForOffice,
+		ClosingFeeForReward:   t.TotalClosingFeeForReward,
+	}
+}
+
+func (b *TimeseriesBucket) Fees() RegistrationFees {
+	return RegistrationFees{
+		ProgramFee:            b.ProgramFee,
+		AdministrationFee:     b.AdministrationFee,
+		FLFee:                 b.FLFee,
+		NLFee:                 b.NLFee,
+		OverpaymentFee:        b.OverpaymentFee,
+		MarketerCommissionFee: b.MarketerCommission,
+		MarketerGiftsFee:      b.MarketerGifts,
+		HRFee:                 b.HRFee,
+		ClosingFeeForOffice:   b.ClosingFeeForOffice,
+		ClosingFeeForReward:   b.ClosingFeeForReward,
+	}
+}
+
+func decimalFromFloatPtr(v *float64) decimal.Decimal {
+	if v == nil {
+		return decimal.Zero
+	}
+
+	return decimal.NewFromFloat(*v)
+}
+
+type RecomputeFeesReq struct {
+	BillingPeriod string `validate:"omitempty,datetime=2006-01"`
+}
+
+// RegistrationFeeSnapshot holds the fee fields of a registration together with the
+// values that are stored from them elsewhere
+type RegistrationFeeSnapshot struct {
+	Id            string `db:"id"`
+	BillingPeriod string `db:"billing_period"`
+	RegistrationFees
+	HRFeeForMentor *decimal.Decimal `db:"hr_fee_for_mentor"`
+	HRFeeForHR     *decimal.Decimal `db:"hr_fee_for_hr"`
+}
+
+type RecomputeFeesResp struct {
+	BillingPeriod string        `json:"billing_period,omitempty"`
+	Checked       int           `json:"checked"`
+	Mismatches    []FeeMismatch `json:"mismatches"`
+}
+
+type FeeMismatch struct {
+	RegistrationId string          `json:"registration_id"`
+	BillingPeriod  string          `json:"billing_period"`
+	Field          string          `json:"field"`
+	Stored         decimal.Decimal `json:"stored"`
+	Expected       decimal.Decimal `json:"expected"`
+}
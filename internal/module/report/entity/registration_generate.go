@@ -0,0 +1,31 @@
+package entity
+
+type GenerateRegistrationsReq struct {
+	UserId        string `json:"user_id" validate:"required,ulid"`
+	BillingPeriod string `json:"billing_period" validate:"datetime=2006-01"`
+}
+
+func (r *GenerateRegistrationsReq) SetDefault() {
+	if r.BillingPeriod == "" {
+		r.BillingPeriod = CurrentBillingPeriod()
+	}
+}
+
+type GenerateRegistrationsResp struct {
+	Period     string `json:"period"`
+	StartedAt  string `json:"started_at"`
+	FinishedAt string `json:"finished_at"`
+
+	Created []GeneratedRegistration `json:"created"`
+	Skipped []GeneratedRegistration `json:"skipped"`
+	Failed  []GeneratedRegistration `json:"failed"`
+}
+
+type GeneratedRegistration struct {
+	TemplateId          string  `json:"template_id" db:"template_id"`
+	StudentName         string  `json:"student_name" db:"student_name"`
+	ProgramName         string  `json:"program_name" db:"program_name"`
+	IsFirstRegistration bool    `json:"is_first_registration" db:"is_first_registration"`
+	RegistrationId      *string `json:"registration_id,omitempty"`
+	Reason              *string `json:"reason,omitempty"`
+}
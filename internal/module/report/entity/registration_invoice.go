@@ -0,0 +1,72 @@
+package entity
+
+import (
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+type GetInvoiceReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string `params:"id" validate:"ulid"`
+}
+
+type GenerateInvoicesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	BillingPeriod string `json:"billing_period" validate:"required,datetime=2006-01"`
+}
+
+type InvoiceResp struct {
+	RegistrationId string `json:"registration_id"`
+	InvoiceNumber  string `json:"invoice_number"`
+	Filename       string `json:"filename"`
+	Link           string `json:"link"`
+	Expires        int64  `json:"expires"`
+}
+
+type GenerateInvoicesResp struct {
+	BillingPeriod string                 `json:"billing_period"`
+	Combined      *InvoiceResp           `json:"combined"` // every invoice of the period in one file, nil when there is none
+	Items         []InvoiceResp          `json:"items"`
+	Failed        []GenerateInvoiceError `json:"failed"`
+}
+
+type GenerateInvoiceError struct {
+	RegistrationId string `json:"registration_id"`
+	Message        string `json:"message"`
+}
+
+// InvoiceData is everything printed on a registration invoice
+type InvoiceData struct {
+	RegistrationId     string          `db:"registration_id"`
+	BillingPeriod      string          `db:"billing_period"`
+	StudentIdentifier  string          `db:"student_identifier"`
+	StudentName        string          `db:"student_name"`
+	StudentManagerName string          `db:"student_manager_name"`
+	ProgramName        string          `db:"program_name"`
+	MarketerName       string          `db:"marketer_name"`
+	LecturerName       *string         `db:"lecturer_name"`
+	ProgramFee         decimal.Decimal `db:"program_fee"`
+	AdministrationFee  decimal.Decimal `db:"administration_fee"`
+	FLFee              decimal.Decimal `db:"foreign_learning_fee"`
+	NLFee              decimal.Decimal `db:"night_learning_fee"`
+	OverpaymentFee     decimal.Decimal `db:"overpayment_fee"`
+	TotalBill          decimal.Decimal `db:"total_bill"`
+	TotalPaid          decimal.Decimal `db:"total_paid"`
+	OutstandingBalance decimal.Decimal `db:"outstanding_balance"`
+	PaymentStatus      string          `db:"payment_status"`
+	Students           []AddStudent
+}
+
+// InvoiceNumber is derived from the billing period and registration id so
+// regenerating an invoice keeps the same number, e.g. INV/202610/01JAF3QZ.
+func (d *InvoiceData) InvoiceNumber() string {
+	return "INV/" + strings.ReplaceAll(d.BillingPeriod, "-", "") + "/" + d.RegistrationId[len(d.RegistrationId)-8:]
+}
+
+// InvoiceFilename is the file name under the private local storage
+func (d *InvoiceData) InvoiceFilename() string {
+	return "invoice_" + d.BillingPeriod + "_" + d.RegistrationId + ".pdf"
+}
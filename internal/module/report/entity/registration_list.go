@@ -13,14 +13,22 @@ type GetRegistrationsReq struct {
 	PaidAtTo   string `query:"paid_at_to" validate:"omitempty,datetime=2006-01-02"`
 	Timezone   string `query:"timezone" validate:"required,timezone"`
 
+	BillingPeriodFrom string `query:"billing_period_from" validate:"omitempty,datetime=2006-01"`
+	BillingPeriodTo   string `query:"billing_period_to" validate:"omitempty,datetime=2006-01"`
+
 	MarketerId string `query:"marketer_id" validate:"omitempty,ulid"`
 	LecturerId string `query:"lecturer_id" validate:"omitempty,ulid"`
 	StudentId  string `query:"student_id" validate:"omitempty,ulid"`
 	ProgramId  string `query:"program_id" validate:"omitempty,ulid"`
 
-	SortBy   string `query:"sort_by" validate:"omitempty,oneof=created_at updated_at paid_at student_name"`
+	PaymentStatus  string `query:"payment_status" validate:"omitempty,oneof=paid partially_paid unpaid"`
+	ApprovalStatus string `query:"approval_status" validate:"omitempty,oneof=draft submitted approved rejected"`
+
+	SortBy   string `query:"sort_by" validate:"omitempty,oneof=created_at updated_at paid_at billing_period student_name"`
 	SortType string `query:"sort_type" validate:"omitempty,oneof=asc desc"`
 
+	Format string `query:"format" validate:"omitempty,oneof=json xlsx csv"`
+
 	types.MetaQuery
 }
 
@@ -48,6 +56,11 @@ func (r *GetRegistrationsReq) Validate() error {
 		err.Add("paid_at_to", "batas atas tanggal pembayaran harus diisi")
 	}
 
+	if (r.BillingPeriodFrom != "" || r.BillingPeriodTo != "") && (r.BillingPeriodFrom == "" || r.BillingPeriodTo == "") {
+		err.Add("billing_period_from", "batas bawah periode tagihan harus diisi")
+		err.Add("billing_period_to", "batas atas periode tagihan harus diisi")
+	}
+
 	if err.HasErrors() {
 		return err
 	}
@@ -82,11 +95,19 @@ type RegisItem struct {
 	HRFee                 float64      `json:"hr_fee" db:"hr_fee"`
 	HRFeeForMentor        *float64     `json:"hr_fee_for_mentor" db:"hr_fee_for_mentor"`
 	HRFeeForHR            *float64     `json:"hr_fee_for_hr" db:"hr_fee_for_hr"`
+	HRFeeSplitRuleId      *string      `json:"hr_fee_split_rule_id" db:"hr_fee_split_rule_id"`
+	HRFeeSplitManual      bool         `json:"hr_fee_split_manual" db:"hr_fee_split_manual"` // the split was typed in instead of following a rule
 	MarketerGiftsFee      float64      `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
 	ClosingFeeForOffice   *float64     `json:"closing_fee_for_office" db:"closing_fee_for_office"`
 	ClosingFeeForReward   *float64     `json:"closing_fee_for_reward" db:"closing_fee_for_reward"`
-	Profit                float64      `json:"profit" db:"profit"`
+	Profit                float64      `json:"profit" db:"-"`
 	Notes                 *string      `json:"notes" db:"notes"`
+	BillingPeriod         string       `json:"billing_period" db:"billing_period"`
+	TotalBill             float64      `json:"total_bill" db:"total_bill"`
+	TotalPaid             float64      `json:"total_paid" db:"total_paid"`
+	OutstandingBalance    float64      `json:"outstanding_balance" db:"outstanding_balance"`
+	PaymentStatus         string       `json:"payment_status" db:"payment_status"`
+	ApprovalStatus        string       `json:"approval_status" db:"approval_status"`
 	PaidAt                string       `json:"paid_at" db:"paid_at"`
 	CreatedAt             string       `json:"created_at" db:"created_at"`
 	UpdatedAt             string       `json:"updated_at" db:"updated_at"`
@@ -12,7 +12,9 @@ type GetRegistrationListPerLecturerReq struct {
 	types.MetaQuery
 	Q    string `query:"q"`
 	Year int    `query:"year"`
-	Tz   string `query:"timezone"`
+	Tz   string `query:"timezone" validate:"timezone"` // the date of the last draw is reported in it
+
+	Format string `query:"format" validate:"omitempty,oneof=json xlsx csv"`
 }
 
 func (r *GetRegistrationListPerLecturerReq) SetDefault() {
@@ -23,8 +25,11 @@ func (r *GetRegistrationListPerLecturerReq) SetDefault() {
 	}
 
 	if r.Year < 1 {
-		year := time.Now().In(time.FixedZone(r.Tz, 0)).Year()
-		r.Year = year
+		now := time.Now()
+		if loc, err := time.LoadLocation(r.Tz); err == nil {
+			now = now.In(loc)
+		}
+		r.Year = now.Year()
 	}
 
 }
@@ -48,13 +53,15 @@ type RegistrationListPerLecturer struct {
 }
 
 type RegistrationListPerLecturerPerMonth struct {
-	RegistrationId *string          `json:"registration_id" db:"registration_id"`
-	Month          string           `json:"month" db:"month"` // indonesia month
-	MonthNum       int              `json:"month_num" db:"month_num"`
-	UsedAmount     *decimal.Decimal `json:"used_amount" db:"used_amount"`
-	HRFeeLecturer  *decimal.Decimal `json:"hr_fee_for_lecturer" db:"hr_fee_for_lecturer"`
-	IsUsed         *bool            `json:"is_used" db:"is_used"`
-	Notes          *string          `json:"notes" db:"notes"`
+	RegistrationId  *string          `json:"registration_id" db:"registration_id"`
+	Month           string           `json:"month" db:"month"` // indonesia month
+	MonthNum        int              `json:"month_num" db:"month_num"`
+	UsedAmount      *decimal.Decimal `json:"used_amount" db:"used_amount"` // net of reversals, from the usage ledger
+	RemainingAmount *decimal.Decimal `json:"remaining_amount" db:"remaining_amount"`
+	HRFeeLecturer   *decimal.Decimal `json:"hr_fee_for_lecturer" db:"hr_fee_for_lecturer"`
+	IsUsed          *bool            `json:"is_used" db:"is_used"`
+	Notes           *string          `json:"notes" db:"notes"` // notes of the last draw
+	UsedAt          *string          `json:"used_at" db:"used_at"`
 
 	ProgramId  string           `json:"program_id" db:"program_id"`
 	LecturerId *string          `json:"lecturer_id" db:"lecturer_id"`
@@ -0,0 +1,149 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"fmt"
+	"strings"
+)
+
+type CreateRegistrationMeetingReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	RegistrationId  string       `params:"id" validate:"ulid"`
+	MetOn           string       `json:"met_on" validate:"required,datetime=2006-01-02"`
+	DurationMinutes int          `json:"duration_minutes" validate:"required,min=1,max=1440"`
+	Attendees       []AddStudent `json:"attendees" validate:"required,dive"` // the main or additional students that attended
+	Notes           *string      `json:"notes" validate:"omitempty,max=255"`
+}
+
+func (r *CreateRegistrationMeetingReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	if len(r.Attendees) == 0 {
+		err.Add("attendees", "minimal satu siswa harus hadir")
+	}
+
+	for i, s := range r.Attendees {
+		if (s.StudentId == nil) == (s.Name == nil) {
+			err.Add(fmt.Sprintf("attendees[%d].student_id", i), "isi salah satu dari student_id atau name")
+			err.Add(fmt.Sprintf("attendees[%d].name", i), "isi salah satu dari student_id atau name")
+		}
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateBillingPeriod checks that the meeting took place in the billing
+// month of its registration, billingPeriod is formatted as 2006-01
+func (r *CreateRegistrationMeetingReq) ValidateBillingPeriod(billingPeriod string) error {
+	if !strings.HasPrefix(r.MetOn, billingPeriod+"-") {
+		return errmsg.NewCustomErrors(422).Add("met_on", "met_on harus di dalam periode tagihan registrasi "+billingPeriod)
+	}
+
+	return nil
+}
+
+type CreateRegistrationMeetingResp struct {
+	Id string `json:"id"`
+	RegistrationMeetingCount
+}
+
+type GetRegistrationMeetingsReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	RegistrationId string `params:"id" validate:"ulid"`
+}
+
+type GetRegistrationMeetingsResp struct {
+	RegistrationMeetingCount
+	Items []RegistrationMeeting `json:"items"`
+}
+
+type DeleteRegistrationMeetingReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	RegistrationId string `params:"id" validate:"ulid"`
+	Id             string `params:"meeting_id" validate:"ulid"`
+}
+
+// RegistrationMeetingCount compares the meetings planned from the days of a
+// registration in its billing month with the meetings recorded
+type RegistrationMeetingCount struct {
+	RegistrationId    string `json:"registration_id" db:"registration_id"`
+	ProgramMeetings   int    `json:"program_meetings" db:"program_meetings"`
+	MeetingsCompleted int    `json:"program_meetings_completed" db:"program_meetings_completed"`
+}
+
+type RegistrationMeeting struct {
+	Id              string       `json:"id" db:"id"`
+	LecturerId      *string      `json:"lecturer_id" db:"lecturer_id"`
+	LecturerName    *string      `json:"lecturer_name" db:"lecturer_name"`
+	UserId          string       `json:"user_id" db:"user_id"`
+	UserName        *string      `json:"user_name" db:"user_name"`
+	MetOn           string       `json:"met_on" db:"met_on"`
+	DurationMinutes int          `json:"duration_minutes" db:"duration_minutes"`
+	Notes           *string      `json:"notes" db:"notes"`
+	Attendees       []AddStudent `json:"attendees" db:"-"`
+	CreatedAt       string       `json:"created_at" db:"created_at"`
+}
+
+// GetAttendancesReq lists the attendances of a billing period, a user with the
+// lecturer role only sees their own registrations
+type GetAttendancesReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	BillingPeriod string `query:"billing_period" validate:"required,datetime=2006-01"`
+	LecturerId    string `query:"lecturer_id" validate:"omitempty,ulid"`
+}
+
+func (r *GetAttendancesReq) SetDefault() {
+	if r.BillingPeriod == "" {
+		r.BillingPeriod = CurrentBillingPeriod()
+	}
+}
+
+type GetAttendancesResp struct {
+	BillingPeriod string `json:"billing_period"`
+	AttendanceTotals
+	Lecturers []LecturerAttendance `json:"lecturers"`
+}
+
+type AttendanceTotals struct {
+	TotalPlanned   int `json:"total_planned"`
+	TotalDelivered int `json:"total_delivered"`
+	TotalMinutes   int `json:"total_minutes"`
+}
+
+func (t *AttendanceTotals) Add(item RegistrationAttendance) {
+	t.TotalPlanned += item.Planned
+	t.TotalDelivered += item.Delivered
+	t.TotalMinutes += item.TotalMinutes
+}
+
+type LecturerAttendance struct {
+	LecturerId   *string `json:"lecturer_id"`
+	LecturerName *string `json:"lecturer_name"`
+	AttendanceTotals
+	Registrations []RegistrationAttendance `json:"registrations"`
+}
+
+type RegistrationAttendance struct {
+	RegistrationId string  `json:"registration_id" db:"registration_id"`
+	LecturerId     *string `json:"-" db:"lecturer_id"`
+	LecturerName   *string `json:"-" db:"lecturer_name"`
+	StudentName    string  `json:"student_name" db:"student_name"`
+	ProgramName    string  `json:"program_name" db:"program_name"`
+	Planned        int     `json:"planned" db:"planned"`
+	Delivered      int     `json:"delivered" db:"delivered"`
+	TotalMinutes   int     `json:"total_minutes" db:"total_minutes"`
+	TotalAttendees int     `json:"total_attendees" db:"total_attendees"` // students present summed over the meetings
+	LastMetOn      *string `json:"last_met_on" db:"last_met_on"`
+}
@@ -0,0 +1,90 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestCreateRegistrationMeetingReqValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		attendees  []AddStudent
+		wantFields []string
+	}{
+		{
+			name:      "student id and name attendees",
+			attendees: []AddStudent{{StudentId: strPtr("01HSTUDENT")}, {Name: strPtr("Ani")}},
+		},
+		{
+			name:       "no attendee",
+			attendees:  []AddStudent{},
+			wantFields: []string{"attendees"},
+		},
+		{
+			name:       "both student id and name",
+			attendees:  []AddStudent{{StudentId: strPtr("01HSTUDENT"), Name: strPtr("Ani")}},
+			wantFields: []string{"attendees[0].student_id", "attendees[0].name"},
+		},
+		{
+			name:       "neither student id nor name",
+			attendees:  []AddStudent{{StudentId: strPtr("01HSTUDENT")}, {}},
+			wantFields: []string{"attendees[1].student_id", "attendees[1].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &CreateRegistrationMeetingReq{Attendees: tt.attendees}
+
+			err := req.Validate()
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			cerr, ok := err.(*errmsg.CustomError)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, cerr.Code)
+				for _, field := range tt.wantFields {
+					assert.Contains(t, cerr.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateRegistrationMeetingReqValidateBillingPeriod(t *testing.T) {
+	tests := []struct {
+		metOn   string
+		wantErr bool
+	}{
+		{"2026-10-01", false},
+		{"2026-10-31", false},
+		{"2026-09-30", true},
+		{"2026-11-01", true},
+		{"2025-10-15", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.metOn, func(t *testing.T) {
+			req := &CreateRegistrationMeetingReq{MetOn: tt.metOn}
+
+			err := req.ValidateBillingPeriod("2026-10")
+			if tt.wantErr {
+				cerr, ok := err.(*errmsg.CustomError)
+				if assert.True(t, ok) {
+					assert.Equal(t, 422, cerr.Code)
+					assert.Contains(t, cerr.Errors, "met_on")
+				}
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
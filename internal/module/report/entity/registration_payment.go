@@ -0,0 +1,72 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+const (
+	PaymentStatusPaid          = "paid"
+	PaymentStatusPartiallyPaid = "partially_paid"
+	PaymentStatusUnpaid        = "unpaid"
+)
+
+type CreateRegistrationPaymentReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string  `params:"id" validate:"ulid"`
+	Amount         float64 `json:"amount" validate:"required,gt=0"`
+	Method         string  `json:"method" validate:"required,oneof=transfer cash qris other"`
+	Reference      *string `json:"reference" validate:"omitempty,max=255"`
+	Notes          *string `json:"notes" validate:"omitempty,max=255"`
+	PaidAt         string  `json:"paid_at" validate:"required,datetime=2006-01-02"`
+	Timezone       string  `json:"timezone" validate:"required,timezone"`
+}
+
+func (r *CreateRegistrationPaymentReq) SetDefault() {
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+}
+
+type CreateRegistrationPaymentResp struct {
+	Id string `json:"id"`
+	RegistrationPaymentBalance
+}
+
+type GetRegistrationPaymentsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string `params:"id" validate:"ulid"`
+}
+
+type GetRegistrationPaymentsResp struct {
+	RegistrationPaymentBalance
+	Items []RegistrationPaymentItem `json:"items"`
+}
+
+type DeleteRegistrationPaymentReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string `params:"id" validate:"ulid"`
+	Id             string `params:"payment_id" validate:"ulid"`
+}
+
+// RegistrationPaymentBalance is the billed amount of a registration compared
+// to the payments recorded against it.
+type RegistrationPaymentBalance struct {
+	RegistrationId     string          `json:"registration_id" db:"registration_id"`
+	TotalBill          decimal.Decimal `json:"total_bill" db:"total_bill"`
+	TotalPaid          decimal.Decimal `json:"total_paid" db:"total_paid"`
+	OutstandingBalance decimal.Decimal `json:"outstanding_balance" db:"outstanding_balance"`
+	PaymentStatus      string          `json:"payment_status" db:"payment_status"`
+}
+
+type RegistrationPaymentItem struct {
+	Id        string  `json:"id" db:"id"`
+	UserId    *string `json:"user_id" db:"user_id"`
+	UserName  *string `json:"user_name" db:"user_name"`
+	Amount    float64 `json:"amount" db:"amount"`
+	Method    string  `json:"method" db:"method"`
+	Reference *string `json:"reference" db:"reference"`
+	Notes     *string `json:"notes" db:"notes"`
+	PaidAt    string  `json:"paid_at" db:"paid_at"`
+	CreatedAt string  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,98 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type IssueReceiptReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string  `params:"id" validate:"ulid"`
+	Reason         *string `json:"reason" validate:"omitempty,max=255"` // recorded when the receipt is reissued
+
+	NumberPrefix   string `json:"-"`
+	SequenceDigits int    `json:"-"`
+}
+
+type IssueReceiptResp struct {
+	RegistrationId string `json:"registration_id"`
+	ReceiptNumber  string `json:"receipt_number"`
+	IsReissue      bool   `json:"is_reissue"`
+	ReissueCount   int    `json:"reissue_count"`
+	Filename       string `json:"filename"`
+	Link           string `json:"link"`
+	Expires        int64  `json:"expires"`
+}
+
+type VerifyReceiptReq struct {
+	Number    string `query:"number" validate:"required,max=50"`
+	Signature string `query:"signature" validate:"required,hexadecimal,len=64"`
+}
+
+type VerifyReceiptResp struct {
+	Valid         bool            `json:"valid"`
+	ReceiptNumber string          `json:"receipt_number"`
+	Amount        decimal.Decimal `json:"amount"`
+	PaidAt        string          `json:"paid_at"`
+	StudentName   string          `json:"student_name"`
+	ProgramName   string          `json:"program_name"`
+	BillingPeriod string          `json:"billing_period"`
+	IssuedAt      time.Time       `json:"issued_at"`
+	ReissueCount  int             `json:"reissue_count"`
+}
+
+// ReceiptData is everything printed on a receipt (kwitansi)
+type ReceiptData struct {
+	Id                string          `db:"id"`
+	RegistrationId    string          `db:"registration_id"`
+	ReceiptNumber     string          `db:"receipt_number"`
+	Amount            decimal.Decimal `db:"amount"`
+	PaidAt            time.Time       `db:"paid_at"`
+	IssuedAt          time.Time       `db:"issued_at"`
+	IssuedByName      string          `db:"issued_by_name"`
+	ReissueCount      int             `db:"reissue_count"`
+	StudentName       string          `db:"student_name"`
+	StudentIdentifier string          `db:"student_identifier"`
+	ProgramName       string          `db:"program_name"`
+	BillingPeriod     string          `db:"billing_period"`
+	IsRevoked         bool            `db:"is_revoked"` // the registration is deleted or its payments no longer cover the amount
+	IsReissue         bool            `db:"-"`
+}
+
+// ReceiptError tells why a first receipt can not be issued for the balance, a
+// receipt needs the registration fully paid by at least one payment to date it
+func ReceiptError(balance *RegistrationPaymentBalance) *errmsg.CustomError {
+	if balance.PaymentStatus != PaymentStatusPaid {
+		return errmsg.NewCustomErrors(422).SetMessage("Registrasi belum lunas, kwitansi belum dapat dibuat")
+	}
+
+	if !balance.TotalPaid.IsPositive() {
+		return errmsg.NewCustomErrors(422).SetMessage("Registrasi belum memiliki pembayaran, kwitansi belum dapat dibuat")
+	}
+
+	return nil
+}
+
+// FormatReceiptNumber returns e.g. KW/2026/10/0001, seq restarts every year
+func FormatReceiptNumber(prefix string, digits int, issuedAt time.Time, seq int) string {
+	return fmt.Sprintf("%s/%s/%0*d", prefix, issuedAt.Format("2006/01"), digits, seq)
+}
+
+// PaidDate is the settlement date in Asia/Makassar
+func (d *ReceiptData) PaidDate() string {
+	return d.PaidAt.In(time.FixedZone("Asia/Makassar", 8*3600)).Format("2006-01-02")
+}
+
+// VerificationPayload is the signed content of the receipt QR code, changing
+// the number, amount or date of a receipt invalidates its signature.
+func (d *ReceiptData) VerificationPayload() string {
+	return d.ReceiptNumber + "|" + d.Amount.StringFixed(2) + "|" + d.PaidDate()
+}
+
+func (d *ReceiptData) Filename() string {
+	return "receipt_" + d.RegistrationId + ".pdf"
+}
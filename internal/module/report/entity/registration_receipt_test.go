@@ -0,0 +1,74 @@
+package entity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptError(t *testing.T) {
+	tests := []struct {
+		name    string
+		balance RegistrationPaymentBalance
+		wantErr bool
+	}{
+		{
+			name:    "fully paid",
+			balance: RegistrationPaymentBalance{TotalBill: decimal.NewFromInt(500000), TotalPaid: decimal.NewFromInt(500000), PaymentStatus: PaymentStatusPaid},
+		},
+		{
+			name:    "overpaid",
+			balance: RegistrationPaymentBalance{TotalBill: decimal.NewFromInt(500000), TotalPaid: decimal.NewFromInt(600000), PaymentStatus: PaymentStatusPaid},
+		},
+		{
+			name:    "partially paid",
+			balance: RegistrationPaymentBalance{TotalBill: decimal.NewFromInt(500000), TotalPaid: decimal.NewFromInt(100000), PaymentStatus: PaymentStatusPartiallyPaid},
+			wantErr: true,
+		},
+		{
+			name:    "unpaid",
+			balance: RegistrationPaymentBalance{TotalBill: decimal.NewFromInt(500000), PaymentStatus: PaymentStatusUnpaid},
+			wantErr: true,
+		},
+		{
+			name:    "nothing billed and no payment",
+			balance: RegistrationPaymentBalance{PaymentStatus: PaymentStatusPaid},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ReceiptError(&tt.balance)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, 422, err.Code)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestFormatReceiptNumber(t *testing.T) {
+	issuedAt := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, "KW/2026/10/0001", FormatReceiptNumber("KW", 4, issuedAt, 1))
+	assert.Equal(t, "KW/2026/10/0123", FormatReceiptNumber("KW", 4, issuedAt, 123))
+	assert.Equal(t, "KW/2026/10/12345", FormatReceiptNumber("KW", 4, issuedAt, 12345), "a sequence longer than digits is not cut")
+	assert.Equal(t, "INV/2026/10/007", FormatReceiptNumber("INV", 3, issuedAt, 7))
+}
+
+func TestReceiptDataVerificationPayload(t *testing.T) {
+	data := &ReceiptData{
+		ReceiptNumber: "KW/2026/10/0001",
+		Amount:        decimal.RequireFromString("500000.5"),
+		// the evening of the 17th in UTC is the 18th in Asia/Makassar
+		PaidAt: time.Date(2026, 10, 17, 17, 0, 0, 0, time.UTC),
+	}
+
+	assert.Equal(t, "2026-10-18", data.PaidDate())
+	assert.Equal(t, "KW/2026/10/0001|500000.50|2026-10-18", data.VerificationPayload())
+}
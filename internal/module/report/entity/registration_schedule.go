@@ -0,0 +1,148 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"strconv"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// ScheduledSession is one date of a registration expanded from its days over
+// its billing month
+type ScheduledSession struct {
+	Date           string  `json:"date" db:"session_date"`
+	Weekday        int     `json:"weekday" db:"weekday"` // ISO weekday, 1 is monday
+	RegistrationId string  `json:"registration_id" db:"registration_id"`
+	LecturerId     *string `json:"lecturer_id" db:"lecturer_id"`
+	LecturerName   *string `json:"lecturer_name" db:"lecturer_name"`
+	StudentName    string  `json:"student_name" db:"student_name"`
+	ProgramName    string  `json:"program_name" db:"program_name"`
+	Met            bool    `json:"met" db:"met"` // a meeting is recorded on the date
+}
+
+type GetRegistrationScheduleReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type GetRegistrationScheduleResp struct {
+	RegistrationId  string             `json:"registration_id" db:"registration_id"`
+	BillingPeriod   string             `json:"billing_period" db:"billing_period"`
+	Days            pq.Int64Array      `json:"days" db:"days"`
+	ProgramMeetings int                `json:"program_meetings" db:"program_meetings"`
+	Sessions        []ScheduledSession `json:"sessions" db:"-"`
+}
+
+type GetLecturerLoadsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	From        string `query:"from" validate:"required,datetime=2006-01-02"`
+	To          string `query:"to" validate:"required,datetime=2006-01-02"`
+	LecturerId  string `query:"lecturer_id" validate:"omitempty,ulid"`
+	MaxSessions int    `query:"max_sessions" validate:"min=1,max=20"` // sessions a lecturer can hold in a day before it is a conflict
+}
+
+func (r *GetLecturerLoadsReq) SetDefault() {
+	now := time.Now().In(time.FixedZone("Asia/Makassar", 8*3600))
+	firstDay := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	if r.From == "" {
+		r.From = firstDay.Format("2006-01-02")
+	}
+
+	if r.To == "" {
+		r.To = firstDay.AddDate(0, 1, -1).Format("2006-01-02")
+	}
+
+	if r.MaxSessions == 0 {
+		r.MaxSessions = 1
+	}
+}
+
+func (r *GetLecturerLoadsReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	from, errFrom := time.Parse("2006-01-02", r.From)
+	to, errTo := time.Parse("2006-01-02", r.To)
+	if errFrom == nil && errTo == nil {
+		if from.After(to) {
+			err.Add("from", "tanggal awal tidak boleh melebihi tanggal akhir")
+		}
+
+		if to.After(from.AddDate(0, 0, 92)) {
+			err.Add("to", "rentang tanggal maksimal 93 hari")
+		}
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+type GetLecturerLoadsResp struct {
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	MaxSessions    int               `json:"max_sessions"`
+	TotalConflicts int               `json:"total_conflicts"`
+	Items          []LecturerDayLoad `json:"items"`
+}
+
+// LecturerDayLoad is the sessions of one lecturer on one day, more sessions
+// than the lecturer can hold is a conflict
+type LecturerDayLoad struct {
+	Date         string             `json:"date"`
+	LecturerId   *string            `json:"lecturer_id"`
+	LecturerName *string            `json:"lecturer_name"`
+	Sessions     int                `json:"sessions"`
+	Conflict     bool               `json:"conflict"`
+	Items        []ScheduledSession `json:"items"`
+}
+
+// LecturerCalendarPayload is the signed content of the calendar feed link of a
+// lecturer, the link stays valid until its calendar version is reset or the
+// signing key changes
+func LecturerCalendarPayload(lecturerId string, version int) string {
+	return "lecturer-calendar|" + lecturerId + "|" + strconv.Itoa(version)
+}
+
+type GetLecturerCalendarLinkReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	LecturerId string `params:"lecturer_id" validate:"ulid"`
+}
+
+// ResetLecturerCalendarLinkReq revokes the calendar feed link of a lecturer
+// and returns a new one
+type ResetLecturerCalendarLinkReq struct {
+	UserId string `validate:"required,ulid"`
+	Role   string
+
+	LecturerId string `params:"lecturer_id" validate:"ulid"`
+}
+
+type GetLecturerCalendarLinkResp struct {
+	LecturerId string `json:"lecturer_id"`
+	Link       string `json:"link"`
+}
+
+type GetLecturerCalendarReq struct {
+	LecturerId string `params:"lecturer_id" validate:"ulid"`
+	Signature  string `query:"signature" validate:"required,hexadecimal,len=64"`
+
+	// sessions of billing periods from this month on are in the feed, filled by the service
+	FromPeriod string `validate:"-"`
+}
+
+// CalendarLecturer is the lecturer of a calendar feed
+type CalendarLecturer struct {
+	Id      string `db:"id"`
+	Name    string `db:"name"`
+	IsUser  bool   `db:"is_user"` // the lecturer is the requesting user
+	Version int    `db:"calendar_version"`
+}
@@ -2,6 +2,8 @@ package entity
 
 import (
 	"codebase-app/pkg/errmsg"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -13,6 +15,43 @@ type GetSummariesReq struct {
 	PaidAtFrom string `query:"paid_at_from" validate:"datetime=2006-01-02"`
 	PaidAtTo   string `query:"paid_at_to" validate:"datetime=2006-01-02"`
 	Timezone   string `query:"timezone" validate:"timezone"`
+
+	// when set, registrations are summarised by billing period instead of paid_at
+	BillingPeriodFrom string `query:"billing_period_from" validate:"omitempty,datetime=2006-01"`
+	BillingPeriodTo   string `query:"billing_period_to" validate:"omitempty,datetime=2006-01"`
+
+	// comma separated dimensions, at most two, e.g. marketer,month
+	GroupBy string `query:"group_by"`
+
+	Format string `query:"format" validate:"omitempty,oneof=json xlsx csv"`
+}
+
+const (
+	SummaryGroupByMarketer       = "marketer"
+	SummaryGroupByLecturer       = "lecturer"
+	SummaryGroupByProgram        = "program"
+	SummaryGroupByStudentManager = "student_manager"
+	SummaryGroupByMonth          = "month"
+)
+
+var summaryGroupByOptions = []string{
+	SummaryGroupByMarketer,
+	SummaryGroupByLecturer,
+	SummaryGroupByProgram,
+	SummaryGroupByStudentManager,
+	SummaryGroupByMonth,
+}
+
+// GroupByFields returns the dimensions of GroupBy in the requested order
+func (r *GetSummariesReq) GroupByFields() []string {
+	fields := make([]string, 0, 2)
+	for _, field := range strings.Split(r.GroupBy, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
 }
 
 func (r *GetSummariesReq) SetDefault() {
@@ -37,6 +76,24 @@ func (r *GetSummariesReq) Validate() error {
 		err.Add("paid_at_to", "batas atas tanggal pembayaran harus diisi")
 	}
 
+	if (r.BillingPeriodFrom != "" || r.BillingPeriodTo != "") && (r.BillingPeriodFrom == "" || r.BillingPeriodTo == "") {
+		err.Add("billing_period_from", "batas bawah periode tagihan harus diisi")
+		err.Add("billing_period_to", "batas atas periode tagihan harus diisi")
+	}
+
+	fields := r.GroupByFields()
+	for i, field := range fields {
+		if !slices.Contains(summaryGroupByOptions, field) {
+			err.Add("group_by", "group_by harus salah satu dari "+strings.Join(summaryGroupByOptions, ", "))
+		} else if slices.Index(fields, field) != i {
+			err.Add("group_by", "group_by tidak boleh berulang")
+		}
+	}
+
+	if len(fields) > 2 {
+		err.Add("group_by", "group_by maksimal berisi dua pengelompokan")
+	}
+
 	if err.HasErrors() {
 		return err
 	}
@@ -48,10 +105,45 @@ type GetSummariesResp struct {
 	PaidAtFrom string `json:"paid_at_from"`
 	PaidAtTo   string `json:"paid_at_to"`
 
-	TotalHrFee               decimal.Decimal `json:"total_hr_fee"`
-	TotalOverpaymentFee      decimal.Decimal `json:"total_overpayment_fee"`
-	TotalMarketerCommission  decimal.Decimal `json:"total_marketer_commission_fee"`
-	TotalMarketerGifts       decimal.Decimal `json:"total_marketer_gifts_fee"`
-	TotalClosingFeeForReward decimal.Decimal `json:"total_closing_fee_for_reward"`
-	TotalProfit              decimal.Decimal `json:"total_profit"`
+	BillingPeriodFrom string `json:"billing_period_from,omitempty"`
+	BillingPeriodTo   string `json:"billing_period_to,omitempty"`
+
+	SummaryTotals
+
+	// grouped rows, only returned when group_by is set; the totals above are the grand total
+	Groups []SummaryGroup `json:"groups,omitempty"`
+}
+
+type SummaryTotals struct {
+	TotalBilled              decimal.Decimal `json:"total_billed" db:"total_billed"`
+	TotalCashReceived        decimal.Decimal `json:"total_cash_received" db:"total_cash_received"` // payments made in the paid_at range, or toward the billing periods
+	TotalOutstanding         decimal.Decimal `json:"total_outstanding" db:"total_outstanding"`
+	TotalProgramFee          decimal.Decimal `json:"total_program_fee" db:"total_program_fee"`
+	TotalAdministrationFee   decimal.Decimal `json:"total_administration_fee" db:"total_administration_fee"`
+	TotalFLFee               decimal.Decimal `json:"total_foreign_learning_fee" db:"total_foreign_learning_fee"`
+	TotalNLFee               decimal.Decimal `json:"total_night_learning_fee" db:"total_night_learning_fee"`
+	TotalHrFee               decimal.Decimal `json:"total_hr_fee" db:"total_hr_fee"`
+	TotalOverpaymentFee      decimal.Decimal `json:"total_overpayment_fee" db:"total_overpayment_fee"`
+	TotalMarketerCommission  decimal.Decimal `json:"total_marketer_commission_fee" db:"total_marketer_commission_fee"`
+	TotalMarketerGifts       decimal.Decimal `json:"total_marketer_gifts_fee" db:"total_marketer_gifts_fee"`
+	TotalClosingFeeForOffice decimal.Decimal `json:"total_closing_fee_for_office" db:"total_closing_fee_for_office"`
+	TotalClosingFeeForReward decimal.Decimal `json:"total_closing_fee_for_reward" db:"total_closing_fee_for_reward"`
+
+	// calculated by the service from the totals above
+	TotalGrossRevenue decimal.Decimal `json:"total_gross_revenue" db:"-"`
+	TotalCost         decimal.Decimal `json:"total_cost" db:"-"`
+	TotalProfit       decimal.Decimal `json:"total_profit" db:"-"`
+}
+
+// SummaryGroup is keyed by the group_by dimensions. With two dimensions a subtotal
+// row of the first dimension follows its rows and only carries the first key.
+type SummaryGroup struct {
+	Group      map[string]SummaryGroupKey `json:"group"`
+	IsSubtotal bool                       `json:"is_subtotal"`
+	SummaryTotals
+}
+
+type SummaryGroupKey struct {
+	Id   *string `json:"id"`
+	Name *string `json:"name"`
 }
@@ -6,12 +6,22 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// UseHRfeeForLecturerReq records one draw on the hr fee of the lecturer, a
+// registration can be drawn several times as long as the total stays within it
 type UseHRfeeForLecturerReq struct {
 	UserId string `json:"user_id" validate:"ulid"`
 
 	RegistrationId string           `params:"registration_id" validate:"ulid"`
-	UsedAmount     *decimal.Decimal `json:"used_amount"`
+	UsedAmount     *decimal.Decimal `json:"used_amount" validate:"required"`
 	Notes          *string          `json:"notes" validate:"omitempty,max=255"`
+	UsedAt         string           `json:"used_at" validate:"omitempty,datetime=2006-01-02"` // today when empty
+	Timezone       string           `json:"timezone" validate:"required,timezone"`
+}
+
+func (r *UseHRfeeForLecturerReq) SetDefault() {
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
 }
 
 func (r *UseHRfeeForLecturerReq) Validate() error {
@@ -27,3 +37,89 @@ func (r *UseHRfeeForLecturerReq) Validate() error {
 
 	return nil
 }
+
+type UseHRfeeForLecturerResp struct {
+	Id string `json:"id"`
+	MentorFeeBalance
+}
+
+type GetMentorFeeUsagesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string `params:"id" validate:"ulid"`
+}
+
+type GetMentorFeeUsagesResp struct {
+	MentorFeeBalance
+	Items []MentorFeeUsageItem `json:"items"`
+}
+
+// ReverseMentorFeeUsageReq cancels a draw by recording its negated amount, the
+// draw itself is kept so the ledger shows both
+type ReverseMentorFeeUsageReq struct {
+	UserId string `validate:"required,ulid"`
+
+	RegistrationId string  `params:"id" validate:"ulid"`
+	Id             string  `params:"usage_id" validate:"ulid"`
+	Notes          *string `json:"notes" validate:"omitempty,max=255"`
+}
+
+type ReverseMentorFeeUsageResp struct {
+	Id string `json:"id"`
+	MentorFeeBalance
+}
+
+// MentorFeeBalance is the hr fee of the lecturer of a registration compared to
+// the draws recorded against it.
+type MentorFeeBalance struct {
+	RegistrationId   string           `json:"registration_id" db:"registration_id"`
+	HRFeeForLecturer *decimal.Decimal `json:"hr_fee_for_lecturer" db:"hr_fee_for_lecturer"`
+	TotalUsed        decimal.Decimal  `json:"total_used" db:"total_used"`
+	RemainingAmount  decimal.Decimal  `json:"remaining_amount" db:"remaining_amount"`
+}
+
+// DrawError tells why amount can not be drawn from the balance, the hr fee has
+// to be distributed and the draw has to fit in the remaining amount
+func (b *MentorFeeBalance) DrawError(amount decimal.Decimal) *errmsg.CustomError {
+	if b.HRFeeForLecturer == nil {
+		return errmsg.NewCustomErrors(422).SetMessage("Biaya SDM untuk pengajar belum didistribusikan")
+	}
+
+	if amount.GreaterThan(b.RemainingAmount) {
+		return errmsg.NewCustomErrors(422).SetMessage("Jumlah yang digunakan melebihi sisa dana pengajar sebesar " + b.RemainingAmount.StringFixed(2))
+	}
+
+	return nil
+}
+
+// MentorFeeUsageState tells whether a ledger entry can still be reversed
+type MentorFeeUsageState struct {
+	IsReversal bool `db:"is_reversal"`
+	IsReversed bool `db:"is_reversed"`
+}
+
+// ReverseError tells why the entry can not be reversed, only a draw that has
+// not been reversed yet can be
+func (s *MentorFeeUsageState) ReverseError() *errmsg.CustomError {
+	if s.IsReversal {
+		return errmsg.NewCustomErrors(422).SetMessage("Pembatalan penggunaan dana tidak dapat dibatalkan")
+	}
+
+	if s.IsReversed {
+		return errmsg.NewCustomErrors(409).SetMessage("Penggunaan dana pengajar sudah dibatalkan")
+	}
+
+	return nil
+}
+
+type MentorFeeUsageItem struct {
+	Id         string          `json:"id" db:"id"`
+	UserId     *string         `json:"user_id" db:"user_id"`
+	UserName   *string         `json:"user_name" db:"user_name"`
+	Amount     decimal.Decimal `json:"amount" db:"amount"`
+	Notes      *string         `json:"notes" db:"notes"`
+	UsedAt     string          `json:"used_at" db:"used_at"`
+	ReversalOf *string         `json:"reversal_of" db:"reversal_of"` // the draw this entry reverses
+	ReversedBy *string         `json:"reversed_by" db:"reversed_by"` // the entry that reversed this draw
+	CreatedAt  string          `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,120 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func decimalPtr(v decimal.Decimal) *decimal.Decimal {
+	return &v
+}
+
+func TestUseHRfeeForLecturerReqValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  *decimal.Decimal
+		wantErr bool
+	}{
+		{"positive", decimalPtr(decimal.NewFromInt(50000)), false},
+		{"missing is left to the validator tags", nil, false},
+		{"zero", decimalPtr(decimal.Zero), true},
+		{"negative", decimalPtr(decimal.NewFromInt(-1)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &UseHRfeeForLecturerReq{UsedAmount: tt.amount}
+
+			err := req.Validate()
+			if !tt.wantErr {
+				assert.NoError(t, err)
+				return
+			}
+
+			cerr, ok := err.(*errmsg.CustomError)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, cerr.Code)
+				assert.Contains(t, cerr.Errors, "used_amount")
+			}
+		})
+	}
+}
+
+func TestMentorFeeBalanceDrawError(t *testing.T) {
+	distributed := decimalPtr(decimal.NewFromInt(150000))
+
+	tests := []struct {
+		name    string
+		balance MentorFeeBalance
+		amount  int64
+		wantErr bool
+	}{
+		{
+			name:    "within remaining",
+			balance: MentorFeeBalance{HRFeeForLecturer: distributed, TotalUsed: decimal.NewFromInt(50000), RemainingAmount: decimal.NewFromInt(100000)},
+			amount:  60000,
+		},
+		{
+			name:    "exactly remaining",
+			balance: MentorFeeBalance{HRFeeForLecturer: distributed, TotalUsed: decimal.NewFromInt(50000), RemainingAmount: decimal.NewFromInt(100000)},
+			amount:  100000,
+		},
+		{
+			name:    "after a reversal",
+			balance: MentorFeeBalance{HRFeeForLecturer: distributed, RemainingAmount: decimal.NewFromInt(150000)},
+			amount:  150000,
+		},
+		{
+			name:    "more than remaining",
+			balance: MentorFeeBalance{HRFeeForLecturer: distributed, TotalUsed: decimal.NewFromInt(50000), RemainingAmount: decimal.NewFromInt(100000)},
+			amount:  100001,
+			wantErr: true,
+		},
+		{
+			name:    "not distributed",
+			balance: MentorFeeBalance{},
+			amount:  1,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.balance.DrawError(decimal.NewFromInt(tt.amount))
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, 422, err.Code)
+				return
+			}
+			assert.Nil(t, err)
+		})
+	}
+}
+
+func TestMentorFeeUsageStateReverseError(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    MentorFeeUsageState
+		wantCode int
+	}{
+		{"draw", MentorFeeUsageState{}, 0},
+		{"reversal", MentorFeeUsageState{IsReversal: true}, 422},
+		{"reversed draw", MentorFeeUsageState{IsReversed: true}, 409},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.state.ReverseError()
+			if tt.wantCode == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			if assert.NotNil(t, err) {
+				assert.Equal(t, tt.wantCode, err.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ArrearStatusMissing       = "missing" // no registration in the period
+	ArrearStatusUnpaid        = PaymentStatusUnpaid
+	ArrearStatusPartiallyPaid = PaymentStatusPartiallyPaid
+
+	AgingBucket0To30  = "0-30"
+	AgingBucket31To60 = "31-60"
+	AgingBucket61To90 = "61-90"
+	AgingBucketOver90 = "90+"
+)
+
+type GetArrearsReq struct {
+	UserId string `validate:"required,ulid"`
+
+	PeriodFrom string `query:"period_from" validate:"datetime=2006-01"`
+	PeriodTo   string `query:"period_to" validate:"datetime=2006-01"`
+	AsOf       string `query:"as_of" validate:"datetime=2006-01-02"` // aging is counted up to this date
+	Timezone   string `query:"timezone" validate:"timezone"`
+
+	Q          string `query:"q" validate:"omitempty,min=3"` // search by student name or program name
+	MarketerId string `query:"marketer_id" validate:"omitempty,ulid"`
+	LecturerId string `query:"lecturer_id" validate:"omitempty,ulid"`
+	StudentId  string `query:"student_id" validate:"omitempty,ulid"`
+	ProgramId  string `query:"program_id" validate:"omitempty,ulid"`
+
+	Status      string `query:"status" validate:"omitempty,oneof=missing unpaid partially_paid"`
+	AgingBucket string `query:"aging_bucket" validate:"omitempty,oneof=0-30 31-60 61-90 90+"`
+
+	SortBy   string `query:"sort_by" validate:"omitempty,oneof=days_overdue amount_owed student_name billing_period"`
+	SortType string `query:"sort_type" validate:"omitempty,oneof=asc desc"`
+
+	types.MetaQuery
+}
+
+func (r *GetArrearsReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		loc = time.FixedZone("Asia/Makassar", 8*3600)
+	}
+	now := time.Now().In(loc)
+
+	if r.PeriodFrom == "" {
+		r.PeriodFrom = now.Format("2006") + "-01"
+	}
+
+	if r.PeriodTo == "" {
+		r.PeriodTo = now.Format("2006-01")
+	}
+
+	if r.AsOf == "" {
+		r.AsOf = now.Format("2006-01-02")
+	}
+
+	if r.SortBy == "" {
+		r.SortBy = "days_overdue"
+	}
+
+	if r.SortType == "" {
+		r.SortType = "desc"
+	}
+}
+
+func (r *GetArrearsReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	from, errFrom := time.Parse("2006-01", r.PeriodFrom)
+	to, errTo := time.Parse("2006-01", r.PeriodTo)
+	if errFrom == nil && errTo == nil {
+		if from.After(to) {
+			err.Add("period_from", "periode awal tidak boleh melebihi periode akhir")
+		}
+
+		if to.After(from.AddDate(0, 23, 0)) {
+			err.Add("period_to", "rentang periode maksimal 24 bulan")
+		}
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+type GetArrearsResp struct {
+	PeriodFrom string         `json:"period_from"`
+	PeriodTo   string         `json:"period_to"`
+	AsOf       string         `json:"as_of"`
+	Summary    ArrearsSummary `json:"summary"`
+	Items      []ArrearItem   `json:"items"`
+	Meta       types.Meta     `json:"meta"`
+}
+
+type ArrearsSummary struct {
+	TotalOwed          decimal.Decimal `json:"total_owed" db:"total_owed"`
+	TotalMissingMonths int             `json:"total_missing_months" db:"total_missing_months"`
+	TotalUnpaidMonths  int             `json:"total_unpaid_months" db:"total_unpaid_months"`
+	TotalPartialMonths int             `json:"total_partially_paid_months" db:"total_partially_paid_months"`
+	Owed0To30          decimal.Decimal `json:"owed_0_30" db:"owed_0_30"`
+	Owed31To60         decimal.Decimal `json:"owed_31_60" db:"owed_31_60"`
+	Owed61To90         decimal.Decimal `json:"owed_61_90" db:"owed_61_90"`
+	OwedOver90         decimal.Decimal `json:"owed_90_plus" db:"owed_90_plus"`
+}
+
+type ArrearItem struct {
+	TemplateId     string          `json:"template_id" db:"template_id"`
+	RegistrationId *string         `json:"registration_id" db:"registration_id"`
+	ProgramId      string          `json:"program_id" db:"program_id"`
+	MarketerId     string          `json:"marketer_id" db:"marketer_id"`
+	LecturerId     *string         `json:"lecturer_id" db:"lecturer_id"`
+	StudentId      string          `json:"student_id" db:"student_id"`
+	ProgramName    string          `json:"program_name" db:"program_name"`
+	MarketerName   string          `json:"marketer_name" db:"marketer_name"`
+	LecturerName   *string         `json:"lecturer_name" db:"lecturer_name"`
+	StudentName    string          `json:"student_name" db:"student_name"`
+	BillingPeriod  string          `json:"billing_period" db:"billing_period"`
+	Status         string          `json:"status" db:"status"`
+	MonthlyFee     decimal.Decimal `json:"monthly_fee" db:"monthly_fee"`
+	TotalBill      decimal.Decimal `json:"total_bill" db:"total_bill"`
+	TotalPaid      decimal.Decimal `json:"total_paid" db:"total_paid"`
+	AmountOwed     decimal.Decimal `json:"amount_owed" db:"amount_owed"`
+	DaysOverdue    int             `json:"days_overdue" db:"days_overdue"`
+	AgingBucket    string          `json:"aging_bucket" db:"aging_bucket"`
+}
@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetArrearsReqValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		from       string
+		to         string
+		wantFields []string
+	}{
+		{"one month", "2026-10", "2026-10", nil},
+		{"24 months", "2025-01", "2026-12", nil},
+		{"25 months", "2025-01", "2027-01", []string{"period_to"}},
+		{"from after to", "2026-10", "2026-09", []string{"period_from"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &GetArrearsReq{PeriodFrom: tt.from, PeriodTo: tt.to}
+
+			err := req.Validate()
+			if len(tt.wantFields) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+
+			cerr, ok := err.(*errmsg.CustomError)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, cerr.Code)
+				assert.Len(t, cerr.Errors, len(tt.wantFields))
+				for _, field := range tt.wantFields {
+					assert.Contains(t, cerr.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestGetArrearsReqSetDefault(t *testing.T) {
+	req := &GetArrearsReq{Timezone: "UTC"}
+	req.SetDefault()
+
+	now := time.Now().UTC()
+	assert.Equal(t, now.Format("2006")+"-01", req.PeriodFrom)
+	assert.Equal(t, now.Format("2006-01"), req.PeriodTo)
+	assert.Equal(t, now.Format("2006-01-02"), req.AsOf)
+	assert.Equal(t, "days_overdue", req.SortBy)
+	assert.Equal(t, "desc", req.SortType)
+
+	req = &GetArrearsReq{PeriodFrom: "2025-03", SortBy: "amount_owed", SortType: "asc"}
+	req.SetDefault()
+
+	assert.Equal(t, "Asia/Makassar", req.Timezone)
+	assert.Equal(t, "2025-03", req.PeriodFrom)
+	assert.Equal(t, "amount_owed", req.SortBy)
+	assert.Equal(t, "asc", req.SortType)
+}
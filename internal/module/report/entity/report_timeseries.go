@@ -0,0 +1,124 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	TimeseriesIntervalDay   = "day"
+	TimeseriesIntervalWeek  = "week"
+	TimeseriesIntervalMonth = "month"
+)
+
+type GetTimeseriesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	From     string `query:"from" validate:"datetime=2006-01-02"`
+	To       string `query:"to" validate:"datetime=2006-01-02"`
+	Interval string `query:"interval" validate:"oneof=day week month"`
+	Timezone string `query:"timezone" validate:"timezone"`
+
+	// also return the same range shifted one year back
+	ComparePreviousYear bool `query:"compare_previous_year"`
+}
+
+func (r *GetTimeseriesReq) SetDefault() {
+	if r.Timezone == "" {
+		r.Timezone = "Asia/Makassar"
+	}
+
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		loc = time.FixedZone("Asia/Makassar", 8*3600)
+	}
+	now := time.Now().In(loc)
+
+	if r.From == "" {
+		r.From = now.Format("2006") + "-01-01"
+	}
+
+	if r.To == "" {
+		r.To = now.Format("2006-01-02")
+	}
+
+	if r.Interval == "" {
+		r.Interval = TimeseriesIntervalMonth
+	}
+}
+
+func (r *GetTimeseriesReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	from, errFrom := time.Parse("2006-01-02", r.From)
+	to, errTo := time.Parse("2006-01-02", r.To)
+	if errFrom == nil && errTo == nil {
+		if from.After(to) {
+			err.Add("from", "tanggal awal tidak boleh melebihi tanggal akhir")
+		}
+
+		// keep the number of buckets reasonable for a chart
+		if r.Interval == TimeseriesIntervalDay && to.After(from.AddDate(1, 0, 0)) {
+			err.Add("to", "rentang harian maksimal 1 tahun")
+		}
+
+		if to.After(from.AddDate(5, 0, 0)) {
+			err.Add("to", "rentang maksimal 5 tahun")
+		}
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
+
+// PreviousYear returns a copy of the request shifted one year back
+func (r *GetTimeseriesReq) PreviousYear() *GetTimeseriesReq {
+	prev := *r
+	prev.ComparePreviousYear = false
+
+	if from, err := time.Parse("2006-01-02", r.From); err == nil {
+		prev.From = from.AddDate(-1, 0, 0).Format("2006-01-02")
+	}
+
+	if to, err := time.Parse("2006-01-02", r.To); err == nil {
+		prev.To = to.AddDate(-1, 0, 0).Format("2006-01-02")
+	}
+
+	return &prev
+}
+
+type GetTimeseriesResp struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Interval string `json:"interval"`
+	Timezone string `json:"timezone"`
+
+	Items        []TimeseriesBucket `json:"items"`
+	PreviousYear []TimeseriesBucket `json:"previous_year,omitempty"`
+}
+
+// TimeseriesBucket is keyed by the first day of the day, week (Monday) or month
+type TimeseriesBucket struct {
+	Period              string          `json:"period" db:"period"`
+	TotalRegistrations  int             `json:"total_registrations" db:"total_registrations"`
+	ProgramFee          decimal.Decimal `json:"program_fee" db:"program_fee"`
+	AdministrationFee   decimal.Decimal `json:"administration_fee" db:"administration_fee"`
+	FLFee               decimal.Decimal `json:"foreign_learning_fee" db:"foreign_learning_fee"`
+	NLFee               decimal.Decimal `json:"night_learning_fee" db:"night_learning_fee"`
+	MarketerCommission  decimal.Decimal `json:"marketer_commission_fee" db:"marketer_commission_fee"`
+	MarketerGifts       decimal.Decimal `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
+	HRFee               decimal.Decimal `json:"hr_fee" db:"hr_fee"`
+	OverpaymentFee      decimal.Decimal `json:"overpayment_fee" db:"overpayment_fee"`
+	ClosingFeeForOffice decimal.Decimal `json:"closing_fee_for_office" db:"closing_fee_for_office"`
+	ClosingFeeForReward decimal.Decimal `json:"closing_fee_for_reward" db:"closing_fee_for_reward"`
+
+	// calculated by the service from the fees above
+	GrossRevenue decimal.Decimal `json:"gross_revenue" db:"-"`
+	TotalCost    decimal.Decimal `json:"total_cost" db:"-"`
+	Profit       decimal.Decimal `json:"profit" db:"-"`
+}
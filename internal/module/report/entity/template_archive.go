@@ -0,0 +1,13 @@
+package entity
+
+type ArchiveTemplateReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
+
+type UnarchiveTemplateReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id string `params:"id" validate:"ulid"`
+}
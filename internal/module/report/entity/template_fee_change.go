@@ -0,0 +1,127 @@
+package entity
+
+import (
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+)
+
+const (
+	TemplateFeeChangeStatusPending  = "pending"
+	TemplateFeeChangeStatusApproved = "approved"
+	TemplateFeeChangeStatusRejected = "rejected"
+)
+
+// TemplateFeeChangeApproval is the review state of a fee change
+type TemplateFeeChangeApproval struct {
+	TemplateId     string `db:"template_id"`
+	TemplateUserId string `db:"template_user_id"` // the user who created the template
+	UserId         string `db:"user_id"`          // the user who proposed the change
+	Status         string `db:"status"`
+}
+
+// CanReview reports whether the change awaits a review
+func (a *TemplateFeeChangeApproval) CanReview() bool {
+	return a.Status == TemplateFeeChangeStatusPending
+}
+
+// IsMaker reports whether the user created the template or proposed the
+// change, a maker can not review it
+func (a *TemplateFeeChangeApproval) IsMaker(userId string) bool {
+	return a.TemplateUserId == userId || a.UserId == userId
+}
+
+type GetTemplateFeeChangesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	TemplateId string `query:"template_id" validate:"omitempty,ulid"`
+	Status     string `query:"status" validate:"omitempty,oneof=pending approved rejected"`
+
+	types.MetaQuery
+}
+
+func (r *GetTemplateFeeChangesReq) SetDefault() {
+	r.MetaQuery.SetDefault()
+}
+
+type GetTemplateFeeChangesResp struct {
+	Items []TemplateFeeChange `json:"items"`
+	Meta  types.Meta          `json:"meta"`
+}
+
+// TemplateFeeChange is a fee edit of a template waiting for, or decided by, an
+// approver. Current holds the fees of the template now, Proposed the edit.
+type TemplateFeeChange struct {
+	Id          string       `json:"id" db:"id"`
+	TemplateId  string       `json:"template_id" db:"template_id"`
+	StudentName string       `json:"student_name" db:"student_name"`
+	ProgramName string       `json:"program_name" db:"program_name"`
+	UserId      string       `json:"user_id" db:"user_id"`
+	UserName    *string      `json:"user_name" db:"user_name"`
+	Status      string       `json:"status" db:"status"`
+	Current     TemplateFees `json:"current" db:"current"`
+	Proposed    TemplateFees `json:"proposed" db:"proposed"`
+	// proposed by propagating the program price, the template follows it once approved
+	FollowsProgramPrice bool    `json:"follows_program_price" db:"follows_program_price"`
+	ReviewedAt          *string `json:"reviewed_at" db:"reviewed_at"`
+	ReviewedBy          *string `json:"reviewed_by" db:"reviewed_by"`
+	ReviewedByName      *string `json:"reviewed_by_name" db:"reviewed_by_name"`
+	ReviewComment       *string `json:"review_comment" db:"review_comment"`
+	CreatedAt           string  `json:"created_at" db:"created_at"`
+	UpdatedAt           string  `json:"updated_at" db:"updated_at"`
+}
+
+// TemplateFees are the fee columns of a template, or of a fee change
+type TemplateFees struct {
+	ProgramFee            *float64 `json:"program_fee" db:"program_fee"`
+	AdministrationFee     *float64 `json:"administration_fee" db:"administration_fee"`
+	FLFee                 *float64 `json:"foreign_learning_fee" db:"foreign_learning_fee"`
+	NLFee                 *float64 `json:"night_learning_fee" db:"night_learning_fee"`
+	MarketerCommissionFee *float64 `json:"marketer_commission_fee" db:"marketer_commission_fee"`
+	OverpaymentFee        *float64 `json:"overpayment_fee" db:"overpayment_fee"`
+	HRFee                 *float64 `json:"hr_fee" db:"hr_fee"`
+	MarketerGiftsFee      *float64 `json:"marketer_gifts_fee" db:"marketer_gifts_fee"`
+	ClosingFeeForOffice   *float64 `json:"closing_fee_for_office" db:"closing_fee_for_office"`
+	ClosingFeeForReward   *float64 `json:"closing_fee_for_reward" db:"closing_fee_for_reward"`
+}
+
+// WithProgramFees returns the fees with the program fee, marketer commission
+// and hr fee of the program price
+func (f TemplateFees) WithProgramFees(program ProgramFees) TemplateFees {
+	f.ProgramFee = program.ProgramFee
+	f.MarketerCommissionFee = program.MarketerCommissionFee
+	f.HRFee = program.HRFee
+	return f
+}
+
+// Values lists the fees in the order of the template fee columns
+func (f TemplateFees) Values() []any {
+	return []any{
+		f.ProgramFee, f.AdministrationFee, f.FLFee, f.NLFee,
+		f.MarketerCommissionFee, f.OverpaymentFee, f.HRFee, f.MarketerGiftsFee,
+		f.ClosingFeeForOffice, f.ClosingFeeForReward,
+	}
+}
+
+// ReviewTemplateFeeChangeReq approves or rejects a pending fee change, Approve
+// is set by the handler from the route
+type ReviewTemplateFeeChangeReq struct {
+	UserId string `validate:"required,ulid"`
+
+	Id      string  `params:"id" validate:"ulid"`
+	Approve bool    `json:"-"`
+	Comment *string `json:"comment" validate:"omitempty,max=255"`
+}
+
+func (r *ReviewTemplateFeeChangeReq) Validate() error {
+	err := errmsg.NewCustomErrors(400)
+
+	if !r.Approve && (r.Comment == nil || *r.Comment == "") {
+		err.Add("comment", "alasan penolakan harus diisi")
+	}
+
+	if err.HasErrors() {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ptr(v float64) *float64 {
+	return &v
+}
+
+func TestTemplateFeesWithProgramFees(t *testing.T) {
+	current := TemplateFees{
+		ProgramFee:            ptr(400000),
+		AdministrationFee:     ptr(25000),
+		MarketerCommissionFee: ptr(40000),
+		HRFee:                 ptr(150000),
+		MarketerGiftsFee:      ptr(10000),
+	}
+	program := ProgramFees{
+		ProgramFee:            ptr(500000),
+		MarketerCommissionFee: ptr(50000),
+		HRFee:                 ptr(200000),
+	}
+
+	got := current.WithProgramFees(program)
+
+	assert.Equal(t, 500000.0, *got.ProgramFee)
+	assert.Equal(t, 50000.0, *got.MarketerCommissionFee)
+	assert.Equal(t, 200000.0, *got.HRFee)
+	assert.Equal(t, 25000.0, *got.AdministrationFee, "other fees are kept")
+	assert.Equal(t, 10000.0, *got.MarketerGiftsFee, "other fees are kept")
+	assert.Nil(t, got.FLFee)
+	assert.Equal(t, 400000.0, *current.ProgramFee, "the current fees are not changed")
+}
+
+func TestUpdateTemplateGeneralReqFees(t *testing.T) {
+	req := &UpdateTemplateGeneralReq{
+		ProgramFee:            500000,
+		AdministrationFee:     25000,
+		NLFee:                 ptr(30000),
+		MarketerCommissionFee: 50000,
+		HRFee:                 200000,
+		MarketerGiftsFee:      10000,
+		ClosingFeeForReward:   ptr(5000),
+	}
+
+	values := req.Fees().Values()
+
+	// the order of the template fee columns
+	assert.Len(t, values, 10)
+	assert.Equal(t, 500000.0, *values[0].(*float64))
+	assert.Equal(t, 25000.0, *values[1].(*float64))
+	assert.Nil(t, values[2].(*float64))
+	assert.Equal(t, 30000.0, *values[3].(*float64))
+	assert.Equal(t, 50000.0, *values[4].(*float64))
+	assert.Nil(t, values[5].(*float64))
+	assert.Equal(t, 200000.0, *values[6].(*float64))
+	assert.Equal(t, 10000.0, *values[7].(*float64))
+	assert.Nil(t, values[8].(*float64))
+	assert.Equal(t, 5000.0, *values[9].(*float64))
+}
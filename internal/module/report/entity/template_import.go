@@ -0,0 +1,98 @@
+package entity
+
+const (
+	TemplateImportRowValid   = "valid"
+	TemplateImportRowInvalid = "invalid"
+	TemplateImportRowCreated = "created"
+)
+
+// TemplateImportHeaders are the columns of the import file, in the order of the blank template.
+// student, program, marketer and lecturer accept an id, identifier/email/phone or exact name,
+// additional_students is separated by ";" (a student identifier or a free-text name)
+// and days is separated by "," (1 = Monday ... 7 = Sunday).
+var TemplateImportHeaders = []string{
+	"student",
+	"program",
+	"marketer",
+	"lecturer",
+	"additional_students",
+	"days",
+	"notes",
+	"administration_fee",
+	"foreign_learning_fee",
+	"night_learning_fee",
+	"overpayment_fee",
+	"marketer_gifts_fee",
+	"closing_fee_for_office",
+	"closing_fee_for_reward",
+}
+
+type ImportTemplatesReq struct {
+	UserId string `validate:"ulid"`
+
+	DryRun   bool   `form:"dry_run"`
+	Filename string `validate:"required"`
+	Format   string `validate:"oneof=xlsx csv"`
+	Content  []byte `json:"-"`
+
+	// filled by the service after parsing and resolving the file
+	Rows []TemplateImportRow `json:"-"`
+}
+
+type GetTemplateImportFileReq struct {
+	Format string `query:"format" validate:"omitempty,oneof=xlsx csv"`
+}
+
+func (r *GetTemplateImportFileReq) SetDefault() {
+	if r.Format == "" {
+		r.Format = ExportFormatXLSX
+	}
+}
+
+// TemplateImportRow is one data row of the import file, Values is keyed by TemplateImportHeaders
+type TemplateImportRow struct {
+	Row      int
+	Values   map[string]string
+	Template *CreateTemplateReq
+	Errors   map[string][]string
+}
+
+func (r *TemplateImportRow) AddError(field, msg string) {
+	if r.Errors == nil {
+		r.Errors = make(map[string][]string)
+	}
+	r.Errors[field] = append(r.Errors[field], msg)
+}
+
+// TemplateImportReferences holds the students, programs, marketers and lecturers
+// that match the values used in an import file
+type TemplateImportReferences struct {
+	Students  []TemplateImportReference
+	Programs  []TemplateImportReference
+	Marketers []TemplateImportReference
+	Lecturers []TemplateImportReference
+}
+
+type TemplateImportReference struct {
+	Id         string  `db:"id"`
+	Name       string  `db:"name"`
+	Identifier *string `db:"identifier"`
+	Email      *string `db:"email"`
+	Phone      *string `db:"phone"`
+}
+
+type ImportTemplatesResp struct {
+	DryRun      bool                      `json:"dry_run"`
+	Committed   bool                      `json:"committed"`
+	TotalRows   int                       `json:"total_rows"`
+	ValidRows   int                       `json:"valid_rows"`
+	InvalidRows int                       `json:"invalid_rows"`
+	Rows        []TemplateImportRowResult `json:"rows"`
+}
+
+type TemplateImportRowResult struct {
+	Row    int                 `json:"row"`
+	Status string              `json:"status"`
+	Id     string              `json:"id,omitempty"`
+	Errors map[string][]string `json:"errors,omitempty"`
+}
@@ -20,6 +20,9 @@ type GetTemplatesReq struct {
 	LecturerId       string `query:"lecturer_id" validate:"omitempty,ulid"`
 	StudentId        string `query:"student_id" validate:"omitempty,ulid"`
 	ProgramId        string `query:"program_id" validate:"omitempty,ulid"`
+
+	IncludeArchived bool `query:"include_archived"`
+	Outdated        bool `query:"outdated"` // only templates whose fees differ from their program
 }
 
 func (r *GetTemplatesReq) SetDefault() {
@@ -68,6 +71,7 @@ type TemplateItem struct {
 	ClosingFeeForReward     *float64      `json:"closing_fee_for_reward" db:"closing_fee_for_reward"`
 	Notes                   *string       `json:"notes" db:"notes"`
 	IsFinanceUpdateRequired bool          `json:"is_finance_update_required" db:"is_finance_update_required"`
+	IsOutdated              bool          `json:"is_outdated" db:"is_outdated"`
 	CreatedAt               string        `json:"created_at" db:"created_at"`
 	UpdatedAt               string        `json:"updated_at" db:"updated_at"`
 	DeletedAt               *string       `json:"deleted_at" db:"deleted_at"`
@@ -0,0 +1,50 @@
+package entity
+
+// ProgramFees are the template fees that follow the price, commission_fee and
+// lecturer_fee of their program
+type ProgramFees struct {
+	ProgramFee            *float64 `json:"program_fee" db:"program_fee"`
+	MarketerCommissionFee *float64 `json:"marketer_commission_fee" db:"marketer_commission_fee"`
+	HRFee                 *float64 `json:"hr_fee" db:"hr_fee"`
+}
+
+type GetTemplatePropagationReq struct {
+	UserId string `validate:"required,ulid"`
+
+	ProgramId string `params:"id" validate:"ulid"`
+}
+
+type GetTemplatePropagationResp struct {
+	ProgramId   string                `json:"program_id" db:"id"`
+	ProgramName string                `json:"program_name" db:"name"`
+	Program     ProgramFees           `json:"program" db:"program"`
+	Items       []TemplatePropagation `json:"items" db:"-"`
+}
+
+// TemplatePropagation is an active template whose fees differ from its program,
+// Current holds the fees of the template and New the fees of the program
+type TemplatePropagation struct {
+	TemplateId          string      `json:"template_id" db:"template_id"`
+	StudentName         string      `json:"student_name" db:"student_name"`
+	LecturerName        *string     `json:"lecturer_name" db:"lecturer_name"`
+	MarketerName        string      `json:"marketer_name" db:"marketer_name"`
+	Current             ProgramFees `json:"current" db:"current"`
+	New                 ProgramFees `json:"new" db:"new"`
+	HasPendingFeeChange bool        `json:"has_pending_fee_change" db:"has_pending_fee_change"` // it can not be propagated until the change is reviewed
+}
+
+type PropagateProgramFeesReq struct {
+	UserId string `validate:"required,ulid"`
+
+	ProgramId   string   `params:"id" validate:"ulid"`
+	TemplateIds []string `json:"template_ids" validate:"required,min=1,max=500,dive,ulid"`
+}
+
+// PropagateProgramFeesResp lists the fee changes queued for an approver, a
+// template that already follows the program price queues none
+type PropagateProgramFeesResp struct {
+	ProgramId           string   `json:"program_id"`
+	TemplateIds         []string `json:"template_ids"`
+	PendingFeeChangeIds []string `json:"pending_fee_change_ids"`
+	TotalQueued         int      `json:"total_queued"`
+}
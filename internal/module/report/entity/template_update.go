@@ -29,6 +29,22 @@ type UpdateTemplateGeneralReq struct {
 	ClosingFeeForReward   *float64 `json:"closing_fee_for_reward" validate:"omitempty,min=0"`
 }
 
+// Fees returns the fees of the request as a proposed fee change
+func (req *UpdateTemplateGeneralReq) Fees() TemplateFees {
+	return TemplateFees{
+		ProgramFee:            &req.ProgramFee,
+		AdministrationFee:     &req.AdministrationFee,
+		FLFee:                 req.FLFee,
+		NLFee:                 req.NLFee,
+		MarketerCommissionFee: &req.MarketerCommissionFee,
+		OverpaymentFee:        req.OverpaymentFee,
+		HRFee:                 &req.HRFee,
+		MarketerGiftsFee:      &req.MarketerGiftsFee,
+		ClosingFeeForOffice:   req.ClosingFeeForOffice,
+		ClosingFeeForReward:   req.ClosingFeeForReward,
+	}
+}
+
 func (req *UpdateTemplateGeneralReq) Validate() error {
 	err := errmsg.NewCustomErrors(400)
 
@@ -63,4 +79,6 @@ type UpdateTemplateFinanceReq struct {
 
 type UpdateTemplateResp struct {
 	Id string `json:"id"`
+	// set when the fees changed, they are applied once the change is approved
+	PendingFeeChangeId *string `json:"pending_fee_change_id"`
 }
@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"codebase-app/internal/adapter"
+	m "codebase-app/internal/middleware"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/response"
+	"codebase-app/pkg/types"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// approverRoles may approve or reject registrations and template fee changes,
+// and propagate program fees to templates
+var approverRoles = []string{types.RoleAdmin}
+
+func (h *reportHandler) submitRegistration(c *fiber.Ctx) error {
+	var (
+		req = new(entity.SubmitRegistrationReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::submitRegistration - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.SubmitRegistration(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) approveRegistration(c *fiber.Ctx) error {
+	return h.reviewRegistration(c, true)
+}
+
+func (h *reportHandler) rejectRegistration(c *fiber.Ctx) error {
+	return h.reviewRegistration(c, false)
+}
+
+func (h *reportHandler) reviewRegistration(c *fiber.Ctx, approve bool) error {
+	var (
+		req = new(entity.ReviewRegistrationReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::reviewRegistration - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+	req.Approve = approve
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::reviewRegistration - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::reviewRegistration - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.ReviewRegistration(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) getTemplateFeeChanges(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetTemplateFeeChangesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getTemplateFeeChanges - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getTemplateFeeChanges - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTemplateFeeChanges(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) approveTemplateFeeChange(c *fiber.Ctx) error {
+	return h.reviewTemplateFeeChange(c, true)
+}
+
+func (h *reportHandler) rejectTemplateFeeChange(c *fiber.Ctx) error {
+	return h.reviewTemplateFeeChange(c, false)
+}
+
+func (h *reportHandler) reviewTemplateFeeChange(c *fiber.Ctx, approve bool) error {
+	var (
+		req = new(entity.ReviewTemplateFeeChangeReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::reviewTemplateFeeChange - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+	req.Approve = approve
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::reviewTemplateFeeChange - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::reviewTemplateFeeChange - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.ReviewTemplateFeeChange(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) getTemplatePropagation(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetTemplatePropagationReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.ProgramId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getTemplatePropagation - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTemplatePropagation(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+// propagateProgramFees proposes the program fees to the templates, another
+// approver reviews the queued fee changes
+func (h *reportHandler) propagateProgramFees(c *fiber.Ctx) error {
+	var (
+		req = new(entity.PropagateProgramFeesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::propagateProgramFees - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.ProgramId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::propagateProgramFees - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.PropagateProgramFees(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
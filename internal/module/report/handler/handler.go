@@ -9,6 +9,9 @@ import (
 	"codebase-app/internal/module/report/service"
 	"codebase-app/pkg/errmsg"
 	"codebase-app/pkg/response"
+	"io"
+	"path/filepath"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
@@ -32,8 +35,18 @@ func NewReportHandler() *reportHandler {
 func (h *reportHandler) Register(router fiber.Router) {
 	router.Post("/templates", m.AuthBearer, h.createTemplate)
 	router.Get("/templates", m.AuthBearer, h.getTemplates)
+	router.Get("/templates/import-file", m.AuthBearer, h.getTemplateImportFile)
+	router.Post("/templates/import", m.AuthBearer, h.importTemplates)
 	router.Put("/templates/:id", m.AuthBearer, h.updateTemplate)
 	router.Get("/templates/:id", m.AuthBearer, h.getTemplate)
+	router.Put("/templates/:id/archive", m.AuthBearer, h.archiveTemplate)
+	router.Put("/templates/:id/unarchive", m.AuthBearer, h.unarchiveTemplate)
+	router.Get("/templates/:id/history", m.AuthBearer, h.getTemplateHistory)
+	router.Get("/template-fee-changes", m.AuthBearer, h.getTemplateFeeChanges)
+	router.Put("/template-fee-changes/:id/approve", m.AuthBearer, m.AuthRole(approverRoles), h.approveTemplateFeeChange)
+	router.Put("/template-fee-changes/:id/reject", m.AuthBearer, m.AuthRole(approverRoles), h.rejectTemplateFeeChange)
+	router.Get("/programs/:id/template-propagation", m.AuthBearer, h.getTemplatePropagation)
+	router.Post("/programs/:id/template-propagation", m.AuthBearer, m.AuthRole(approverRoles), h.propagateProgramFees)
 
 	router.Post("/registrations", m.AuthBearer, h.createRegistrations)
 	router.Post("/copy-registrations", m.AuthBearer, h.copyRegistrations)
@@ -41,12 +54,72 @@ func (h *reportHandler) Register(router fiber.Router) {
 	router.Get("/registrations", m.AuthBearer, h.getRegistrations)
 	router.Put("/registrations/:id", m.AuthBearer, h.updateRegistration)
 	router.Get("/registrations/:id", m.AuthBearer, h.getRegistration)
+	router.Delete("/registrations/:id", m.AuthBearer, h.deleteRegistration)
+	router.Put("/registrations/:id/restore", m.AuthBearer, h.restoreRegistration)
+	router.Get("/registrations/:id/history", m.AuthBearer, h.getRegistrationHistory)
+	router.Put("/registrations/:id/submit", m.AuthBearer, h.submitRegistration)
+	router.Put("/registrations/:id/approve", m.AuthBearer, m.AuthRole(approverRoles), h.approveRegistration)
+	router.Put("/registrations/:id/reject", m.AuthBearer, m.AuthRole(approverRoles), h.rejectRegistration)
+	router.Get("/deleted-registrations", m.AuthBearer, h.getDeletedRegistrations)
+	router.Post("/registrations/:id/payments", m.AuthBearer, h.createRegistrationPayment)
+	router.Get("/registrations/:id/payments", m.AuthBearer, h.getRegistrationPayments)
+	router.Delete("/registrations/:id/payments/:payment_id", m.AuthBearer, h.deleteRegistrationPayment)
+	router.Get("/registrations/:id/invoice", m.AuthBearer, h.getInvoice)
+	router.Post("/registration-invoices", m.AuthBearer, h.generateInvoices)
+	router.Post("/registrations/:id/receipt", m.AuthBearer, h.issueReceipt)
+	router.Get("/receipts/verify", h.verifyReceipt) // public, opened from the receipt QR code
 	router.Put("/registrations/:id/hr-fee-distributions", m.AuthBearer, h.hrDistributions)
-	router.Put("/registrations/:id/lecturer-distributions", m.AuthBearer, h.lecturerDistributions)
+	router.Put("/registrations/:id/lecturer-distributions", m.AuthBearer, h.lecturerDistributions) // kept for older clients, records a draw like the POST
+	router.Post("/registrations/:id/lecturer-distributions", m.AuthBearer, h.lecturerDistributions)
+	router.Get("/registrations/:id/lecturer-distributions", m.AuthBearer, h.getLecturerDistributions)
+	router.Post("/registrations/:id/lecturer-distributions/:usage_id/reverse", m.AuthBearer, h.reverseLecturerDistribution)
+	router.Post("/registrations/:id/meetings", m.AuthBearerLecturer, m.AuthRole(meetingRoles), h.createRegistrationMeeting)
+	router.Get("/registrations/:id/meetings", m.AuthBearerLecturer, h.getRegistrationMeetings)
+	router.Delete("/registrations/:id/meetings/:meeting_id", m.AuthBearerLecturer, m.AuthRole(meetingRoles), h.deleteRegistrationMeeting)
+	router.Get("/meeting-attendances", m.AuthBearerLecturer, h.getAttendances)
+	router.Get("/registrations/:id/schedule", m.AuthBearerLecturer, h.getRegistrationSchedule)
+	router.Get("/lecturer-loads", m.AuthBearer, h.getLecturerLoads)
+	router.Get("/lecturers/:lecturer_id/calendar-link", m.AuthBearerLecturer, h.getLecturerCalendarLink)
+	router.Put("/lecturers/:lecturer_id/calendar-link/reset", m.AuthBearerLecturer, h.resetLecturerCalendarLink)
+	router.Get("/lecturer-calendars/:lecturer_id/sessions.ics", h.getLecturerCalendar) // public, the link is signed
+
+	router.Get("/hr-fee-split-rules", m.AuthBearer, h.getHRFeeSplitRules)
+	router.Post("/hr-fee-split-rules", m.AuthBearer, m.AuthRole(payoutRoles), h.createHRFeeSplitRule)
+	router.Post("/hr-fee-split-rules/apply", m.AuthBearer, m.AuthRole(payoutRoles), h.applyHRFeeSplitRules)
+	router.Put("/hr-fee-split-rules/:id", m.AuthBearer, m.AuthRole(payoutRoles), h.updateHRFeeSplitRule)
+	router.Delete("/hr-fee-split-rules/:id", m.AuthBearer, m.AuthRole(payoutRoles), h.deleteHRFeeSplitRule)
+
+	router.Get("/accounting-periods", m.AuthBearer, h.getAccountingPeriods)
+	router.Get("/accounting-periods/:period", m.AuthBearer, h.getAccountingPeriod)
+	router.Put("/accounting-periods/:period/close", m.AuthBearer, m.AuthRole(accountingPeriodRoles), h.closeAccountingPeriod)
+	router.Put("/accounting-periods/:period/reopen", m.AuthBearer, m.AuthRole(accountingPeriodRoles), h.reopenAccountingPeriod)
+
+	router.Get("/arrears", m.AuthBearer, h.getArrears)
+	router.Get("/timeseries", m.AuthBearer, h.getTimeseries)
 
 	router.Get("/registration-per-lecturers", m.AuthBearer, h.getRegistrationListPerLecturer)
 
 	router.Get("/lecturer-programs", m.AuthBearer, h.getLecturerPrograms)
+
+	router.Get("/commission-statements", m.AuthBearer, h.getCommissionStatements)
+	router.Post("/commission-payout-batches", m.AuthBearer, m.AuthRole(payoutRoles), h.createCommissionPayoutBatch)
+	router.Get("/commission-payout-batches", m.AuthBearer, h.getCommissionPayoutBatches)
+	router.Get("/commission-payout-batches/:id", m.AuthBearer, h.getCommissionPayoutBatch)
+	router.Put("/commission-payout-batches/:id/unlock", m.AuthBearer, m.AuthRole(payoutRoles), h.unlockCommissionPayoutBatch)
+
+	router.Post("/lecturer-statements/generate", m.AuthBearer, h.generateLecturerStatements)
+	router.Get("/lecturer-statements", m.AuthBearer, h.getLecturerStatements)
+	router.Get("/lecturer-statements/:id", m.AuthBearer, h.getLecturerStatement)
+	router.Put("/lecturer-statements/:id/approve", m.AuthBearer, m.AuthRole(payoutRoles), h.approveLecturerStatement)
+	router.Put("/lecturer-statements/:id/reopen", m.AuthBearer, m.AuthRole(payoutRoles), h.reopenLecturerStatement)
+	router.Get("/lecturer-statements/:id/payslip", m.AuthBearer, h.getLecturerPayslip)
+	router.Post("/lecturer-payout-batches", m.AuthBearer, m.AuthRole(payoutRoles), h.createLecturerPayoutBatch)
+	router.Get("/lecturer-payout-batches", m.AuthBearer, h.getLecturerPayoutBatches)
+	router.Get("/lecturer-payout-batches/:id", m.AuthBearer, h.getLecturerPayoutBatch)
+	router.Delete("/lecturer-payout-batches/:id", m.AuthBearer, m.AuthRole(payoutRoles), h.deleteLecturerPayoutBatch)
+	router.Put("/lecturer-payout-batches/:id/approve", m.AuthBearer, m.AuthRole(payoutRoles), h.approveLecturerPayoutBatch)
+	router.Put("/lecturer-payout-batches/:id/pay", m.AuthBearer, m.AuthRole(payoutRoles), h.payLecturerPayoutBatch)
+	router.Get("/lecturer-payout-batches/:id/bank-file", m.AuthBearer, h.getLecturerPayoutBankFile)
 }
 
 func (h *reportHandler) getTemplates(c *fiber.Ctx) error {
@@ -140,6 +213,91 @@ func (h *reportHandler) createTemplate(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
 }
 
+func (h *reportHandler) importTemplates(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ImportTemplatesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::importTemplates - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		log.Warn().Err(err).Msg("handler::importTemplates - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(errmsg.NewCustomErrors(400).Add("file", "file harus diisi")))
+	}
+
+	req.UserId = l.GetUserId()
+	req.Filename = file.Filename
+	req.Format = strings.ToLower(strings.TrimPrefix(filepath.Ext(file.Filename), "."))
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::importTemplates - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("handler::importTemplates - failed to open file")
+		return c.Status(fiber.StatusInternalServerError).JSON(response.Error(err))
+	}
+	defer f.Close()
+
+	req.Content, err = io.ReadAll(f)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("handler::importTemplates - failed to read file")
+		return c.Status(fiber.StatusInternalServerError).JSON(response.Error(err))
+	}
+
+	resp, err := h.service.ImportTemplates(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	switch {
+	case resp.Committed:
+		return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+	case resp.InvalidRows > 0:
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(response.Success(resp, "Terdapat baris yang tidak valid, tidak ada template yang disimpan"))
+	default:
+		return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+	}
+}
+
+func (h *reportHandler) getTemplateImportFile(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetTemplateImportFileReq)
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getTemplateImportFile - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getTemplateImportFile - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	file, err := h.service.GetTemplateImportFile(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return sendExport(c, file)
+}
+
 func (h *reportHandler) updateTemplate(c *fiber.Ctx) error {
 	var (
 		req = new(entity.UpdateTemplateGeneralReq)
@@ -176,6 +334,56 @@ func (h *reportHandler) updateTemplate(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
+func (h *reportHandler) archiveTemplate(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ArchiveTemplateReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::archiveTemplate - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.ArchiveTemplate(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) unarchiveTemplate(c *fiber.Ctx) error {
+	var (
+		req = new(entity.UnarchiveTemplateReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::unarchiveTemplate - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.UnarchiveTemplate(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
 func (h *reportHandler) getSummaries(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetSummariesReq)
@@ -204,6 +412,16 @@ func (h *reportHandler) getSummaries(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if entity.IsExportFormat(req.Format) {
+		file, err := h.service.ExportSummaries(c.Context(), req)
+		if err != nil {
+			code, errs := errmsg.Errors[error](err)
+			return c.Status(code).JSON(response.Error(errs))
+		}
+
+		return sendExport(c, file)
+	}
+
 	resp, err := h.service.GetSummaries(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
@@ -213,6 +431,78 @@ func (h *reportHandler) getSummaries(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
+func (h *reportHandler) getArrears(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetArrearsReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getArrears - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getArrears - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getArrears - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetArrears(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) getTimeseries(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetTimeseriesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getTimeseries - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getTimeseries - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if err := req.Validate(); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getTimeseries - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetTimeseries(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
 func (h *reportHandler) createRegistrations(c *fiber.Ctx) error {
 	var (
 		req = new(entity.CreateRegistrationsReq)
@@ -226,6 +516,9 @@ func (h *reportHandler) createRegistrations(c *fiber.Ctx) error {
 	}
 
 	req.UserId = l.GetUserId()
+	req.Mode = c.Query("mode")
+	req.BillingPeriod = c.Query("billing_period")
+	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("req", req).Msg("handler::createRegistrations - invalid request")
@@ -233,13 +526,17 @@ func (h *reportHandler) createRegistrations(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	err := h.service.CreateRegistrations(c.Context(), req)
+	resp, err := h.service.CreateRegistrations(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(response.Success(nil, ""))
+	if resp.TotalFailed > 0 || resp.TotalSkipped > 0 {
+		return c.Status(fiber.StatusMultiStatus).JSON(response.Success(resp, ""))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
 }
 
 func (h *reportHandler) copyRegistrations(c *fiber.Ctx) error {
@@ -255,6 +552,9 @@ func (h *reportHandler) copyRegistrations(c *fiber.Ctx) error {
 	}
 
 	req.UserId = l.GetUserId()
+	req.Mode = c.Query("mode")
+	req.BillingPeriod = c.Query("billing_period")
+	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("req", req).Msg("handler::copyRegistrations - invalid request")
@@ -262,13 +562,17 @@ func (h *reportHandler) copyRegistrations(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	err := h.service.CopyRegistrations(c.Context(), req)
+	resp, err := h.service.CopyRegistrations(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(response.Success(nil, ""))
+	if resp.TotalFailed > 0 || resp.TotalSkipped > 0 {
+		return c.Status(fiber.StatusMultiStatus).JSON(response.Success(resp, ""))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
 }
 
 func (h *reportHandler) updateRegistration(c *fiber.Ctx) error {
@@ -334,6 +638,16 @@ func (h *reportHandler) getRegistrations(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if entity.IsExportFormat(req.Format) {
+		file, err := h.service.ExportRegistrations(c.Context(), req)
+		if err != nil {
+			code, errs := errmsg.Errors[error](err)
+			return c.Status(code).JSON(response.Error(errs))
+		}
+
+		return sendExport(c, file)
+	}
+
 	resp, err := h.service.GetRegistrations(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
@@ -368,6 +682,289 @@ func (h *reportHandler) getRegistration(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
 
+func (h *reportHandler) deleteRegistration(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteRegistrationReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::deleteRegistration - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::deleteRegistration - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteRegistration(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) restoreRegistration(c *fiber.Ctx) error {
+	var (
+		req = new(entity.RestoreRegistrationReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.Id = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::restoreRegistration - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.RestoreRegistration(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) getDeletedRegistrations(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetDeletedRegistrationsReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::getDeletedRegistrations - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getDeletedRegistrations - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetDeletedRegistrations(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) createRegistrationPayment(c *fiber.Ctx) error {
+	var (
+		req = new(entity.CreateRegistrationPaymentReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::createRegistrationPayment - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+	req.SetDefault()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::createRegistrationPayment - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.CreateRegistrationPayment(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) getRegistrationPayments(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetRegistrationPaymentsReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getRegistrationPayments - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetRegistrationPayments(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) deleteRegistrationPayment(c *fiber.Ctx) error {
+	var (
+		req = new(entity.DeleteRegistrationPaymentReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+	req.Id = c.Params("payment_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::deleteRegistrationPayment - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	err := h.service.DeleteRegistrationPayment(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+}
+
+func (h *reportHandler) getInvoice(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetInvoiceReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getInvoice - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetInvoice(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) generateInvoices(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GenerateInvoicesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::generateInvoices - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::generateInvoices - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GenerateInvoices(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) issueReceipt(c *fiber.Ctx) error {
+	var (
+		req = new(entity.IssueReceiptReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			log.Warn().Err(err).Msg("handler::issueReceipt - invalid request")
+			return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+		}
+	}
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::issueReceipt - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.IssueReceipt(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	if resp.IsReissue {
+		return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) verifyReceipt(c *fiber.Ctx) error {
+	var (
+		req = new(entity.VerifyReceiptReq)
+		v   = adapter.Adapters.Validator
+	)
+
+	if err := c.QueryParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::verifyReceipt - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::verifyReceipt - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.VerifyReceipt(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
 func (h *reportHandler) getLecturerPrograms(c *fiber.Ctx) error {
 	var (
 		req = new(entity.GetLecturerProgramsReq)
@@ -442,6 +1039,7 @@ func (h *reportHandler) lecturerDistributions(c *fiber.Ctx) error {
 
 	req.UserId = l.GetUserId()
 	req.RegistrationId = c.Params("id")
+	req.SetDefault()
 
 	if err := v.Validate(req); err != nil {
 		log.Warn().Err(err).Any("req", req).Msg("handler::lecturerDistributions - invalid request")
@@ -455,13 +1053,69 @@ func (h *reportHandler) lecturerDistributions(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	err := h.service.UseHRfeeForLecturer(c.Context(), req)
+	resp, err := h.service.UseHRfeeForLecturer(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(response.Success(nil, ""))
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) getLecturerDistributions(c *fiber.Ctx) error {
+	var (
+		req = new(entity.GetMentorFeeUsagesReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::getLecturerDistributions - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.GetMentorFeeUsages(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
+}
+
+func (h *reportHandler) reverseLecturerDistribution(c *fiber.Ctx) error {
+	var (
+		req = new(entity.ReverseMentorFeeUsageReq)
+		v   = adapter.Adapters.Validator
+		l   = m.GetLocals(c)
+	)
+
+	if err := c.BodyParser(req); err != nil {
+		log.Warn().Err(err).Msg("handler::reverseLecturerDistribution - invalid request")
+		return c.Status(fiber.StatusBadRequest).JSON(response.Error(err))
+	}
+
+	req.UserId = l.GetUserId()
+	req.RegistrationId = c.Params("id")
+	req.Id = c.Params("usage_id")
+
+	if err := v.Validate(req); err != nil {
+		log.Warn().Err(err).Any("req", req).Msg("handler::reverseLecturerDistribution - invalid request")
+		code, errs := errmsg.Errors(err, req)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	resp, err := h.service.ReverseMentorFeeUsage(c.Context(), req)
+	if err != nil {
+		code, errs := errmsg.Errors[error](err)
+		return c.Status(code).JSON(response.Error(errs))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(response.Success(resp, ""))
 }
 
 func (h *reportHandler) getRegistrationListPerLecturer(c *fiber.Ctx) error {
@@ -485,6 +1139,16 @@ func (h *reportHandler) getRegistrationListPerLecturer(c *fiber.Ctx) error {
 		return c.Status(code).JSON(response.Error(errs))
 	}
 
+	if entity.IsExportFormat(req.Format) {
+		file, err := h.service.ExportRegistrationsPerLecturer(c.Context(), req)
+		if err != nil {
+			code, errs := errmsg.Errors[error](err)
+			return c.Status(code).JSON(response.Error(errs))
+		}
+
+		return sendExport(c, file)
+	}
+
 	resp, err := h.service.GetRegistrationsPerLecturer(c.Context(), req)
 	if err != nil {
 		code, errs := errmsg.Errors[error](err)
@@ -493,3 +1157,11 @@ func (h *reportHandler) getRegistrationListPerLecturer(c *fiber.Ctx) error {
 
 	return c.Status(fiber.StatusOK).JSON(response.Success(resp, ""))
 }
+
+// sendExport writes an exported report as a file download
+func sendExport(c *fiber.Ctx, file *entity.ExportFile) error {
+	c.Attachment(file.Filename)
+	c.Set(fiber.HeaderContentType, file.ContentType)
+
+	return c.Status(fiber.StatusOK).Send(file.Content)
+}
@@ -10,20 +10,92 @@ type ReportRepository interface {
 	GetTemplate(ctx context.Context, req *entity.GetTemplateReq) (*entity.GetTemplateResp, error)
 	CreateTemplate(ctx context.Context, req *entity.CreateTemplateReq) (*entity.CreateTemplateResp, error)
 	UpdateTemplate(ctx context.Context, req *entity.UpdateTemplateGeneralReq) (*entity.UpdateTemplateResp, error)
+	ArchiveTemplate(ctx context.Context, req *entity.ArchiveTemplateReq) error
+	UnarchiveTemplate(ctx context.Context, req *entity.UnarchiveTemplateReq) error
+	GetTemplateImportReferences(ctx context.Context, keys []string) (*entity.TemplateImportReferences, error)
+	ImportTemplates(ctx context.Context, req *entity.ImportTemplatesReq) (*entity.ImportTemplatesResp, error)
 
-	CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) error
-	CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) error
+	CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) (*entity.RegistrationBatchResp, error)
+	CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) (*entity.RegistrationBatchResp, error)
+	GenerateRegistrations(ctx context.Context, req *entity.GenerateRegistrationsReq) (*entity.GenerateRegistrationsResp, error)
 	GetRegistrations(ctx context.Context, req *entity.GetRegistrationsReq) (*entity.GetRegistrationsResp, error)
 	GetRegistration(ctx context.Context, req *entity.GetRegistrationReq) (*entity.GetRegistrationResp, error)
 	UpdateRegistration(ctx context.Context, req *entity.UpdateRegistrationReq) (*entity.UpdateRegistrationResp, error)
+	DeleteRegistration(ctx context.Context, req *entity.DeleteRegistrationReq) error
+	RestoreRegistration(ctx context.Context, req *entity.RestoreRegistrationReq) error
+	GetDeletedRegistrations(ctx context.Context, req *entity.GetDeletedRegistrationsReq) (*entity.GetDeletedRegistrationsResp, error)
+
+	CreateRegistrationPayment(ctx context.Context, req *entity.CreateRegistrationPaymentReq) (*entity.CreateRegistrationPaymentResp, error)
+	GetRegistrationPayments(ctx context.Context, req *entity.GetRegistrationPaymentsReq) (*entity.GetRegistrationPaymentsResp, error)
+	DeleteRegistrationPayment(ctx context.Context, req *entity.DeleteRegistrationPaymentReq) error
 
 	DistributeHRFee(ctx context.Context, req *entity.HRDistributionReq) error
-	UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) error
+	UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) (*entity.UseHRfeeForLecturerResp, error)
+	GetMentorFeeUsages(ctx context.Context, req *entity.GetMentorFeeUsagesReq) (*entity.GetMentorFeeUsagesResp, error)
+	ReverseMentorFeeUsage(ctx context.Context, req *entity.ReverseMentorFeeUsageReq) (*entity.ReverseMentorFeeUsageResp, error)
 
 	GetSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.GetSummariesResp, error)
+	GetArrears(ctx context.Context, req *entity.GetArrearsReq) (*entity.GetArrearsResp, error)
+	GetTimeseries(ctx context.Context, req *entity.GetTimeseriesReq) ([]entity.TimeseriesBucket, error)
+	GetRegistrationFeeSnapshots(ctx context.Context, req *entity.RecomputeFeesReq) ([]entity.RegistrationFeeSnapshot, error)
+	GetInvoiceData(ctx context.Context, req *entity.GetInvoiceReq) (*entity.InvoiceData, error)
+	GetInvoicesDataByPeriod(ctx context.Context, req *entity.GenerateInvoicesReq) ([]entity.InvoiceData, error)
+	IssueReceipt(ctx context.Context, req *entity.IssueReceiptReq) (*entity.ReceiptData, error)
+	GetReceiptByNumber(ctx context.Context, req *entity.VerifyReceiptReq) (*entity.ReceiptData, error)
 	GetLecturerPrograms(ctx context.Context, req *entity.GetLecturerProgramsReq) (*entity.GetLecturerProgramsResp, error)
 
 	GetRegistrationsPerLecturer(ctx context.Context, req *entity.GetRegistrationListPerLecturerReq) (*entity.GetRegistrationListPerLecturerResp, error)
+
+	GetCommissionStatementRows(ctx context.Context, req *entity.GetCommissionStatementsReq) ([]entity.CommissionStatementRow, error)
+	CreateCommissionPayoutBatch(ctx context.Context, req *entity.CreateCommissionPayoutBatchReq) (*entity.CreateCommissionPayoutBatchResp, error)
+	GetCommissionPayoutBatches(ctx context.Context, req *entity.GetCommissionPayoutBatchesReq) (*entity.GetCommissionPayoutBatchesResp, error)
+	GetCommissionPayoutBatch(ctx context.Context, req *entity.GetCommissionPayoutBatchReq) (*entity.GetCommissionPayoutBatchResp, error)
+	UnlockCommissionPayoutBatch(ctx context.Context, req *entity.UnlockCommissionPayoutBatchReq) error
+
+	GenerateLecturerStatements(ctx context.Context, req *entity.GenerateLecturerStatementsReq) (*entity.GenerateLecturerStatementsResp, error)
+	GetLecturerStatements(ctx context.Context, req *entity.GetLecturerStatementsReq) (*entity.GetLecturerStatementsResp, error)
+	GetLecturerStatement(ctx context.Context, req *entity.GetLecturerStatementReq) (*entity.GetLecturerStatementResp, error)
+	ApproveLecturerStatement(ctx context.Context, req *entity.ApproveLecturerStatementReq) error
+	ReopenLecturerStatement(ctx context.Context, req *entity.ReopenLecturerStatementReq) error
+	CreateLecturerPayoutBatch(ctx context.Context, req *entity.CreateLecturerPayoutBatchReq) (*entity.CreateLecturerPayoutBatchResp, error)
+	GetLecturerPayoutBatches(ctx context.Context, req *entity.GetLecturerPayoutBatchesReq) (*entity.GetLecturerPayoutBatchesResp, error)
+	GetLecturerPayoutBatch(ctx context.Context, req *entity.GetLecturerPayoutBatchReq) (*entity.GetLecturerPayoutBatchResp, error)
+	ApproveLecturerPayoutBatch(ctx context.Context, req *entity.ApproveLecturerPayoutBatchReq) error
+	PayLecturerPayoutBatch(ctx context.Context, req *entity.PayLecturerPayoutBatchReq) error
+	DeleteLecturerPayoutBatch(ctx context.Context, req *entity.DeleteLecturerPayoutBatchReq) error
+	GetLecturerPayoutBankFile(ctx context.Context, req *entity.GetLecturerPayoutBankFileReq) (*entity.LecturerPayoutBankFile, error)
+
+	GetHRFeeSplitRules(ctx context.Context, req *entity.GetHRFeeSplitRulesReq) (*entity.GetHRFeeSplitRulesResp, error)
+	CreateHRFeeSplitRule(ctx context.Context, req *entity.CreateHRFeeSplitRuleReq) (*entity.CreateHRFeeSplitRuleResp, error)
+	UpdateHRFeeSplitRule(ctx context.Context, req *entity.UpdateHRFeeSplitRuleReq) error
+	DeleteHRFeeSplitRule(ctx context.Context, req *entity.DeleteHRFeeSplitRuleReq) error
+	ApplyHRFeeSplitRules(ctx context.Context, req *entity.ApplyHRFeeSplitRulesReq) (*entity.ApplyHRFeeSplitRulesResp, error)
+
+	GetHistory(ctx context.Context, req *entity.GetHistoryReq) (*entity.GetHistoryResp, error)
+
+	GetAccountingPeriods(ctx context.Context, req *entity.GetAccountingPeriodsReq) (*entity.GetAccountingPeriodsResp, error)
+	GetAccountingPeriod(ctx context.Context, req *entity.GetAccountingPeriodReq) (*entity.GetAccountingPeriodResp, error)
+	CloseAccountingPeriod(ctx context.Context, req *entity.CloseAccountingPeriodReq) error
+	ReopenAccountingPeriod(ctx context.Context, req *entity.ReopenAccountingPeriodReq) error
+
+	SubmitRegistration(ctx context.Context, req *entity.SubmitRegistrationReq) error
+	ReviewRegistration(ctx context.Context, req *entity.ReviewRegistrationReq) error
+	GetTemplateFeeChanges(ctx context.Context, req *entity.GetTemplateFeeChangesReq) (*entity.GetTemplateFeeChangesResp, error)
+	ReviewTemplateFeeChange(ctx context.Context, req *entity.ReviewTemplateFeeChangeReq) error
+
+	CreateRegistrationMeeting(ctx context.Context, req *entity.CreateRegistrationMeetingReq) (*entity.CreateRegistrationMeetingResp, error)
+	GetRegistrationMeetings(ctx context.Context, req *entity.GetRegistrationMeetingsReq) (*entity.GetRegistrationMeetingsResp, error)
+	DeleteRegistrationMeeting(ctx context.Context, req *entity.DeleteRegistrationMeetingReq) (*entity.RegistrationMeetingCount, error)
+	GetAttendances(ctx context.Context, req *entity.GetAttendancesReq) (*entity.GetAttendancesResp, error)
+
+	GetRegistrationSchedule(ctx context.Context, req *entity.GetRegistrationScheduleReq) (*entity.GetRegistrationScheduleResp, error)
+	GetLecturerLoads(ctx context.Context, req *entity.GetLecturerLoadsReq) (*entity.GetLecturerLoadsResp, error)
+	GetCalendarLecturer(ctx context.Context, lecturerId, userId string) (*entity.CalendarLecturer, error)
+	ResetCalendarVersion(ctx context.Context, lecturerId string) (int, error)
+	GetLecturerSessions(ctx context.Context, req *entity.GetLecturerCalendarReq) ([]entity.ScheduledSession, error)
+
+	GetTemplatePropagation(ctx context.Context, req *entity.GetTemplatePropagationReq) (*entity.GetTemplatePropagationResp, error)
+	PropagateProgramFees(ctx context.Context, req *entity.PropagateProgramFeesReq) (*entity.PropagateProgramFeesResp, error)
 }
 
 type ReportService interface {
@@ -31,18 +103,94 @@ type ReportService interface {
 	GetTemplate(ctx context.Context, req *entity.GetTemplateReq) (*entity.GetTemplateResp, error)
 	CreateTemplate(ctx context.Context, req *entity.CreateTemplateReq) (*entity.CreateTemplateResp, error)
 	UpdateTemplate(ctx context.Context, req *entity.UpdateTemplateGeneralReq) (*entity.UpdateTemplateResp, error)
+	ArchiveTemplate(ctx context.Context, req *entity.ArchiveTemplateReq) error
+	UnarchiveTemplate(ctx context.Context, req *entity.UnarchiveTemplateReq) error
+	GetTemplateImportFile(ctx context.Context, req *entity.GetTemplateImportFileReq) (*entity.ExportFile, error)
+	ImportTemplates(ctx context.Context, req *entity.ImportTemplatesReq) (*entity.ImportTemplatesResp, error)
 
-	CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) error
-	CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) error
+	CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) (*entity.RegistrationBatchResp, error)
+	CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) (*entity.RegistrationBatchResp, error)
+	GenerateRegistrations(ctx context.Context, req *entity.GenerateRegistrationsReq) (*entity.GenerateRegistrationsResp, error)
 	GetRegistrations(ctx context.Context, req *entity.GetRegistrationsReq) (*entity.GetRegistrationsResp, error)
+	ExportRegistrations(ctx context.Context, req *entity.GetRegistrationsReq) (*entity.ExportFile, error)
 	GetRegistration(ctx context.Context, req *entity.GetRegistrationReq) (*entity.GetRegistrationResp, error)
 	UpdateRegistration(ctx context.Context, req *entity.UpdateRegistrationReq) (*entity.UpdateRegistrationResp, error)
+	DeleteRegistration(ctx context.Context, req *entity.DeleteRegistrationReq) error
+	RestoreRegistration(ctx context.Context, req *entity.RestoreRegistrationReq) error
+	GetDeletedRegistrations(ctx context.Context, req *entity.GetDeletedRegistrationsReq) (*entity.GetDeletedRegistrationsResp, error)
+
+	CreateRegistrationPayment(ctx context.Context, req *entity.CreateRegistrationPaymentReq) (*entity.CreateRegistrationPaymentResp, error)
+	GetRegistrationPayments(ctx context.Context, req *entity.GetRegistrationPaymentsReq) (*entity.GetRegistrationPaymentsResp, error)
+	DeleteRegistrationPayment(ctx context.Context, req *entity.DeleteRegistrationPaymentReq) error
 
 	DistributeHRFee(ctx context.Context, req *entity.HRDistributionReq) error
-	UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) error
+	UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) (*entity.UseHRfeeForLecturerResp, error)
+	GetMentorFeeUsages(ctx context.Context, req *entity.GetMentorFeeUsagesReq) (*entity.GetMentorFeeUsagesResp, error)
+	ReverseMentorFeeUsage(ctx context.Context, req *entity.ReverseMentorFeeUsageReq) (*entity.ReverseMentorFeeUsageResp, error)
 
 	GetSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.GetSummariesResp, error)
+	ExportSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.ExportFile, error)
+	GetArrears(ctx context.Context, req *entity.GetArrearsReq) (*entity.GetArrearsResp, error)
+	GetTimeseries(ctx context.Context, req *entity.GetTimeseriesReq) (*entity.GetTimeseriesResp, error)
+	RecomputeFees(ctx context.Context, req *entity.RecomputeFeesReq) (*entity.RecomputeFeesResp, error)
+	GetInvoice(ctx context.Context, req *entity.GetInvoiceReq) (*entity.InvoiceResp, error)
+	GenerateInvoices(ctx context.Context, req *entity.GenerateInvoicesReq) (*entity.GenerateInvoicesResp, error)
+	IssueReceipt(ctx context.Context, req *entity.IssueReceiptReq) (*entity.IssueReceiptResp, error)
+	VerifyReceipt(ctx context.Context, req *entity.VerifyReceiptReq) (*entity.VerifyReceiptResp, error)
 	GetLecturerPrograms(ctx context.Context, req *entity.GetLecturerProgramsReq) (*entity.GetLecturerProgramsResp, error)
 
 	GetRegistrationsPerLecturer(ctx context.Context, req *entity.GetRegistrationListPerLecturerReq) (*entity.GetRegistrationListPerLecturerResp, error)
+	ExportRegistrationsPerLecturer(ctx context.Context, req *entity.GetRegistrationListPerLecturerReq) (*entity.ExportFile, error)
+
+	GetCommissionStatements(ctx context.Context, req *entity.GetCommissionStatementsReq) (*entity.GetCommissionStatementsResp, error)
+	CreateCommissionPayoutBatch(ctx context.Context, req *entity.CreateCommissionPayoutBatchReq) (*entity.CreateCommissionPayoutBatchResp, error)
+	GetCommissionPayoutBatches(ctx context.Context, req *entity.GetCommissionPayoutBatchesReq) (*entity.GetCommissionPayoutBatchesResp, error)
+	GetCommissionPayoutBatch(ctx context.Context, req *entity.GetCommissionPayoutBatchReq) (*entity.GetCommissionPayoutBatchResp, error)
+	UnlockCommissionPayoutBatch(ctx context.Context, req *entity.UnlockCommissionPayoutBatchReq) error
+
+	GenerateLecturerStatements(ctx context.Context, req *entity.GenerateLecturerStatementsReq) (*entity.GenerateLecturerStatementsResp, error)
+	GetLecturerStatements(ctx context.Context, req *entity.GetLecturerStatementsReq) (*entity.GetLecturerStatementsResp, error)
+	GetLecturerStatement(ctx context.Context, req *entity.GetLecturerStatementReq) (*entity.GetLecturerStatementResp, error)
+	ApproveLecturerStatement(ctx context.Context, req *entity.ApproveLecturerStatementReq) error
+	ReopenLecturerStatement(ctx context.Context, req *entity.ReopenLecturerStatementReq) error
+	GetLecturerPayslip(ctx context.Context, req *entity.GetLecturerPayslipReq) (*entity.LecturerPayslipResp, error)
+	CreateLecturerPayoutBatch(ctx context.Context, req *entity.CreateLecturerPayoutBatchReq) (*entity.CreateLecturerPayoutBatchResp, error)
+	GetLecturerPayoutBatches(ctx context.Context, req *entity.GetLecturerPayoutBatchesReq) (*entity.GetLecturerPayoutBatchesResp, error)
+	GetLecturerPayoutBatch(ctx context.Context, req *entity.GetLecturerPayoutBatchReq) (*entity.GetLecturerPayoutBatchResp, error)
+	ApproveLecturerPayoutBatch(ctx context.Context, req *entity.ApproveLecturerPayoutBatchReq) error
+	PayLecturerPayoutBatch(ctx context.Context, req *entity.PayLecturerPayoutBatchReq) error
+	DeleteLecturerPayoutBatch(ctx context.Context, req *entity.DeleteLecturerPayoutBatchReq) error
+	GetLecturerPayoutBankFile(ctx context.Context, req *entity.GetLecturerPayoutBankFileReq) (*entity.ExportFile, error)
+
+	GetHRFeeSplitRules(ctx context.Context, req *entity.GetHRFeeSplitRulesReq) (*entity.GetHRFeeSplitRulesResp, error)
+	CreateHRFeeSplitRule(ctx context.Context, req *entity.CreateHRFeeSplitRuleReq) (*entity.CreateHRFeeSplitRuleResp, error)
+	UpdateHRFeeSplitRule(ctx context.Context, req *entity.UpdateHRFeeSplitRuleReq) error
+	DeleteHRFeeSplitRule(ctx context.Context, req *entity.DeleteHRFeeSplitRuleReq) error
+	ApplyHRFeeSplitRules(ctx context.Context, req *entity.ApplyHRFeeSplitRulesReq) (*entity.ApplyHRFeeSplitRulesResp, error)
+
+	GetHistory(ctx context.Context, req *entity.GetHistoryReq) (*entity.GetHistoryResp, error)
+
+	GetAccountingPeriods(ctx context.Context, req *entity.GetAccountingPeriodsReq) (*entity.GetAccountingPeriodsResp, error)
+	GetAccountingPeriod(ctx context.Context, req *entity.GetAccountingPeriodReq) (*entity.GetAccountingPeriodResp, error)
+	CloseAccountingPeriod(ctx context.Context, req *entity.CloseAccountingPeriodReq) error
+	ReopenAccountingPeriod(ctx context.Context, req *entity.ReopenAccountingPeriodReq) error
+
+	SubmitRegistration(ctx context.Context, req *entity.SubmitRegistrationReq) error
+	ReviewRegistration(ctx context.Context, req *entity.ReviewRegistrationReq) error
+	GetTemplateFeeChanges(ctx context.Context, req *entity.GetTemplateFeeChangesReq) (*entity.GetTemplateFeeChangesResp, error)
+	ReviewTemplateFeeChange(ctx context.Context, req *entity.ReviewTemplateFeeChangeReq) error
+
+	CreateRegistrationMeeting(ctx context.Context, req *entity.CreateRegistrationMeetingReq) (*entity.CreateRegistrationMeetingResp, error)
+	GetRegistrationMeetings(ctx context.Context, req *entity.GetRegistrationMeetingsReq) (*entity.GetRegistrationMeetingsResp, error)
+	DeleteRegistrationMeeting(ctx context.Context, req *entity.DeleteRegistrationMeetingReq) (*entity.RegistrationMeetingCount, error)
+	GetAttendances(ctx context.Context, req *entity.GetAttendancesReq) (*entity.GetAttendancesResp, error)
+
+	GetRegistrationSchedule(ctx context.Context, req *entity.GetRegistrationScheduleReq) (*entity.GetRegistrationScheduleResp, error)
+	GetLecturerLoads(ctx context.Context, req *entity.GetLecturerLoadsReq) (*entity.GetLecturerLoadsResp, error)
+	GetLecturerCalendarLink(ctx context.Context, req *entity.GetLecturerCalendarLinkReq) (*entity.GetLecturerCalendarLinkResp, error)
+	ResetLecturerCalendarLink(ctx context.Context, req *entity.ResetLecturerCalendarLinkReq) (*entity.GetLecturerCalendarLinkResp, error)
+	GetLecturerCalendar(ctx context.Context, req *entity.GetLecturerCalendarReq) (*entity.ExportFile, error)
+
+	GetTemplatePropagation(ctx context.Context, req *entity.GetTemplatePropagationReq) (*entity.GetTemplatePropagationResp, error)
+	PropagateProgramFees(ctx context.Context, req *entity.PropagateProgramFeesReq) (*entity.PropagateProgramFeesResp, error)
 }
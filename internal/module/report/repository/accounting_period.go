@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	accountingPeriodColumnsSQL = `
+		ap.id,
+		TO_CHAR(ap.period, 'YYYY-MM') AS period,
+		ap.status,
+		ap.closed_at,
+		ap.closed_by,
+		cu.name AS closed_by_name,
+		ap.close_reason,
+		ap.reopened_at,
+		ap.reopened_by,
+		ru.name AS reopened_by_name,
+		ap.reopen_reason
+	`
+
+	accountingPeriodJoinSQL = `
+		LEFT JOIN
+			users cu
+			ON ap.closed_by = cu.id
+		LEFT JOIN
+			users ru
+			ON ap.reopened_by = ru.id
+	`
+)
+
+func (r *reportRepo) GetAccountingPeriods(ctx context.Context, req *entity.GetAccountingPeriodsReq) (*entity.GetAccountingPeriodsResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.AccountingPeriod
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetAccountingPeriodsResp)
+		args = make([]any, 0, 3)
+	)
+	resp.Items = make([]entity.AccountingPeriod, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			` + accountingPeriodColumnsSQL + `
+		FROM
+			accounting_periods ap
+		` + accountingPeriodJoinSQL + `
+		WHERE
+			1 = 1
+	`
+
+	if req.Status != "" {
+		query += ` AND ap.status = ?`
+		args = append(args, req.Status)
+	}
+
+	query += ` ORDER BY ap.period DESC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetAccountingPeriods - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.AccountingPeriod)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// GetAccountingPeriod returns the period as open when it has never been closed
+func (r *reportRepo) GetAccountingPeriod(ctx context.Context, req *entity.GetAccountingPeriodReq) (*entity.GetAccountingPeriodResp, error) {
+	var resp = new(entity.GetAccountingPeriodResp)
+
+	query := `
+		SELECT
+			` + accountingPeriodColumnsSQL + `,
+			ap.summary
+		FROM
+			accounting_periods ap
+		` + accountingPeriodJoinSQL + `
+		WHERE
+			ap.period = TO_DATE(?, 'YYYY-MM')
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), req.Period)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			resp.Period = req.Period
+			resp.Status = entity.AccountingPeriodStatusOpen
+			return resp, nil
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetAccountingPeriod - failed to fetch data")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// CloseAccountingPeriod takes the summary of the period after locking it
+// exclusively, changes to its registrations hold the same lock shared so none
+// can happen between the snapshot and the closing
+func (r *reportRepo) CloseAccountingPeriod(ctx context.Context, req *entity.CloseAccountingPeriodReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CloseAccountingPeriod - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	id, status, err := lockAccountingPeriod(ctx, tx, req.Period)
+	if err != nil {
+		return err
+	}
+
+	if status == entity.AccountingPeriodStatusClosed {
+		log.Warn().Any("req", req).Msg("repo::CloseAccountingPeriod - period is already closed")
+		return errmsg.NewCustomErrors(409).SetMessage("Periode akuntansi sudah ditutup")
+	}
+
+	if id == "" {
+		id = ulid.Make().String()
+	}
+
+	snapshot, err := getSummaries(ctx, tx, req.SummariesReq())
+	if err != nil {
+		return err
+	}
+	if req.Summarize != nil {
+		req.Summarize(snapshot)
+	}
+
+	summary, err := json.Marshal(snapshot)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CloseAccountingPeriod - failed to encode summary")
+		return err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.AccountingPeriod, id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO accounting_periods (
+			id,
+			period,
+			status,
+			closed_at,
+			closed_by,
+			close_reason,
+			summary
+		) VALUES (?, TO_DATE(?, 'YYYY-MM'), ?, NOW(), ?, ?, ?::JSONB)
+		ON CONFLICT (period) DO UPDATE SET
+			status = EXCLUDED.status,
+			closed_at = EXCLUDED.closed_at,
+			closed_by = EXCLUDED.closed_by,
+			close_reason = EXCLUDED.close_reason,
+			summary = EXCLUDED.summary,
+			updated_at = NOW()
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		id, req.Period, entity.AccountingPeriodStatusClosed, req.UserId, req.Reason, string(summary),
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CloseAccountingPeriod - failed to close period")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CloseAccountingPeriod - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// ReopenAccountingPeriod keeps the closing summary so it can be compared with
+// the numbers after the period is closed again
+func (r *reportRepo) ReopenAccountingPeriod(ctx context.Context, req *entity.ReopenAccountingPeriodReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenAccountingPeriod - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	id, status, err := lockAccountingPeriod(ctx, tx, req.Period)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.AccountingPeriodStatusClosed {
+		log.Warn().Any("req", req).Msg("repo::ReopenAccountingPeriod - period is not closed")
+		return errmsg.NewCustomErrors(409).SetMessage("Periode akuntansi belum ditutup")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.AccountingPeriod, id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			accounting_periods
+		SET
+			status = ?,
+			reopened_at = NOW(),
+			reopened_by = ?,
+			reopen_reason = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), entity.AccountingPeriodStatusOpen, req.UserId, req.Reason, id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenAccountingPeriod - failed to reopen period")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenAccountingPeriod - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// lockAccountingPeriod takes the period lock exclusively and returns the id and
+// status of the period, both are empty when the period has never been closed
+func lockAccountingPeriod(ctx context.Context, tx *sqlx.Tx, period string) (id, status string, err error) {
+	var row struct {
+		Id     string `db:"id"`
+		Status string `db:"status"`
+	}
+
+	if err = lockPeriod(ctx, tx, period, true); err != nil {
+		return "", "", err
+	}
+
+	query := `
+		SELECT
+			id,
+			status
+		FROM
+			accounting_periods
+		WHERE
+			period = TO_DATE(?, 'YYYY-MM')
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &row, tx.Rebind(query), period)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", "", nil
+		}
+		log.Error().Err(err).Str("period", period).Msg("repo::lockAccountingPeriod - failed to fetch period")
+		return "", "", err
+	}
+
+	return row.Id, row.Status, nil
+}
@@ -0,0 +1,365 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+func (r *reportRepo) CreateCommissionPayoutBatch(ctx context.Context, req *entity.CreateCommissionPayoutBatchReq) (*entity.CreateCommissionPayoutBatchResp, error) {
+	type dao struct {
+		RegistrationId        string          `db:"registration_id"`
+		MarketerId            string          `db:"marketer_id"`
+		MarketerCommissionFee decimal.Decimal `db:"marketer_commission_fee"`
+		MarketerGiftsFee      decimal.Decimal `db:"marketer_gifts_fee"`
+	}
+
+	var (
+		data = make([]dao, 0)
+		resp = new(entity.CreateCommissionPayoutBatchResp)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the registrations are locked so they can not be edited while the batch is created
+	query := `
+		SELECT
+			pr.id AS registration_id,
+			pr.marketer_id,
+			pr.marketer_commission_fee,
+			pr.marketer_gifts_fee
+		FROM
+			program_registrations pr
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+			AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND pr.marketer_id = ANY(?)
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					commission_payout_items cpi
+				WHERE
+					cpi.registration_id = pr.id
+					AND cpi.unlocked_at IS NULL
+			)
+		ORDER BY
+			pr.marketer_id ASC, pr.id ASC
+		FOR UPDATE OF pr
+	`
+
+	err = tx.SelectContext(ctx, &data, tx.Rebind(query), req.BillingPeriod, pq.Array(req.MarketerIds))
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - failed to fetch unpaid registrations")
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		log.Warn().Any("req", req).Msg("repo::CreateCommissionPayoutBatch - no unpaid registrations")
+		return nil, errmsg.NewCustomErrors(422).SetMessage("Tidak ada komisi yang belum dibayar untuk marketer tersebut di periode ini")
+	}
+
+	resp.Id = ulid.Make().String()
+	resp.TotalAmount = decimal.Zero
+	for _, item := range data {
+		resp.TotalAmount = resp.TotalAmount.Add(item.MarketerCommissionFee).Add(item.MarketerGiftsFee)
+	}
+	resp.TotalItems = len(data)
+
+	query = `
+		INSERT INTO commission_payout_batches (
+			id,
+			user_id,
+			billing_period,
+			paid_at,
+			reference,
+			notes,
+			total_amount
+		) VALUES (?, ?, TO_DATE(?, 'YYYY-MM'), TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP AT TIME ZONE ?, ?, ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		resp.Id,
+		req.UserId,
+		req.BillingPeriod,
+		req.PaidAt,
+		req.Timezone,
+		req.Reference,
+		req.Notes,
+		resp.TotalAmount,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - failed to insert batch")
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO commission_payout_items (
+			id,
+			batch_id,
+			registration_id,
+			marketer_id,
+			marketer_commission_fee,
+			marketer_gifts_fee,
+			amount
+		) VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	for _, item := range data {
+		_, err = tx.ExecContext(ctx, tx.Rebind(query),
+			ulid.Make().String(),
+			resp.Id,
+			item.RegistrationId,
+			item.MarketerId,
+			item.MarketerCommissionFee,
+			item.MarketerGiftsFee,
+			item.MarketerCommissionFee.Add(item.MarketerGiftsFee),
+		)
+		if err != nil {
+			// a concurrent batch took the same registration first
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				log.Warn().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - registration already in another batch")
+				return nil, errmsg.NewCustomErrors(409).SetMessage("Sebagian registrasi sudah masuk batch pembayaran komisi lain, silakan coba lagi")
+			}
+			log.Error().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - failed to insert item")
+			return nil, err
+		}
+	}
+
+	if err = audit.Created(ctx, tx, audit.CommissionPayoutBatch, resp.Id); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateCommissionPayoutBatch - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetCommissionPayoutBatches(ctx context.Context, req *entity.GetCommissionPayoutBatchesReq) (*entity.GetCommissionPayoutBatchesResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.CommissionPayoutBatch
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetCommissionPayoutBatchesResp)
+		args = make([]any, 0, 3)
+	)
+	resp.Items = make([]entity.CommissionPayoutBatch, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			` + commissionPayoutBatchColumnsSQL + `
+		FROM
+			commission_payout_batches cpb
+		` + commissionPayoutBatchJoinSQL + `
+		WHERE
+			1 = 1
+	`
+
+	if req.BillingPeriod != "" {
+		query += ` AND cpb.billing_period = TO_DATE(?, 'YYYY-MM')`
+		args = append(args, req.BillingPeriod)
+	}
+
+	query += ` ORDER BY cpb.paid_at DESC, cpb.created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetCommissionPayoutBatches - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.CommissionPayoutBatch)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetCommissionPayoutBatch(ctx context.Context, req *entity.GetCommissionPayoutBatchReq) (*entity.GetCommissionPayoutBatchResp, error) {
+	var (
+		resp = new(entity.GetCommissionPayoutBatchResp)
+	)
+	resp.Items = make([]entity.CommissionPayoutBatchItem, 0)
+
+	query := `
+		SELECT
+			` + commissionPayoutBatchColumnsSQL + `
+		FROM
+			commission_payout_batches cpb
+		` + commissionPayoutBatchJoinSQL + `
+		WHERE
+			cpb.id = ?
+	`
+
+	err := r.db.GetContext(ctx, &resp.CommissionPayoutBatch, r.db.Rebind(query), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetCommissionPayoutBatch - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Batch pembayaran komisi tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetCommissionPayoutBatch - failed to fetch data")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			cpi.id,
+			cpi.registration_id,
+			cpi.marketer_id,
+			m.name AS marketer_name,
+			s.name AS student_name,
+			pr.program_name,
+			cpi.marketer_commission_fee,
+			cpi.marketer_gifts_fee,
+			cpi.amount
+		FROM
+			commission_payout_items cpi
+		JOIN
+			program_registrations pr
+			ON cpi.registration_id = pr.id
+		JOIN
+			marketers m
+			ON cpi.marketer_id = m.id
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		WHERE
+			cpi.batch_id = ?
+		ORDER BY
+			m.name ASC, s.name ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Items, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetCommissionPayoutBatch - failed to fetch items")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// UnlockCommissionPayoutBatch releases the registrations of a batch so they can
+// be edited and paid again, the batch itself is kept for the history.
+func (r *reportRepo) UnlockCommissionPayoutBatch(ctx context.Context, req *entity.UnlockCommissionPayoutBatchReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, audit.CommissionPayoutBatch, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			commission_payout_batches
+		SET
+			unlocked_at = NOW(),
+			unlocked_by = ?,
+			unlocked_reason = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND unlocked_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Reason, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - failed to unlock batch")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - data not found or already unlocked")
+		return errmsg.NewCustomErrors(404).SetMessage("Batch pembayaran komisi tidak ditemukan atau sudah dibuka")
+	}
+
+	query = `
+		UPDATE
+			commission_payout_items
+		SET
+			unlocked_at = NOW()
+		WHERE
+			batch_id = ?
+			AND unlocked_at IS NULL
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - failed to unlock items")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnlockCommissionPayoutBatch - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+const (
+	commissionPayoutBatchColumnsSQL = `
+		cpb.id,
+		cpb.user_id,
+		u.name AS user_name,
+		TO_CHAR(cpb.billing_period, 'YYYY-MM') AS billing_period,
+		cpb.paid_at,
+		cpb.reference,
+		cpb.notes,
+		(SELECT COUNT(*) FROM commission_payout_items cpi WHERE cpi.batch_id = cpb.id) AS total_items,
+		cpb.total_amount,
+		cpb.unlocked_at,
+		cpb.unlocked_by,
+		uu.name AS unlocked_by_name,
+		cpb.unlocked_reason,
+		cpb.created_at
+	`
+
+	commissionPayoutBatchJoinSQL = `
+		LEFT JOIN
+			users u
+			ON cpb.user_id = u.id
+		LEFT JOIN
+			users uu
+			ON cpb.unlocked_by = uu.id
+	`
+)
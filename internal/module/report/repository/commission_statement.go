@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// activeCommissionPayoutItemJoinSQL joins the payout item and batch of a
+// registration (aliased pr) that is not unlocked yet, a registration has at
+// most one.
+const activeCommissionPayoutItemJoinSQL = `
+	LEFT JOIN
+		commission_payout_items cpi
+		ON cpi.registration_id = pr.id
+		AND cpi.unlocked_at IS NULL
+	LEFT JOIN
+		commission_payout_batches cpb
+		ON cpi.batch_id = cpb.id
+`
+
+func (r *reportRepo) GetCommissionStatementRows(ctx context.Context, req *entity.GetCommissionStatementsReq) ([]entity.CommissionStatementRow, error) {
+	var (
+		data = make([]entity.CommissionStatementRow, 0)
+		args = make([]any, 0, 4)
+	)
+
+	query := `
+		SELECT
+			sm.id AS student_manager_id,
+			sm.name AS student_manager_name,
+			m.id AS marketer_id,
+			m.name AS marketer_name,
+			pr.id AS registration_id,
+			pr.student_id,
+			s.name AS student_name,
+			pr.program_name,
+			pr.paid_at,
+			pr.marketer_commission_fee,
+			pr.marketer_gifts_fee,
+			pr.marketer_commission_fee + pr.marketer_gifts_fee AS amount,
+			CASE WHEN cpi.id IS NULL THEN 'unpaid' ELSE 'paid' END AS payout_status,
+			cpb.id AS payout_batch_id,
+			cpb.paid_at AS payout_paid_at
+		FROM
+			program_registrations pr
+		JOIN
+			marketers m
+			ON pr.marketer_id = m.id
+		JOIN
+			student_managers sm
+			ON m.student_manager_id = sm.id
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		` + activeCommissionPayoutItemJoinSQL + `
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+			AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+	`
+	args = append(args, req.BillingPeriod)
+
+	if req.StudentManagerId != "" {
+		query += ` AND sm.id = ?`
+		args = append(args, req.StudentManagerId)
+	}
+
+	if req.MarketerId != "" {
+		query += ` AND m.id = ?`
+		args = append(args, req.MarketerId)
+	}
+
+	switch req.PayoutStatus {
+	case entity.CommissionPayoutStatusPaid:
+		query += ` AND cpi.id IS NOT NULL`
+	case entity.CommissionPayoutStatusUnpaid:
+		query += ` AND cpi.id IS NULL`
+	}
+
+	query += ` ORDER BY sm.name ASC, sm.id ASC, m.name ASC, m.id ASC, pr.paid_at ASC, pr.id ASC`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetCommissionStatementRows - failed to fetch data")
+		return nil, err
+	}
+
+	return data, nil
+}
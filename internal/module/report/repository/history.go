@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *reportRepo) GetHistory(ctx context.Context, req *entity.GetHistoryReq) (*entity.GetHistoryResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.HistoryItem
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetHistoryResp)
+	)
+	resp.Items = make([]entity.HistoryItem, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			al.id,
+			al.user_id,
+			u.name AS user_name,
+			al.ip,
+			al.action,
+			al.before,
+			al.after,
+			al.created_at
+		FROM
+			audit_logs al
+		LEFT JOIN
+			users u
+			ON al.user_id = u.id
+		WHERE
+			al.entity = ?
+			AND al.entity_id = ?
+		ORDER BY
+			al.created_at DESC, al.id DESC
+		LIMIT ? OFFSET ?
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.Entity, req.Id, req.Paginate, (req.Page-1)*req.Paginate)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetHistory - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.HistoryItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
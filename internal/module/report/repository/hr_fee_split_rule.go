@@ -0,0 +1,502 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// hrFeeSplitRuleJoinSQL picks the most specific active rule of a registration
+	// aliased as pr: a lecturer rule, then a program rule, then the global rule
+	hrFeeSplitRuleJoinSQL = `
+		LEFT JOIN LATERAL (
+			SELECT
+				hfsr.id,
+				hfsr.split_type,
+				hfsr.mentor_value
+			FROM
+				hr_fee_split_rules hfsr
+			WHERE
+				hfsr.deleted_at IS NULL
+				AND (
+					(hfsr.scope = 'lecturer' AND hfsr.lecturer_id = pr.lecturer_id)
+					OR (hfsr.scope = 'program' AND hfsr.program_id = pr.program_id)
+					OR hfsr.scope = 'global'
+				)
+			ORDER BY
+				CASE hfsr.scope
+					WHEN 'lecturer' THEN 1
+					WHEN 'program' THEN 2
+					ELSE 3
+				END
+			LIMIT 1
+		) rule
+			ON TRUE
+	`
+
+	hrFeeSplitRuleColumnsSQL = `
+		hfsr.id,
+		hfsr.user_id,
+		u.name AS user_name,
+		hfsr.scope,
+		hfsr.program_id,
+		p.name AS program_name,
+		hfsr.lecturer_id,
+		l.name AS lecturer_name,
+		hfsr.split_type,
+		hfsr.mentor_value,
+		hfsr.notes,
+		hfsr.created_at,
+		hfsr.updated_at
+	`
+)
+
+// applyHRFeeSplitRules fills the hr fee split of the registrations matching
+// cond, a condition on program_registrations aliased as pr, from their rule.
+// Registrations without a rule, or whose rule would leave the lecturer with less
+// than was already drawn, are left as they are.
+func applyHRFeeSplitRules(ctx context.Context, q sqlx.ExtContext, cond string, args ...any) (int64, error) {
+	type dao struct {
+		Id          string          `db:"id"`
+		HRFee       decimal.Decimal `db:"hr_fee"`
+		RuleId      string          `db:"rule_id"`
+		SplitType   string          `db:"split_type"`
+		MentorValue decimal.Decimal `db:"mentor_value"`
+		TotalUsed   decimal.Decimal `db:"total_used"`
+	}
+
+	var (
+		data       = make([]dao, 0)
+		ids        = make([]string, 0)
+		ruleIds    = make([]string, 0)
+		mentorFees = make([]string, 0)
+	)
+
+	query := `
+		SELECT
+			pr.id,
+			pr.hr_fee,
+			rule.id AS rule_id,
+			rule.split_type,
+			rule.mentor_value,
+			` + registrationMentorFeeUsedSQL + ` AS total_used
+		FROM
+			program_registrations pr
+		` + hrFeeSplitRuleJoinSQL + `
+		` + registrationMentorFeeUsagesJoinSQL + `
+		WHERE
+			pr.deleted_at IS NULL
+			AND rule.id IS NOT NULL
+			AND ` + cond + `
+		FOR UPDATE OF pr
+	`
+
+	err := sqlx.SelectContext(ctx, q, &data, q.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Str("cond", cond).Any("args", args).Msg("repo::applyHRFeeSplitRules - failed to fetch registrations")
+		return 0, err
+	}
+
+	for _, d := range data {
+		rule := entity.HRFeeSplitRuleValue{SplitType: d.SplitType, MentorValue: d.MentorValue}
+
+		mentorFee := rule.MentorFee(d.HRFee)
+		if mentorFee.LessThan(d.TotalUsed) {
+			continue
+		}
+
+		ids = append(ids, d.Id)
+		ruleIds = append(ruleIds, d.RuleId)
+		mentorFees = append(mentorFees, mentorFee.String())
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	query = `
+		UPDATE
+			program_registrations target
+		SET
+			mentor_detail_fee = split.mentor_fee,
+			hr_detail_fee = target.hr_fee - split.mentor_fee,
+			hr_fee_split_rule_id = split.rule_id,
+			hr_fee_split_manual = FALSE
+		FROM
+			UNNEST(?::TEXT[], ?::TEXT[], ?::NUMERIC[]) AS split(id, rule_id, mentor_fee)
+		WHERE
+			target.id = split.id
+	`
+
+	result, err := q.ExecContext(ctx, q.Rebind(query), pq.Array(ids), pq.Array(ruleIds), pq.Array(mentorFees))
+	if err != nil {
+		log.Error().Err(err).Str("cond", cond).Any("args", args).Msg("repo::applyHRFeeSplitRules - failed to apply rules")
+		return 0, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Str("cond", cond).Any("args", args).Msg("repo::applyHRFeeSplitRules - failed to get affected rows")
+		return 0, err
+	}
+
+	return affected, nil
+}
+
+func (r *reportRepo) ApplyHRFeeSplitRules(ctx context.Context, req *entity.ApplyHRFeeSplitRulesReq) (*entity.ApplyHRFeeSplitRulesResp, error) {
+	var (
+		resp = &entity.ApplyHRFeeSplitRulesResp{BillingPeriod: req.BillingPeriod}
+		cond = `pr.billing_period = TO_DATE(?, 'YYYY-MM')`
+		args = []any{req.BillingPeriod}
+	)
+
+	if req.ProgramId != "" {
+		cond += ` AND pr.program_id = ?`
+		args = append(args, req.ProgramId)
+	}
+
+	if req.LecturerId != "" {
+		cond += ` AND pr.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApplyHRFeeSplitRules - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = lockPeriod(ctx, tx, req.BillingPeriod, false); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE locked) AS total_locked,
+			COUNT(*) FILTER (WHERE NOT locked AND manual AND NOT ?) AS total_manual,
+			COUNT(*) FILTER (WHERE NOT locked AND NOT (manual AND NOT ?) AND rule_id IS NULL) AS total_no_rule
+		FROM (
+			SELECT
+				` + registrationLockedSQL + ` AS locked,
+				pr.hr_fee_split_manual AS manual,
+				rule.id AS rule_id
+			FROM
+				program_registrations pr
+			` + hrFeeSplitRuleJoinSQL + `
+			WHERE
+				pr.deleted_at IS NULL
+				AND ` + cond + `
+		) candidates
+	`
+
+	err = tx.GetContext(ctx, resp, tx.Rebind(query), append([]any{req.OverwriteManual, req.OverwriteManual}, args...)...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApplyHRFeeSplitRules - failed to count registrations")
+		return nil, err
+	}
+
+	var ids []string
+	err = tx.SelectContext(ctx, &ids, tx.Rebind(`SELECT pr.id FROM program_registrations pr WHERE pr.deleted_at IS NULL AND `+cond), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApplyHRFeeSplitRules - failed to fetch registrations")
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	cond += ` AND NOT ` + registrationLockedSQL
+	if !req.OverwriteManual {
+		cond += ` AND pr.hr_fee_split_manual = FALSE`
+	}
+
+	updated, err := applyHRFeeSplitRules(ctx, tx, cond, args...)
+	if err != nil {
+		return nil, err
+	}
+	resp.TotalUpdated = int(updated)
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApplyHRFeeSplitRules - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetHRFeeSplitRules(ctx context.Context, req *entity.GetHRFeeSplitRulesReq) (*entity.GetHRFeeSplitRulesResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.HRFeeSplitRule
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetHRFeeSplitRulesResp)
+		args = make([]any, 0, 5)
+	)
+	resp.Items = make([]entity.HRFeeSplitRule, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			` + hrFeeSplitRuleColumnsSQL + `
+		FROM
+			hr_fee_split_rules hfsr
+		LEFT JOIN
+			users u
+			ON hfsr.user_id = u.id
+		LEFT JOIN
+			programs p
+			ON hfsr.program_id = p.id
+		LEFT JOIN
+			lecturers l
+			ON hfsr.lecturer_id = l.id
+		WHERE
+			hfsr.deleted_at IS NULL
+	`
+
+	if req.Scope != "" {
+		query += ` AND hfsr.scope = ?`
+		args = append(args, req.Scope)
+	}
+
+	if req.ProgramId != "" {
+		query += ` AND hfsr.program_id = ?`
+		args = append(args, req.ProgramId)
+	}
+
+	if req.LecturerId != "" {
+		query += ` AND hfsr.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	query += `
+		ORDER BY
+			CASE hfsr.scope
+				WHEN 'global' THEN 1
+				WHEN 'program' THEN 2
+				ELSE 3
+			END,
+			COALESCE(p.name, l.name) ASC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetHRFeeSplitRules - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.HRFeeSplitRule)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *reportRepo) CreateHRFeeSplitRule(ctx context.Context, req *entity.CreateHRFeeSplitRuleReq) (*entity.CreateHRFeeSplitRuleResp, error) {
+	var (
+		resp = new(entity.CreateHRFeeSplitRuleResp)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateHRFeeSplitRule - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryCheck := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					hr_fee_split_rules
+				WHERE
+					scope = ?
+					AND program_id IS NOT DISTINCT FROM ?
+					AND lecturer_id IS NOT DISTINCT FROM ?
+					AND deleted_at IS NULL
+			)
+	`
+
+	var exist bool
+	err = tx.GetContext(ctx, &exist, tx.Rebind(queryCheck), req.Scope, req.ProgramId, req.LecturerId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateHRFeeSplitRule - failed to check data")
+		return nil, err
+	}
+
+	if exist {
+		log.Warn().Any("req", req).Msg("repo::CreateHRFeeSplitRule - rule already exist")
+		return nil, errmsg.NewCustomErrors(409).SetMessage("Aturan pembagian biaya SDM untuk cakupan tersebut sudah ada")
+	}
+
+	query := `
+		INSERT INTO hr_fee_split_rules (
+			id,
+			user_id,
+			scope,
+			program_id,
+			lecturer_id,
+			split_type,
+			mentor_value,
+			notes
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	resp.Id = ulid.Make().String()
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		resp.Id,
+		req.UserId,
+		req.Scope,
+		req.ProgramId,
+		req.LecturerId,
+		req.SplitType,
+		req.MentorValue,
+		req.Notes,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateHRFeeSplitRule - failed to insert data")
+		return nil, err
+	}
+
+	if err = audit.Created(ctx, tx, audit.HRFeeSplitRule, resp.Id); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateHRFeeSplitRule - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) UpdateHRFeeSplitRule(ctx context.Context, req *entity.UpdateHRFeeSplitRuleReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateHRFeeSplitRule - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, audit.HRFeeSplitRule, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			hr_fee_split_rules
+		SET
+			split_type = ?,
+			mentor_value = ?,
+			notes = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.SplitType, req.MentorValue, req.Notes, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateHRFeeSplitRule - failed to update data")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateHRFeeSplitRule - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::UpdateHRFeeSplitRule - data not found")
+		return errmsg.NewCustomErrors(404).SetMessage("Aturan pembagian biaya SDM tidak ditemukan")
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UpdateHRFeeSplitRule - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) DeleteHRFeeSplitRule(ctx context.Context, req *entity.DeleteHRFeeSplitRuleReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteHRFeeSplitRule - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, audit.HRFeeSplitRule, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			hr_fee_split_rules
+		SET
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteHRFeeSplitRule - failed to delete data")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteHRFeeSplitRule - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::DeleteHRFeeSplitRule - data not found")
+		return errmsg.NewCustomErrors(404).SetMessage("Aturan pembagian biaya SDM tidak ditemukan")
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteHRFeeSplitRule - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
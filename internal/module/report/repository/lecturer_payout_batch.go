@@ -0,0 +1,509 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	lecturerPayoutBatchColumnsSQL = `
+		lpb.id,
+		lpb.user_id,
+		u.name AS user_name,
+		TO_CHAR(lpb.billing_period, 'YYYY-MM') AS billing_period,
+		lpb.status,
+		lpb.notes,
+		(SELECT COUNT(*) FROM lecturer_payout_statements lps WHERE lps.batch_id = lpb.id) AS total_statements,
+		lpb.total_amount,
+		lpb.approved_at,
+		lpb.approved_by,
+		ua.name AS approved_by_name,
+		lpb.paid_at,
+		lpb.paid_by,
+		up.name AS paid_by_name,
+		lpb.reference,
+		lpb.created_at
+	`
+
+	lecturerPayoutBatchJoinSQL = `
+		LEFT JOIN
+			users u
+			ON lpb.user_id = u.id
+		LEFT JOIN
+			users ua
+			ON lpb.approved_by = ua.id
+		LEFT JOIN
+			users up
+			ON lpb.paid_by = up.id
+	`
+)
+
+func (r *reportRepo) CreateLecturerPayoutBatch(ctx context.Context, req *entity.CreateLecturerPayoutBatchReq) (*entity.CreateLecturerPayoutBatchResp, error) {
+	type dao struct {
+		Id          string          `db:"id"`
+		TotalAmount decimal.Decimal `db:"total_amount"`
+	}
+
+	var (
+		data = make([]dao, 0)
+		resp = new(entity.CreateLecturerPayoutBatchResp)
+		args = []any{req.BillingPeriod}
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturerPayoutBatch - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			lps.id,
+			lps.total_amount
+		FROM
+			lecturer_payout_statements lps
+		WHERE
+			lps.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND lps.status = 'approved'
+			AND lps.batch_id IS NULL
+	`
+
+	if len(req.StatementIds) > 0 {
+		query += ` AND lps.id = ANY(?)`
+		args = append(args, pq.Array(req.StatementIds))
+	}
+
+	query += ` ORDER BY lps.id ASC FOR UPDATE`
+
+	err = tx.SelectContext(ctx, &data, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturerPayoutBatch - failed to fetch statements")
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		log.Warn().Any("req", req).Msg("repo::CreateLecturerPayoutBatch - no approved statements")
+		return nil, errmsg.NewCustomErrors(422).SetMessage("Tidak ada slip honor pengajar yang sudah disetujui dan belum masuk batch di periode ini")
+	}
+
+	if len(req.StatementIds) > 0 && len(data) != len(req.StatementIds) {
+		log.Warn().Any("req", req).Int("found", len(data)).Msg("repo::CreateLecturerPayoutBatch - some statements can not be batched")
+		return nil, errmsg.NewCustomErrors(422).SetMessage("Sebagian slip honor pengajar belum disetujui, berbeda periode, atau sudah masuk batch lain")
+	}
+
+	var (
+		ids = make([]string, 0, len(data))
+	)
+	resp.Id = ulid.Make().String()
+	resp.TotalAmount = decimal.Zero
+	for _, item := range data {
+		ids = append(ids, item.Id)
+		resp.TotalAmount = resp.TotalAmount.Add(item.TotalAmount)
+	}
+	resp.TotalStatements = len(data)
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutStatement, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO lecturer_payout_batches (
+			id,
+			user_id,
+			billing_period,
+			notes,
+			total_amount
+		) VALUES (?, ?, TO_DATE(?, 'YYYY-MM'), ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		resp.Id, req.UserId, req.BillingPeriod, req.Notes, resp.TotalAmount,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturerPayoutBatch - failed to insert batch")
+		return nil, err
+	}
+
+	query = `
+		UPDATE
+			lecturer_payout_statements
+		SET
+			batch_id = ?,
+			updated_at = NOW()
+		WHERE
+			id = ANY(?)
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), resp.Id, pq.Array(ids))
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturerPayoutBatch - failed to attach statements")
+		return nil, err
+	}
+
+	if err = audit.Created(ctx, tx, audit.LecturerPayoutBatch, resp.Id); err != nil {
+		return nil, err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateLecturerPayoutBatch - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetLecturerPayoutBatches(ctx context.Context, req *entity.GetLecturerPayoutBatchesReq) (*entity.GetLecturerPayoutBatchesResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.LecturerPayoutBatch
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetLecturerPayoutBatchesResp)
+		args = make([]any, 0, 4)
+	)
+	resp.Items = make([]entity.LecturerPayoutBatch, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			` + lecturerPayoutBatchColumnsSQL + `
+		FROM
+			lecturer_payout_batches lpb
+		` + lecturerPayoutBatchJoinSQL + `
+		WHERE
+			1 = 1
+	`
+
+	if req.BillingPeriod != "" {
+		query += ` AND lpb.billing_period = TO_DATE(?, 'YYYY-MM')`
+		args = append(args, req.BillingPeriod)
+	}
+
+	if req.Status != "" {
+		query += ` AND lpb.status = ?`
+		args = append(args, req.Status)
+	}
+
+	query += ` ORDER BY lpb.created_at DESC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerPayoutBatches - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.LecturerPayoutBatch)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetLecturerPayoutBatch(ctx context.Context, req *entity.GetLecturerPayoutBatchReq) (*entity.GetLecturerPayoutBatchResp, error) {
+	var (
+		resp = new(entity.GetLecturerPayoutBatchResp)
+	)
+	resp.Statements = make([]entity.LecturerStatement, 0)
+
+	query := `
+		SELECT
+			` + lecturerPayoutBatchColumnsSQL + `
+		FROM
+			lecturer_payout_batches lpb
+		` + lecturerPayoutBatchJoinSQL + `
+		WHERE
+			lpb.id = ?
+	`
+
+	err := r.db.GetContext(ctx, &resp.LecturerPayoutBatch, r.db.Rebind(query), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetLecturerPayoutBatch - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Batch pembayaran honor pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerPayoutBatch - failed to fetch data")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			` + lecturerStatementColumnsSQL + `
+		FROM
+			lecturer_payout_statements lps
+		` + lecturerStatementJoinSQL + `
+		WHERE
+			lps.batch_id = ?
+		ORDER BY
+			l.name ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Statements, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerPayoutBatch - failed to fetch statements")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) ApproveLecturerPayoutBatch(ctx context.Context, req *entity.ApproveLecturerPayoutBatchReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerPayoutBatch - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := getLecturerPayoutBatchStatusForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.LecturerPayoutStatusDraft {
+		log.Warn().Any("req", req).Str("status", status).Msg("repo::ApproveLecturerPayoutBatch - batch is not a draft")
+		return errmsg.NewCustomErrors(409).SetMessage("Batch pembayaran honor pengajar sudah disetujui")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutBatch, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			lecturer_payout_batches
+		SET
+			status = 'approved',
+			approved_at = NOW(),
+			approved_by = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerPayoutBatch - failed to approve batch")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerPayoutBatch - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) PayLecturerPayoutBatch(ctx context.Context, req *entity.PayLecturerPayoutBatchReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PayLecturerPayoutBatch - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := getLecturerPayoutBatchStatusForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.LecturerPayoutStatusApproved {
+		log.Warn().Any("req", req).Str("status", status).Msg("repo::PayLecturerPayoutBatch - batch is not approved")
+		return errmsg.NewCustomErrors(409).SetMessage("Hanya batch yang sudah disetujui dan belum dibayar yang dapat ditandai dibayar")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutBatch, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			lecturer_payout_batches
+		SET
+			status = 'paid',
+			paid_at = TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP AT TIME ZONE ?,
+			paid_by = ?,
+			reference = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.PaidAt, req.Timezone, req.UserId, req.Reference, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PayLecturerPayoutBatch - failed to mark batch as paid")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PayLecturerPayoutBatch - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// DeleteLecturerPayoutBatch removes a draft batch, its statements stay approved
+// and can be put in another batch
+func (r *reportRepo) DeleteLecturerPayoutBatch(ctx context.Context, req *entity.DeleteLecturerPayoutBatchReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteLecturerPayoutBatch - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	status, err := getLecturerPayoutBatchStatusForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.LecturerPayoutStatusDraft {
+		log.Warn().Any("req", req).Str("status", status).Msg("repo::DeleteLecturerPayoutBatch - batch is not a draft")
+		return errmsg.NewCustomErrors(409).SetMessage("Hanya batch draft yang dapat dihapus")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutBatch, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			lecturer_payout_statements
+		SET
+			batch_id = NULL,
+			updated_at = NOW()
+		WHERE
+			batch_id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteLecturerPayoutBatch - failed to detach statements")
+		return err
+	}
+
+	query = `DELETE FROM lecturer_payout_batches WHERE id = ?`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteLecturerPayoutBatch - failed to delete batch")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteLecturerPayoutBatch - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) GetLecturerPayoutBankFile(ctx context.Context, req *entity.GetLecturerPayoutBankFileReq) (*entity.LecturerPayoutBankFile, error) {
+	var (
+		resp = new(entity.LecturerPayoutBankFile)
+	)
+	resp.Transfers = make([]entity.LecturerPayoutTransfer, 0)
+
+	query := `
+		SELECT
+			` + lecturerPayoutBatchColumnsSQL + `
+		FROM
+			lecturer_payout_batches lpb
+		` + lecturerPayoutBatchJoinSQL + `
+		WHERE
+			lpb.id = ?
+	`
+
+	err := r.db.GetContext(ctx, &resp.Batch, r.db.Rebind(query), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetLecturerPayoutBankFile - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Batch pembayaran honor pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerPayoutBankFile - failed to fetch batch")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			lps.id AS statement_id,
+			lps.lecturer_id,
+			l.name AS lecturer_name,
+			lps.total_amount,
+			l.bank_name,
+			l.bank_account_number,
+			l.bank_account_name
+		FROM
+			lecturer_payout_statements lps
+		JOIN
+			lecturers l
+			ON lps.lecturer_id = l.id
+		WHERE
+			lps.batch_id = ?
+			AND lps.total_amount > 0
+		ORDER BY
+			l.name ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Transfers, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerPayoutBankFile - failed to fetch transfers")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func getLecturerPayoutBatchStatusForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (string, error) {
+	var status string
+
+	query := `SELECT status FROM lecturer_payout_batches WHERE id = ? FOR UPDATE`
+
+	err := tx.GetContext(ctx, &status, tx.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repo::getLecturerPayoutBatchStatusForUpdate - data not found")
+			return "", errmsg.NewCustomErrors(404).SetMessage("Batch pembayaran honor pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Str("id", id).Msg("repo::getLecturerPayoutBatchStatusForUpdate - failed to fetch data")
+		return "", err
+	}
+
+	return status, nil
+}
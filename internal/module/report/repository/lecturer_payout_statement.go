@@ -0,0 +1,524 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	lecturerStatementColumnsSQL = `
+		lps.id,
+		lps.lecturer_id,
+		l.name AS lecturer_name,
+		TO_CHAR(lps.billing_period, 'YYYY-MM') AS billing_period,
+		lps.status,
+		lps.batch_id,
+		lpb.status AS batch_status,
+		(SELECT COUNT(*) FROM lecturer_payout_statement_items lpsi WHERE lpsi.statement_id = lps.id) AS total_items,
+		lps.total_hr_fee,
+		lps.total_amount,
+		lps.approved_at,
+		lps.approved_by,
+		ua.name AS approved_by_name,
+		lps.created_at,
+		lps.updated_at
+	`
+
+	lecturerStatementJoinSQL = `
+		JOIN
+			lecturers l
+			ON lps.lecturer_id = l.id
+		LEFT JOIN
+			lecturer_payout_batches lpb
+			ON lps.batch_id = lpb.id
+		LEFT JOIN
+			users ua
+			ON lps.approved_by = ua.id
+	`
+)
+
+// GenerateLecturerStatements rebuilds the draft statements of a billing period
+// from the distributed lecturer fees, approved statements are left untouched
+func (r *reportRepo) GenerateLecturerStatements(ctx context.Context, req *entity.GenerateLecturerStatementsReq) (*entity.GenerateLecturerStatementsResp, error) {
+	var (
+		resp = &entity.GenerateLecturerStatementsResp{
+			BillingPeriod: req.BillingPeriod,
+			Skipped:       make([]entity.LecturerStatementSkipped, 0),
+		}
+		sources = make([]entity.LecturerStatementSource, 0)
+		filter  string
+		args    = []any{req.BillingPeriod}
+	)
+
+	if req.LecturerId != "" {
+		filter = ` AND lps.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT
+			lps.id,
+			lps.lecturer_id,
+			l.name AS lecturer_name,
+			lps.status
+		FROM
+			lecturer_payout_statements lps
+		JOIN
+			lecturers l
+			ON lps.lecturer_id = l.id
+		WHERE
+			lps.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND lps.status != 'draft'
+	` + filter + `
+		ORDER BY
+			l.name ASC
+		FOR UPDATE OF lps
+	`
+
+	err = tx.SelectContext(ctx, &resp.Skipped, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to fetch approved statements")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			lps.id
+		FROM
+			lecturer_payout_statements lps
+		WHERE
+			lps.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND lps.status = 'draft'
+	` + filter
+
+	var draftIds []string
+	err = tx.SelectContext(ctx, &draftIds, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to fetch draft statements")
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutStatement, draftIds...)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		DELETE FROM
+			lecturer_payout_statements lps
+		WHERE
+			lps.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND lps.status = 'draft'
+	` + filter
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to delete draft statements")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			pr.id AS registration_id,
+			pr.lecturer_id,
+			pr.mentor_detail_fee AS hr_fee_for_lecturer,
+			mfu_sum.total_used AS used_amount,
+			COALESCE(mfu_sum.last_notes, pr.notes_for_fund_distributions) AS notes
+		FROM
+			program_registrations pr
+		` + registrationMentorFeeUsagesJoinSQL + `
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+			AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND pr.lecturer_id IS NOT NULL
+			AND pr.mentor_detail_fee IS NOT NULL
+			AND NOT EXISTS (
+				SELECT
+					1
+				FROM
+					lecturer_payout_statements lps
+				WHERE
+					lps.lecturer_id = pr.lecturer_id
+					AND lps.billing_period = pr.billing_period
+			)
+	`
+
+	if req.LecturerId != "" {
+		query += ` AND pr.lecturer_id = ?`
+	}
+
+	query += ` ORDER BY pr.lecturer_id ASC, pr.id ASC`
+
+	err = tx.SelectContext(ctx, &sources, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to fetch registrations")
+		return nil, err
+	}
+
+	queryStatement := `
+		INSERT INTO lecturer_payout_statements (
+			id,
+			lecturer_id,
+			billing_period,
+			total_hr_fee,
+			total_amount
+		) VALUES (?, ?, TO_DATE(?, 'YYYY-MM'), ?, ?)
+	`
+
+	queryItem := `
+		INSERT INTO lecturer_payout_statement_items (
+			id,
+			statement_id,
+			registration_id,
+			hr_fee_for_lecturer,
+			used_amount,
+			notes
+		) VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	// sources are ordered by lecturer so each statement is a contiguous run
+	createdIds := make([]string, 0)
+	for start := 0; start < len(sources); {
+		end := start
+		for end < len(sources) && sources[end].LecturerId == sources[start].LecturerId {
+			end++
+		}
+		lines := sources[start:end]
+		start = end
+
+		var (
+			statementId = ulid.Make().String()
+			totalHRFee  = decimal.Zero
+			totalAmount = decimal.Zero
+		)
+		for _, line := range lines {
+			totalHRFee = totalHRFee.Add(line.HRFeeForLecturer)
+			if line.UsedAmount != nil {
+				totalAmount = totalAmount.Add(*line.UsedAmount)
+			}
+		}
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(queryStatement),
+			statementId, lines[0].LecturerId, req.BillingPeriod, totalHRFee, totalAmount,
+		)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to insert statement")
+			return nil, err
+		}
+
+		for _, line := range lines {
+			_, err = tx.ExecContext(ctx, tx.Rebind(queryItem),
+				ulid.Make().String(), statementId, line.RegistrationId,
+				line.HRFeeForLecturer, line.UsedAmount, line.Notes,
+			)
+			if err != nil {
+				log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to insert statement item")
+				return nil, err
+			}
+		}
+
+		createdIds = append(createdIds, statementId)
+		resp.Generated++
+	}
+
+	if err = audit.Created(ctx, tx, audit.LecturerPayoutStatement, createdIds...); err != nil {
+		return nil, err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateLecturerStatements - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetLecturerStatements(ctx context.Context, req *entity.GetLecturerStatementsReq) (*entity.GetLecturerStatementsResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.LecturerStatement
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetLecturerStatementsResp)
+		args = make([]any, 0, 5)
+	)
+	resp.Items = make([]entity.LecturerStatement, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			` + lecturerStatementColumnsSQL + `
+		FROM
+			lecturer_payout_statements lps
+		` + lecturerStatementJoinSQL + `
+		WHERE
+			1 = 1
+	`
+
+	if req.BillingPeriod != "" {
+		query += ` AND lps.billing_period = TO_DATE(?, 'YYYY-MM')`
+		args = append(args, req.BillingPeriod)
+	}
+
+	if req.LecturerId != "" {
+		query += ` AND lps.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	if req.Status != "" {
+		query += ` AND lps.status = ?`
+		args = append(args, req.Status)
+	}
+
+	if req.Unbatched {
+		query += ` AND lps.batch_id IS NULL`
+	}
+
+	query += ` ORDER BY lps.billing_period DESC, l.name ASC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerStatements - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.LecturerStatement)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetLecturerStatement(ctx context.Context, req *entity.GetLecturerStatementReq) (*entity.GetLecturerStatementResp, error) {
+	var (
+		resp = new(entity.GetLecturerStatementResp)
+	)
+	resp.Items = make([]entity.LecturerStatementItem, 0)
+
+	query := `
+		SELECT
+			` + lecturerStatementColumnsSQL + `,
+			l.bank_name,
+			l.bank_account_number,
+			l.bank_account_name
+		FROM
+			lecturer_payout_statements lps
+		` + lecturerStatementJoinSQL + `
+		WHERE
+			lps.id = ?
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetLecturerStatement - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Slip honor pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerStatement - failed to fetch data")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			lpsi.id,
+			lpsi.registration_id,
+			pr.student_id,
+			s.name AS student_name,
+			pr.program_id,
+			pr.program_name,
+			pr.foreign_learning_fee IS NOT NULL AS is_fl,
+			pr.night_learning_fee IS NOT NULL AS is_nl,
+			lpsi.hr_fee_for_lecturer,
+			lpsi.used_amount,
+			COALESCE(lpsi.used_amount, 0) AS amount,
+			lpsi.notes
+		FROM
+			lecturer_payout_statement_items lpsi
+		JOIN
+			program_registrations pr
+			ON lpsi.registration_id = pr.id
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		WHERE
+			lpsi.statement_id = ?
+		ORDER BY
+			s.name ASC, pr.program_name ASC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Items, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerStatement - failed to fetch items")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) ApproveLecturerStatement(ctx context.Context, req *entity.ApproveLecturerStatementReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerStatement - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	status, _, err := getLecturerStatementForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.LecturerPayoutStatusDraft {
+		log.Warn().Any("req", req).Str("status", status).Msg("repo::ApproveLecturerStatement - statement is not a draft")
+		return errmsg.NewCustomErrors(409).SetMessage("Slip honor pengajar sudah disetujui")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutStatement, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			lecturer_payout_statements
+		SET
+			status = 'approved',
+			approved_at = NOW(),
+			approved_by = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerStatement - failed to approve statement")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ApproveLecturerStatement - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// ReopenLecturerStatement turns an approved statement back into a draft so its
+// registrations can be edited and the statement regenerated
+func (r *reportRepo) ReopenLecturerStatement(ctx context.Context, req *entity.ReopenLecturerStatementReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenLecturerStatement - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	status, batchId, err := getLecturerStatementForUpdate(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if status != entity.LecturerPayoutStatusApproved {
+		log.Warn().Any("req", req).Str("status", status).Msg("repo::ReopenLecturerStatement - statement is not approved")
+		return errmsg.NewCustomErrors(409).SetMessage("Slip honor pengajar belum disetujui")
+	}
+
+	if batchId != nil {
+		log.Warn().Any("req", req).Str("batch_id", *batchId).Msg("repo::ReopenLecturerStatement - statement is in a payout batch")
+		return errmsg.NewCustomErrors(409).SetMessage("Slip honor pengajar sudah masuk batch pembayaran, hapus batch terlebih dahulu")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.LecturerPayoutStatement, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			lecturer_payout_statements
+		SET
+			status = 'draft',
+			approved_at = NULL,
+			approved_by = NULL,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenLecturerStatement - failed to reopen statement")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReopenLecturerStatement - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func getLecturerStatementForUpdate(ctx context.Context, tx *sqlx.Tx, id string) (string, *string, error) {
+	var data struct {
+		Status  string  `db:"status"`
+		BatchId *string `db:"batch_id"`
+	}
+
+	query := `
+		SELECT
+			status,
+			batch_id
+		FROM
+			lecturer_payout_statements
+		WHERE
+			id = ?
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, &data, tx.Rebind(query), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("id", id).Msg("repo::getLecturerStatementForUpdate - data not found")
+			return "", nil, errmsg.NewCustomErrors(404).SetMessage("Slip honor pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Str("id", id).Msg("repo::getLecturerStatementForUpdate - failed to fetch data")
+		return "", nil, err
+	}
+
+	return data.Status, data.BatchId, nil
+}
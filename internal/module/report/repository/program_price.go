@@ -0,0 +1,10 @@
+package repository
+
+import "codebase-app/pkg/programprice"
+
+const (
+	programPriceJoinSQL     = programprice.JoinSQL
+	programPriceSQL         = programprice.PriceSQL
+	programCommissionFeeSQL = programprice.CommissionFeeSQL
+	programLecturerFeeSQL   = programprice.LecturerFeeSQL
+)
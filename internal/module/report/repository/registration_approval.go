@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// registrationApprovedSQL limits program_registrations aliased as pr to the
+// registrations that count in the reports
+const registrationApprovedSQL = `pr.approval_status = 'approved'`
+
+func (r *reportRepo) SubmitRegistration(ctx context.Context, req *entity.SubmitRegistrationReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::SubmitRegistration - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	approval, err := lockRegistrationApproval(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if !approval.CanSubmit() {
+		log.Warn().Any("req", req).Str("status", approval.Status).Msg("repo::SubmitRegistration - registration is already submitted")
+		return errmsg.NewCustomErrors(409).SetMessage("Registrasi sudah diajukan atau disetujui")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			program_registrations
+		SET
+			approval_status = ?,
+			submitted_at = NOW(),
+			submitted_by = ?,
+			reviewed_at = NULL,
+			reviewed_by = NULL,
+			review_comment = NULL,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), entity.RegistrationStatusSubmitted, req.UserId, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::SubmitRegistration - failed to submit registration")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::SubmitRegistration - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) ReviewRegistration(ctx context.Context, req *entity.ReviewRegistrationReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewRegistration - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	// an approval adds the registration to the numbers of its period, the
+	// period lock is taken before the registration row
+	if req.Approve {
+		if err = checkRegistrationUnlocked(ctx, tx, req.Id); err != nil {
+			return err
+		}
+	}
+
+	approval, err := lockRegistrationApproval(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	if !approval.CanReview() {
+		log.Warn().Any("req", req).Str("status", approval.Status).Msg("repo::ReviewRegistration - registration is not submitted")
+		return errmsg.NewCustomErrors(409).SetMessage("Registrasi belum diajukan untuk disetujui")
+	}
+
+	if approval.IsMaker(req.UserId) {
+		log.Warn().Any("req", req).Msg("repo::ReviewRegistration - maker can not review their own registration")
+		return errmsg.NewCustomErrors(403).SetMessage("Registrasi tidak dapat disetujui atau ditolak oleh pembuat atau pengajunya sendiri")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.Id)
+	if err != nil {
+		return err
+	}
+
+	status := entity.RegistrationStatusRejected
+	if req.Approve {
+		status = entity.RegistrationStatusApproved
+	}
+
+	query := `
+		UPDATE
+			program_registrations
+		SET
+			approval_status = ?,
+			reviewed_at = NOW(),
+			reviewed_by = ?,
+			review_comment = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), status, req.UserId, req.Comment, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewRegistration - failed to review registration")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewRegistration - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// lockRegistrationApproval returns the approval status of the registration and
+// who submitted it, the registration is locked for update
+func lockRegistrationApproval(ctx context.Context, tx *sqlx.Tx, registrationId string) (*entity.RegistrationApproval, error) {
+	var approval = new(entity.RegistrationApproval)
+
+	query := `
+		SELECT
+			approval_status,
+			user_id,
+			updated_by,
+			submitted_by
+		FROM
+			program_registrations
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+		FOR UPDATE
+	`
+
+	err := tx.GetContext(ctx, approval, tx.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("registration_id", registrationId).Msg("repo::lockRegistrationApproval - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+		}
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::lockRegistrationApproval - failed to fetch data")
+		return nil, err
+	}
+
+	return approval, nil
+}
@@ -2,18 +2,28 @@ package repository
 
 import (
 	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
+	"database/sql"
+	"fmt"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
 )
 
-func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) error {
+func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) (*entity.RegistrationBatchResp, error) {
+	if req.Mode == entity.RegistrationBatchModePartial {
+		return r.createRegistrationsPartial(ctx, req)
+	}
+
+	var resp = &entity.RegistrationBatchResp{Mode: req.Mode, Items: make([]entity.RegistrationBatchItem, 0)}
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("repo::CreateRegistrations - failed to begin transaction")
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
@@ -29,6 +39,259 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 		}
 	}()
 
+	// refuse missing or archived templates before inserting anything
+	errTemplates := errmsg.NewCustomErrors(422).SetMessage("Beberapa template tidak dapat dibuatkan registrasi")
+	for i, item := range req.Registrations {
+		var msg string
+
+		msg, err = r.checkTemplateRegistrable(ctx, tx, item.TemplateId)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to check template status")
+			return nil, err
+		}
+
+		if msg != "" {
+			errTemplates.Add(fmt.Sprintf("Registrations[%d].template_id", i), msg)
+		}
+	}
+
+	if errTemplates.HasErrors() {
+		log.Warn().Any("req", req).Any("errors", errTemplates.Errors).Msg("repo::CreateRegistrations - some templates are missing or archived")
+		err = errTemplates
+		return nil, err
+	}
+
+	for i, item := range req.Registrations {
+		var (
+			exist   bool
+			regisId string
+		)
+
+		exist, err = r.isTemplateRegisteredInPeriod(ctx, tx, item.TemplateId, item.BillingPeriod)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to check data")
+			return nil, err
+		}
+
+		if exist {
+			log.Warn().Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - data already exist")
+			err = errmsg.NewCustomErrors(403).SetMessage(`Data dengan template id ` + item.TemplateId + ` sudah ada di periode ` + item.BillingPeriod)
+			return nil, err
+		}
+
+		regisId, err = r.insertRegistrationFromTemplate(ctx, tx, req.UserId, item)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to insert data")
+			return nil, err
+		}
+
+		resp.Add(entity.RegistrationBatchItem{
+			Index:      i,
+			TemplateId: item.TemplateId,
+			Status:     entity.RegistrationBatchStatusCreated,
+			Id:         &regisId,
+		})
+	}
+
+	return resp, nil
+}
+
+// createRegistrationsPartial saves every item on its own savepoint, so a
+// duplicate or failing template does not cancel the rest of the batch
+func (r *reportRepo) createRegistrationsPartial(ctx context.Context, req *entity.CreateRegistrationsReq) (*entity.RegistrationBatchResp, error) {
+	var resp = &entity.RegistrationBatchResp{Mode: req.Mode, Items: make([]entity.RegistrationBatchItem, 0)}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repo::CreateRegistrations - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i, item := range req.Registrations {
+		var result = entity.RegistrationBatchItem{
+			Index:      i,
+			TemplateId: item.TemplateId,
+			Status:     entity.RegistrationBatchStatusCreated,
+		}
+
+		errItem := r.withSavepoint(ctx, tx, func() error {
+			msg, err := r.checkTemplateRegistrable(ctx, tx, item.TemplateId)
+			if err != nil {
+				return err
+			}
+
+			if msg != "" {
+				return errmsg.NewCustomErrors(422).SetMessage(msg)
+			}
+
+			exist, err := r.isTemplateRegisteredInPeriod(ctx, tx, item.TemplateId, item.BillingPeriod)
+			if err != nil {
+				return err
+			}
+
+			if exist {
+				result.Status = entity.RegistrationBatchStatusSkippedDuplicate
+				msg = `Data dengan template id ` + item.TemplateId + ` sudah ada di periode ` + item.BillingPeriod
+				result.Message = &msg
+				return nil
+			}
+
+			regisId, err := r.insertRegistrationFromTemplate(ctx, tx, req.UserId, item)
+			if err != nil {
+				return err
+			}
+
+			result.Id = &regisId
+			return nil
+		})
+		if errItem != nil {
+			log.Warn().Err(errItem).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to create item in partial mode")
+			msg := batchItemErrorMessage(errItem)
+			result.Status = entity.RegistrationBatchStatusFailed
+			result.Message = &msg
+		}
+
+		resp.Add(result)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrations - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// checkTemplateRegistrable returns a user facing message when the template
+// is missing or archived, and an empty message when it can be registered
+func (r *reportRepo) checkTemplateRegistrable(ctx context.Context, tx *sqlx.Tx, templateId string) (string, error) {
+	queryTemplateStatus := `
+		SELECT
+			prt.deleted_at IS NOT NULL AS is_archived
+		FROM
+			program_registration_templates prt
+		WHERE
+			prt.id = ?
+	`
+
+	var isArchived bool
+	err := tx.GetContext(ctx, &isArchived, tx.Rebind(queryTemplateStatus), templateId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return `Template dengan id ` + templateId + ` tidak ditemukan`, nil
+		}
+		return "", err
+	}
+
+	if isArchived {
+		return `Template dengan id ` + templateId + ` sudah diarsipkan`, nil
+	}
+
+	return "", nil
+}
+
+// withSavepoint runs fn inside a savepoint, rolling back only fn's changes
+// when it fails
+func (r *reportRepo) withSavepoint(ctx context.Context, tx *sqlx.Tx, fn func() error) error {
+	if _, err := tx.ExecContext(ctx, `SAVEPOINT batch_item`); err != nil {
+		return err
+	}
+
+	if err := fn(); err != nil {
+		if _, errRB := tx.ExecContext(ctx, `ROLLBACK TO SAVEPOINT batch_item`); errRB != nil {
+			log.Error().Err(errRB).Msg("repo::withSavepoint - failed to rollback to savepoint")
+			return errRB
+		}
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `RELEASE SAVEPOINT batch_item`)
+	return err
+}
+
+func batchItemErrorMessage(err error) string {
+	if errCustom, ok := err.(*errmsg.CustomError); ok {
+		return errCustom.Msg
+	}
+
+	return "Gagal menyimpan data"
+}
+
+// isTemplateRegisteredInPeriod checks if program_id, lecturer_id, and student_id
+// of the template already have a registration in the billing period (YYYY-MM)
+func (r *reportRepo) isTemplateRegisteredInPeriod(ctx context.Context, tx *sqlx.Tx, templateId, billingPeriod string) (bool, error) {
+	queryCheck := `
+		SELECT EXISTS (
+			SELECT
+				1
+			FROM
+				program_registrations pr
+			JOIN
+				program_registration_templates prt
+				ON prt.id = ?
+			WHERE
+				pr.program_id = prt.program_id
+				AND pr.lecturer_id IS NOT DISTINCT FROM prt.lecturer_id
+				AND pr.student_id = prt.student_id
+				AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+				AND pr.deleted_at IS NULL
+		)
+	`
+
+	var exist bool
+	err := tx.GetContext(ctx, &exist, tx.Rebind(queryCheck), templateId, billingPeriod)
+	if err != nil {
+		return false, err
+	}
+
+	return exist, nil
+}
+
+// isRegistrationRegisteredInPeriod checks if program_id, lecturer_id, and student_id
+// of the registration already have a registration in the billing period (YYYY-MM)
+func (r *reportRepo) isRegistrationRegisteredInPeriod(ctx context.Context, tx *sqlx.Tx, regisId, billingPeriod string) (bool, error) {
+	queryCheck := `
+		SELECT EXISTS (
+			SELECT
+				1
+			FROM
+				program_registrations pr
+			JOIN
+				program_registrations source
+				ON source.id = ?
+			WHERE
+				pr.program_id = source.program_id
+				AND pr.lecturer_id IS NOT DISTINCT FROM source.lecturer_id
+				AND pr.student_id = source.student_id
+				AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+				AND pr.deleted_at IS NULL
+		)
+	`
+
+	var exist bool
+	err := tx.GetContext(ctx, &exist, tx.Rebind(queryCheck), regisId, billingPeriod)
+	if err != nil {
+		return false, err
+	}
+
+	return exist, nil
+}
+
+// insertRegistrationFromTemplate copies the template fees and its additional
+// students into a new registration, returning the new registration id. A
+// template that follows the program price takes the price in effect in the
+// billing period
+func (r *reportRepo) insertRegistrationFromTemplate(ctx context.Context, tx *sqlx.Tx, userId string, item entity.RegistrationItem) (string, error) {
+	var (
+		prId     = ulid.Make().String()
+		students = make([]entity.AddStudent, 0)
+	)
+
+	if err := checkPeriodOpen(ctx, tx, item.BillingPeriod); err != nil {
+		return "", err
+	}
+
 	query := `
 		INSERT INTO program_registrations (
 		id,
@@ -40,7 +303,6 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 		student_id,
 		program_name,
 		program_fee,
-		program_meetings,
 		administration_fee,
 		foreign_learning_fee,
 		night_learning_fee,
@@ -52,6 +314,7 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 		closing_fee_for_reward,
 		days,
 		notes,
+		billing_period,
 		started_at
 		)
 		SELECT
@@ -63,28 +326,38 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 			prt.marketer_id,
 			prt.student_id,
 			p.name,
-			prt.program_fee,
-			0,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programPriceSQL + `
+				ELSE prt.program_fee
+			END,
 			CASE
 				WHEN ? = TRUE THEN prt.administration_fee
 				ELSE NULL
 			END,
 			prt.foreign_learning_fee,
 			prt.night_learning_fee,
-			prt.marketer_commission_fee,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programCommissionFeeSQL + `
+				ELSE prt.marketer_commission_fee
+			END,
 			prt.overpayment_fee,
-			prt.hr_fee,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programLecturerFeeSQL + `
+				ELSE prt.hr_fee
+			END,
 			prt.marketer_gifts_fee,
 			prt.closing_fee_for_office,
 			prt.closing_fee_for_reward,
 			prt.days,
 			prt.notes,
+			TO_DATE(?, 'YYYY-MM'),
 			NOW()
 		FROM
 			program_registration_templates prt
 		JOIN
 			programs p
 			ON prt.program_id = p.id
+		` + programPriceJoinSQL + `
 		WHERE
 			prt.id = ?
 			AND prt.deleted_at IS NULL
@@ -99,7 +372,6 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 		WHERE
 			adds.prt_id = ?
 	`
-	queryStudents = r.db.Rebind(queryStudents)
 
 	queryInsertStudents := `
 		INSERT INTO pr_additional_students (
@@ -109,89 +381,54 @@ func (r *reportRepo) CreateRegistrations(ctx context.Context, req *entity.Create
 			name
 		) VALUES (?, ?, ?, ?)
 	`
-	queryInsertStudents = r.db.Rebind(queryInsertStudents)
-
-	for _, item := range req.Registrations {
-		var prId = ulid.Make().String()
-		var students = make([]entity.AddStudent, 0)
-
-		// check if program_id, lecturer_id, and student_id already exist in this month
-
-		queryCheck := `
-
-			SELECT EXISTS (
-				WITH template AS (
-					SELECT
-						prt.program_id,
-						prt.lecturer_id,
-						prt.student_id
-					FROM
-						program_registration_templates prt
-					WHERE
-						prt.id = ?
-						AND prt.deleted_at IS NULL
-				)
-				SELECT
-					1
-				FROM
-					program_registrations pr
-				WHERE
-					pr.program_id = (SELECT program_id FROM template)
-					AND pr.lecturer_id = (SELECT lecturer_id FROM template)
-					AND pr.student_id = (SELECT student_id FROM template)
-					AND EXTRACT(MONTH FROM pr.started_at) = EXTRACT(MONTH FROM NOW())
-					AND EXTRACT(YEAR FROM pr.started_at) = EXTRACT(YEAR FROM NOW())
-					AND pr.deleted_at IS NULL
-			)
-		`
-
-		var exist bool
-		err = tx.GetContext(ctx, &exist, tx.Rebind(queryCheck), item.TemplateId)
-		if err != nil {
-			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to check data")
-			return err
-		}
 
-		if exist {
-			log.Warn().Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - data already exist")
-			err = errmsg.NewCustomErrors(403).SetMessage(`Data dengan template id ` + item.TemplateId + ` sudah ada di bulan ini`)
-			return err
-		}
+	_, err := tx.ExecContext(ctx, tx.Rebind(query),
+		prId, item.TemplateId, userId, item.IsFirstRegistration, item.BillingPeriod, item.BillingPeriod, item.TemplateId,
+	)
+	if err != nil {
+		return "", err
+	}
 
-		_, err = tx.ExecContext(ctx, tx.Rebind(query),
-			prId, item.TemplateId, req.UserId, item.IsFirstRegistration, item.TemplateId,
+	err = tx.SelectContext(ctx, &students, tx.Rebind(queryStudents), item.TemplateId)
+	if err != nil {
+		return "", err
+	}
+
+	for _, student := range students {
+		_, err = tx.ExecContext(ctx, tx.Rebind(queryInsertStudents),
+			ulid.Make().String(), prId, student.StudentId, student.Name,
 		)
 		if err != nil {
-			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to insert data")
-			return err
+			return "", err
 		}
+	}
 
-		err = tx.SelectContext(ctx, &students, queryStudents, item.TemplateId)
-		if err != nil {
-			log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to fetch additional students")
-			return err
-		}
+	if _, err = applyHRFeeSplitRules(ctx, tx, `pr.id = ?`, prId); err != nil {
+		return "", err
+	}
 
-		for _, student := range students {
-			_, err = tx.ExecContext(ctx, queryInsertStudents,
-				ulid.Make().String(), prId, student.StudentId, student.Name,
-			)
-			if err != nil {
-				log.Error().Err(err).Any("req", req).Any("template_id", item.TemplateId).Msg("repo::CreateRegistrations - failed to insert additional students")
-				return err
-			}
-		}
+	if err = syncPlannedMeetings(ctx, tx, prId); err != nil {
+		return "", err
+	}
 
+	if err = audit.Created(ctx, tx, audit.Registration, prId); err != nil {
+		return "", err
 	}
 
-	return nil
+	return prId, nil
 }
 
-func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) error {
+func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) (*entity.RegistrationBatchResp, error) {
+	if req.Mode == entity.RegistrationBatchModePartial {
+		return r.copyRegistrationsPartial(ctx, req)
+	}
+
+	var resp = &entity.RegistrationBatchResp{Mode: req.Mode, Items: make([]entity.RegistrationBatchItem, 0)}
+
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Error().Err(err).Msg("repo::CopyRegistrations - failed to begin transaction")
-		return err
+		return nil, err
 	}
 	defer func() {
 		if err != nil {
@@ -207,6 +444,112 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 		}
 	}()
 
+	for i, item := range req.Registrations {
+		var (
+			exist   bool
+			regisId string
+		)
+
+		exist, err = r.isRegistrationRegisteredInPeriod(ctx, tx, item.RegisId, req.BillingPeriod)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to check data")
+			return nil, err
+		}
+
+		if exist {
+			log.Warn().Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - data already exist")
+			err = errmsg.NewCustomErrors(403).SetMessage(`Data dengan registrasi id ` + item.RegisId + ` sudah ada di periode ` + req.BillingPeriod)
+			return nil, err
+		}
+
+		regisId, err = r.copyRegistration(ctx, tx, req.UserId, item.RegisId, req.BillingPeriod)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to copy data")
+			return nil, err
+		}
+
+		resp.Add(entity.RegistrationBatchItem{
+			Index:          i,
+			RegistrationId: item.RegisId,
+			Status:         entity.RegistrationBatchStatusCreated,
+			Id:             &regisId,
+		})
+	}
+
+	return resp, nil
+}
+
+// copyRegistrationsPartial copies every item on its own savepoint
+func (r *reportRepo) copyRegistrationsPartial(ctx context.Context, req *entity.CopyRegistrationsReq) (*entity.RegistrationBatchResp, error) {
+	var resp = &entity.RegistrationBatchResp{Mode: req.Mode, Items: make([]entity.RegistrationBatchItem, 0)}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Msg("repo::CopyRegistrations - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for i, item := range req.Registrations {
+		var result = entity.RegistrationBatchItem{
+			Index:          i,
+			RegistrationId: item.RegisId,
+			Status:         entity.RegistrationBatchStatusCreated,
+		}
+
+		errItem := r.withSavepoint(ctx, tx, func() error {
+			exist, err := r.isRegistrationRegisteredInPeriod(ctx, tx, item.RegisId, req.BillingPeriod)
+			if err != nil {
+				return err
+			}
+
+			if exist {
+				msg := `Data dengan registrasi id ` + item.RegisId + ` sudah ada di periode ` + req.BillingPeriod
+				result.Status = entity.RegistrationBatchStatusSkippedDuplicate
+				result.Message = &msg
+				return nil
+			}
+
+			regisId, err := r.copyRegistration(ctx, tx, req.UserId, item.RegisId, req.BillingPeriod)
+			if err != nil {
+				return err
+			}
+
+			result.Id = &regisId
+			return nil
+		})
+		if errItem != nil {
+			log.Warn().Err(errItem).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to copy item in partial mode")
+			msg := batchItemErrorMessage(errItem)
+			result.Status = entity.RegistrationBatchStatusFailed
+			result.Message = &msg
+		}
+
+		resp.Add(result)
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CopyRegistrations - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// copyRegistration duplicates a registration and its additional students into
+// the billing period, returning the new registration id. A registration of a
+// template that follows the program price takes the price in effect in the
+// billing period
+func (r *reportRepo) copyRegistration(ctx context.Context, tx *sqlx.Tx, userId, regisId, billingPeriod string) (string, error) {
+	var (
+		prId     = ulid.Make().String()
+		students = make([]entity.AddStudent, 0)
+	)
+
+	if err := checkPeriodOpen(ctx, tx, billingPeriod); err != nil {
+		return "", err
+	}
+
 	query := `
 		INSERT INTO program_registrations (
 		id,
@@ -218,7 +561,6 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 		student_id,
 		program_name,
 		program_fee,
-		program_meetings,
 		administration_fee,
 		foreign_learning_fee,
 		night_learning_fee,
@@ -229,7 +571,8 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 		closing_fee_for_office,
 		closing_fee_for_reward,
 		days,
-		notes
+		notes,
+		billing_period
 		)
 		SELECT
 			?,
@@ -240,21 +583,37 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 			pr.marketer_id,
 			pr.student_id,
 			pr.program_name,
-			pr.program_fee,
-			pr.program_meetings,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programPriceSQL + `
+				ELSE pr.program_fee
+			END,
 			pr.administration_fee,
 			pr.foreign_learning_fee,
 			pr.night_learning_fee,
-			pr.marketer_commission_fee,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programCommissionFeeSQL + `
+				ELSE pr.marketer_commission_fee
+			END,
 			pr.overpayment_fee,
-			pr.hr_fee,
+			CASE
+				WHEN prt.follows_program_price THEN ` + programLecturerFeeSQL + `
+				ELSE pr.hr_fee
+			END,
 			pr.marketer_gifts_fee,
 			pr.closing_fee_for_office,
 			pr.closing_fee_for_reward,
 			pr.days,
-			pr.notes
+			pr.notes,
+			TO_DATE(?, 'YYYY-MM')
 		FROM
 			program_registrations pr
+		LEFT JOIN
+			program_registration_templates prt
+			ON pr.template_id = prt.id
+		LEFT JOIN
+			programs p
+			ON pr.program_id = p.id
+		` + programPriceJoinSQL + `
 		WHERE
 			pr.id = ?
 			AND pr.deleted_at IS NULL
@@ -269,7 +628,6 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 		WHERE
 			adds.pr_id = ?
 	`
-	queryStudents = r.db.Rebind(queryStudents)
 
 	queryInsertStudents := `
 		INSERT INTO pr_additional_students (
@@ -279,37 +637,46 @@ func (r *reportRepo) CopyRegistrations(ctx context.Context, req *entity.CopyRegi
 			name
 		) VALUES (?, ?, ?, ?)
 	`
-	queryInsertStudents = r.db.Rebind(queryInsertStudents)
 
-	for _, item := range req.Registrations {
-		var prId = ulid.Make().String()
-		var students = make([]entity.AddStudent, 0)
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), prId, userId, billingPeriod, billingPeriod, regisId)
+	if err != nil {
+		return "", err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	if affected == 0 {
+		return "", errmsg.NewCustomErrors(404).SetMessage(`Registrasi dengan id ` + regisId + ` tidak ditemukan`)
+	}
+
+	err = tx.SelectContext(ctx, &students, tx.Rebind(queryStudents), regisId)
+	if err != nil {
+		return "", err
+	}
 
-		_, err = tx.ExecContext(ctx, tx.Rebind(query),
-			prId, req.UserId, item.RegisId,
+	for _, student := range students {
+		_, err = tx.ExecContext(ctx, tx.Rebind(queryInsertStudents),
+			ulid.Make().String(), prId, student.StudentId, student.Name,
 		)
 		if err != nil {
-			log.Error().Err(err).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to insert data")
-			return err
+			return "", err
 		}
+	}
 
-		err = tx.SelectContext(ctx, &students, queryStudents, item.RegisId)
-		if err != nil {
-			log.Error().Err(err).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to fetch additional students")
-			return err
-		}
+	if _, err = applyHRFeeSplitRules(ctx, tx, `pr.id = ?`, prId); err != nil {
+		return "", err
+	}
 
-		for _, student := range students {
-			_, err = tx.ExecContext(ctx, queryInsertStudents,
-				ulid.Make().String(), prId, student.StudentId, student.Name,
-			)
-			if err != nil {
-				log.Error().Err(err).Any("req", req).Any("registration_id", item.RegisId).Msg("repo::CopyRegistrations - failed to insert additional students")
-				return err
-			}
-		}
+	if err = syncPlannedMeetings(ctx, tx, prId); err != nil {
+		return "", err
+	}
 
+	if err = audit.Created(ctx, tx, audit.Registration, prId); err != nil {
+		return "", err
 	}
 
-	return nil
+	return prId, nil
 }
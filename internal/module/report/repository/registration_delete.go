@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *reportRepo) DeleteRegistration(ctx context.Context, req *entity.DeleteRegistrationReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistration - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkRegistrationUnlocked(ctx, tx, req.Id); err != nil {
+		return err
+	}
+
+	if err = checkReceiptNotIssued(ctx, tx, req.Id); err != nil {
+		return err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			program_registrations
+		SET
+			deleted_at = NOW(),
+			deleted_by = ?,
+			deleted_reason = ?
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Reason, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistration - failed to delete data")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistration - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::DeleteRegistration - data not found")
+		return errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistration - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) RestoreRegistration(ctx context.Context, req *entity.RestoreRegistrationReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::RestoreRegistration - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	// the restored registration must not collide with an active registration
+	// of the same program, lecturer and student in the same billing period
+	queryCheck := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					program_registrations active
+				WHERE
+					active.id != deleted.id
+					AND active.program_id = deleted.program_id
+					AND active.student_id = deleted.student_id
+					AND active.lecturer_id IS NOT DISTINCT FROM deleted.lecturer_id
+					AND active.billing_period = deleted.billing_period
+					AND active.deleted_at IS NULL
+			)
+		FROM
+			program_registrations deleted
+		WHERE
+			deleted.id = ?
+			AND deleted.deleted_at IS NOT NULL
+	`
+
+	var exist bool
+	err = tx.GetContext(ctx, &exist, tx.Rebind(queryCheck), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::RestoreRegistration - deleted data not found")
+			return errmsg.NewCustomErrors(404).SetMessage("Registrasi yang dihapus tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::RestoreRegistration - failed to check data")
+		return err
+	}
+
+	if exist {
+		log.Warn().Any("req", req).Msg("repo::RestoreRegistration - active data already exist in the same billing period")
+		return errmsg.NewCustomErrors(409).SetMessage("Registrasi dengan program, pengajar, dan santri yang sama sudah ada di periode tersebut")
+	}
+
+	// restoring brings the registration back into the numbers of its period
+	if err = checkRegistrationUnlocked(ctx, tx, req.Id); err != nil {
+		return err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			program_registrations
+		SET
+			deleted_at = NULL,
+			deleted_by = NULL,
+			deleted_reason = NULL,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NOT NULL
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::RestoreRegistration - failed to restore data")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::RestoreRegistration - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) GetDeletedRegistrations(ctx context.Context, req *entity.GetDeletedRegistrationsReq) (*entity.GetDeletedRegistrationsResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.DeletedRegisItem
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetDeletedRegistrationsResp)
+		args = make([]any, 0, 4)
+	)
+	resp.Items = make([]entity.DeletedRegisItem, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			pr.id,
+			pr.program_id,
+			pr.marketer_id,
+			pr.lecturer_id,
+			pr.student_id,
+			pr.program_name,
+			pr.program_fee,
+			pr.deleted_by,
+			pr.deleted_reason,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			pr.paid_at,
+			pr.created_at,
+			pr.deleted_at,
+			u.name AS deleted_by_name,
+			l.name AS lecturer_name,
+			m.name AS marketer_name,
+			s.name AS student_name
+		FROM
+			program_registrations pr
+		LEFT JOIN
+			users u
+			ON pr.deleted_by = u.id
+		LEFT JOIN
+			lecturers l
+			ON pr.lecturer_id = l.id
+		JOIN
+			marketers m
+			ON pr.marketer_id = m.id
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		WHERE
+			pr.deleted_at IS NOT NULL
+	`
+
+	if req.Q != "" {
+		query += ` AND (
+			pr.program_name ILIKE '%' || ? || '%' OR
+			s.name ILIKE '%' || ? || '%'
+		)`
+		args = append(args, req.Q, req.Q)
+	}
+
+	sortTypeMap := map[string]string{
+		"asc":  "ASC",
+		"desc": "DESC",
+		"":     "DESC",
+	}
+
+	query += ` ORDER BY pr.deleted_at ` + sortTypeMap[req.SortType] + ` LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetDeletedRegistrations - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.DeletedRegisItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
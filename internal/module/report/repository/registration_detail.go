@@ -36,25 +36,25 @@ func (r *reportRepo) GetRegistration(ctx context.Context, req *entity.GetRegistr
 			pr.hr_fee,
 			pr.mentor_detail_fee AS hr_fee_for_mentor,
 			pr.hr_detail_fee AS hr_fee_for_hr,
+			pr.hr_fee_split_rule_id,
+			pr.hr_fee_split_manual,
 			pr.marketer_gifts_fee,
 			pr.closing_fee_for_office,
 			pr.closing_fee_for_reward,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			` + registrationTotalBillSQL + ` AS total_bill,
+			` + registrationTotalPaidSQL + ` AS total_paid,
+			` + registrationOutstandingSQL + ` AS outstanding_balance,
+			` + registrationPaymentStatusSQL + ` AS payment_status,
+			pr.approval_status,
+			pr.submitted_at,
+			pr.submitted_by,
+			pr.reviewed_at,
+			pr.reviewed_by,
+			pr.review_comment,
 			pr.created_at,
 			pr.updated_at,
 			pr.notes,
-			(
-				COALESCE(pr.administration_fee, 0)
-				+ COALESCE(pr.program_fee, 0)
-				+ COALESCE(pr.overpayment_fee, 0)
-				+ COALESCE(pr.night_learning_fee, 0)
-				+ COALESCE(pr.foreign_learning_fee, 0)
-				- COALESCE(pr.marketer_commission_fee, 0)
-				- COALESCE(pr.marketer_gifts_fee, 0)
-				- COALESCE(pr.hr_fee, 0)
-				- COALESCE(pr.overpayment_fee, 0)
-				- COALESCE(pr.closing_fee_for_office, 0)
-				- COALESCE(pr.closing_fee_for_reward, 0)
-			) AS profit,
 			l.name AS lecturer_name,
 			m.name AS marketer_name,
 			s.name AS student_name,
@@ -76,6 +76,7 @@ func (r *reportRepo) GetRegistration(ctx context.Context, req *entity.GetRegistr
 		JOIN
 			programs p
 			ON pr.program_id = p.id
+		` + registrationPaymentsJoinSQL + `
 		WHERE
 			pr.id = ?
 			AND pr.deleted_at IS NULL
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetRegistrationFeeSnapshots returns the fees of every active registration,
+// optionally of one billing period, with the values stored from them
+func (r *reportRepo) GetRegistrationFeeSnapshots(ctx context.Context, req *entity.RecomputeFeesReq) ([]entity.RegistrationFeeSnapshot, error) {
+	var (
+		data = make([]entity.RegistrationFeeSnapshot, 0)
+		args = make([]any, 0, 1)
+	)
+
+	query := `
+		SELECT
+			pr.id,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			pr.program_fee,
+			COALESCE(pr.administration_fee, 0) AS administration_fee,
+			COALESCE(pr.foreign_learning_fee, 0) AS foreign_learning_fee,
+			COALESCE(pr.night_learning_fee, 0) AS night_learning_fee,
+			COALESCE(pr.overpayment_fee, 0) AS overpayment_fee,
+			pr.marketer_commission_fee,
+			pr.marketer_gifts_fee,
+			pr.hr_fee,
+			COALESCE(pr.closing_fee_for_office, 0) AS closing_fee_for_office,
+			COALESCE(pr.closing_fee_for_reward, 0) AS closing_fee_for_reward,
+			pr.mentor_detail_fee AS hr_fee_for_mentor,
+			pr.hr_detail_fee AS hr_fee_for_hr
+		FROM
+			program_registrations pr
+		WHERE
+			pr.deleted_at IS NULL
+	`
+
+	if req.BillingPeriod != "" {
+		query += ` AND pr.billing_period = TO_DATE(?, 'YYYY-MM')`
+		args = append(args, req.BillingPeriod)
+	}
+
+	query += ` ORDER BY pr.billing_period ASC, pr.id ASC`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationFeeSnapshots - failed to fetch data")
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GenerateRegistrations creates the billing period's registration for every active,
+// finance-complete template. Every template runs in its own transaction so one
+// failure does not cancel the others.
+func (r *reportRepo) GenerateRegistrations(ctx context.Context, req *entity.GenerateRegistrationsReq) (*entity.GenerateRegistrationsResp, error) {
+	var (
+		resp      = new(entity.GenerateRegistrationsResp)
+		templates = make([]entity.GeneratedRegistration, 0)
+		now       = time.Now().UTC()
+	)
+	resp.Period = req.BillingPeriod
+	resp.StartedAt = now.Format(time.RFC3339)
+	resp.Created = make([]entity.GeneratedRegistration, 0)
+	resp.Skipped = make([]entity.GeneratedRegistration, 0)
+	resp.Failed = make([]entity.GeneratedRegistration, 0)
+
+	query := `
+		SELECT
+			prt.id AS template_id,
+			s.name AS student_name,
+			p.name AS program_name,
+			NOT EXISTS (
+				SELECT
+					1
+				FROM
+					program_registrations pr
+				WHERE
+					pr.template_id = prt.id
+					AND pr.deleted_at IS NULL
+			) AS is_first_registration
+		FROM
+			program_registration_templates prt
+		JOIN
+			students s
+			ON prt.student_id = s.id
+		JOIN
+			programs p
+			ON prt.program_id = p.id
+		WHERE
+			prt.deleted_at IS NULL
+			AND prt.program_fee IS NOT NULL
+		ORDER BY
+			s.name ASC,
+			p.name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &templates, r.db.Rebind(query))
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GenerateRegistrations - failed to fetch templates")
+		return nil, err
+	}
+
+	for _, template := range templates {
+		regisId, skipped, err := r.generateRegistration(ctx, req, template)
+		switch {
+		case err != nil:
+			reason := err.Error()
+			template.Reason = &reason
+			resp.Failed = append(resp.Failed, template)
+		case skipped:
+			reason := "sudah ada di periode " + req.BillingPeriod
+			template.Reason = &reason
+			resp.Skipped = append(resp.Skipped, template)
+		default:
+			template.RegistrationId = &regisId
+			resp.Created = append(resp.Created, template)
+		}
+	}
+
+	resp.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+
+	return resp, nil
+}
+
+func (r *reportRepo) generateRegistration(ctx context.Context, req *entity.GenerateRegistrationsReq, template entity.GeneratedRegistration) (regisId string, skipped bool, err error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("template", template).Msg("repo::GenerateRegistrations - failed to begin transaction")
+		return "", false, err
+	}
+	defer tx.Rollback()
+
+	exist, err := r.isTemplateRegisteredInPeriod(ctx, tx, template.TemplateId, req.BillingPeriod)
+	if err != nil {
+		log.Error().Err(err).Any("template", template).Msg("repo::GenerateRegistrations - failed to check data")
+		return "", false, err
+	}
+
+	if exist {
+		log.Info().Any("template", template).Msg("repo::GenerateRegistrations - data already exist, skipped")
+		return "", true, nil
+	}
+
+	regisId, err = r.insertRegistrationFromTemplate(ctx, tx, req.UserId, entity.RegistrationItem{
+		TemplateId:          template.TemplateId,
+		IsFirstRegistration: template.IsFirstRegistration,
+		BillingPeriod:       req.BillingPeriod,
+	})
+	if err != nil {
+		log.Error().Err(err).Any("template", template).Msg("repo::GenerateRegistrations - failed to insert data")
+		return "", false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("template", template).Msg("repo::GenerateRegistrations - failed to commit transaction")
+		return "", false, err
+	}
+
+	return regisId, false, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+var invoiceDataQuery = `
+	SELECT
+		pr.id AS registration_id,
+		TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+		s.identifier AS student_identifier,
+		s.name AS student_name,
+		sm.name AS student_manager_name,
+		pr.program_name,
+		m.name AS marketer_name,
+		l.name AS lecturer_name,
+		COALESCE(pr.program_fee, 0) AS program_fee,
+		COALESCE(pr.administration_fee, 0) AS administration_fee,
+		COALESCE(pr.foreign_learning_fee, 0) AS foreign_learning_fee,
+		COALESCE(pr.night_learning_fee, 0) AS night_learning_fee,
+		COALESCE(pr.overpayment_fee, 0) AS overpayment_fee,
+		` + registrationTotalBillSQL + ` AS total_bill,
+		` + registrationTotalPaidSQL + ` AS total_paid,
+		` + registrationOutstandingSQL + ` AS outstanding_balance,
+		` + registrationPaymentStatusSQL + ` AS payment_status
+	FROM
+		program_registrations pr
+	LEFT JOIN
+		lecturers l
+		ON pr.lecturer_id = l.id
+	JOIN
+		marketers m
+		ON pr.marketer_id = m.id
+	JOIN
+		student_managers sm
+		ON m.student_manager_id = sm.id
+	JOIN
+		students s
+		ON pr.student_id = s.id
+	` + registrationPaymentsJoinSQL
+
+func (r *reportRepo) GetInvoiceData(ctx context.Context, req *entity.GetInvoiceReq) (*entity.InvoiceData, error) {
+	var (
+		data = make([]entity.InvoiceData, 0, 1)
+	)
+
+	query := invoiceDataQuery + `
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetInvoiceData - failed to fetch data")
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		log.Warn().Any("req", req).Msg("repo::GetInvoiceData - data not found")
+		return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan atau belum disetujui")
+	}
+
+	if err = r.fillInvoiceStudents(ctx, data); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetInvoiceData - failed to fetch additional students")
+		return nil, err
+	}
+
+	return &data[0], nil
+}
+
+func (r *reportRepo) GetInvoicesDataByPeriod(ctx context.Context, req *entity.GenerateInvoicesReq) ([]entity.InvoiceData, error) {
+	var (
+		data = make([]entity.InvoiceData, 0)
+	)
+
+	query := invoiceDataQuery + `
+		WHERE
+			pr.billing_period = TO_DATE(?, 'YYYY-MM')
+			AND pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+		ORDER BY
+			s.name ASC, pr.program_name ASC
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.BillingPeriod)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetInvoicesDataByPeriod - failed to fetch data")
+		return nil, err
+	}
+
+	if err = r.fillInvoiceStudents(ctx, data); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetInvoicesDataByPeriod - failed to fetch additional students")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *reportRepo) fillInvoiceStudents(ctx context.Context, data []entity.InvoiceData) error {
+	type dao struct {
+		PrId string `db:"pr_id"`
+		entity.AddStudent
+	}
+	var (
+		students        = make([]dao, 0)
+		registrationIds = make([]string, 0, len(data))
+	)
+
+	for i := range data {
+		data[i].Students = make([]entity.AddStudent, 0)
+		registrationIds = append(registrationIds, data[i].RegistrationId)
+	}
+
+	if len(registrationIds) == 0 {
+		return nil
+	}
+
+	query := `
+		SELECT
+			prs.pr_id,
+			prs.student_id,
+			CASE
+				WHEN s.id IS NULL THEN prs.name
+				ELSE s.name
+			END AS name
+		FROM
+			pr_additional_students prs
+		LEFT JOIN
+			students s
+			ON prs.student_id = s.id
+		WHERE prs.pr_id IN (?)
+	`
+
+	query, args, err := sqlx.In(query, registrationIds)
+	if err != nil {
+		return err
+	}
+
+	err = r.db.SelectContext(ctx, &students, r.db.Rebind(query), args...)
+	if err != nil {
+		return err
+	}
+
+	for i := range data {
+		for _, student := range students {
+			if data[i].RegistrationId == student.PrId {
+				data[i].Students = append(data[i].Students, student.AddStudent)
+			}
+		}
+	}
+
+	return nil
+}
@@ -40,9 +40,17 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 			pr.hr_fee,
 			pr.mentor_detail_fee AS hr_fee_for_mentor,
 			pr.hr_detail_fee AS hr_fee_for_hr,
+			pr.hr_fee_split_rule_id,
+			pr.hr_fee_split_manual,
 			pr.marketer_gifts_fee,
 			pr.closing_fee_for_office,
 			pr.closing_fee_for_reward,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			` + registrationTotalBillSQL + ` AS total_bill,
+			` + registrationTotalPaidSQL + ` AS total_paid,
+			` + registrationOutstandingSQL + ` AS outstanding_balance,
+			` + registrationPaymentStatusSQL + ` AS payment_status,
+			pr.approval_status,
 			pr.paid_at,
 			pr.created_at,
 			pr.updated_at,
@@ -52,19 +60,6 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 			COALESCE(pr.night_learning_fee, 0) +
 			COALESCE(pr.overpayment_fee, 0)
 			AS monthly_fee,
-			(
-				COALESCE(pr.administration_fee, 0)
-				+ COALESCE(pr.program_fee, 0)
-				+ COALESCE(pr.overpayment_fee, 0)
-				+ COALESCE(pr.night_learning_fee, 0)
-				+ COALESCE(pr.foreign_learning_fee, 0)
-				- COALESCE(pr.marketer_commission_fee, 0)
-				- COALESCE(pr.marketer_gifts_fee, 0)
-				- COALESCE(pr.hr_fee, 0)
-				- COALESCE(pr.overpayment_fee, 0)
-				- COALESCE(pr.closing_fee_for_office, 0)
-				- COALESCE(pr.closing_fee_for_reward, 0)
-			) AS profit,
 			l.name AS lecturer_name,
 			m.name AS marketer_name,
 			s.name AS student_name
@@ -82,6 +77,7 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 		JOIN
 			programs p
 			ON pr.program_id = p.id
+		` + registrationPaymentsJoinSQL + `
 		WHERE
 			pr.deleted_at IS NULL
 	`
@@ -95,6 +91,11 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 		args = append(args, req.Timezone, req.PaidAtFrom, req.PaidAtTo)
 	}
 
+	if req.BillingPeriodFrom != "" && req.BillingPeriodTo != "" {
+		query += ` AND pr.billing_period BETWEEN TO_DATE(?, 'YYYY-MM') AND TO_DATE(?, 'YYYY-MM')`
+		args = append(args, req.BillingPeriodFrom, req.BillingPeriodTo)
+	}
+
 	if req.Q != "" {
 		query += ` AND (
 			pr.program_name ILIKE '%' || ? || '%' OR
@@ -123,12 +124,23 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 		args = append(args, req.ProgramId)
 	}
 
+	if req.PaymentStatus != "" {
+		query += ` AND ` + registrationPaymentStatusSQL + ` = ?`
+		args = append(args, req.PaymentStatus)
+	}
+
+	if req.ApprovalStatus != "" {
+		query += ` AND pr.approval_status = ?`
+		args = append(args, req.ApprovalStatus)
+	}
+
 	sortByMap := map[string]string{
-		"created_at":   "pr.created_at",
-		"paid_at":      "pr.paid_at",
-		"updated_at":   "pr.updated_at",
-		"student_name": "s.name",
-		"":             "pr.paid_at",
+		"created_at":     "pr.created_at",
+		"paid_at":        "pr.paid_at",
+		"billing_period": "pr.billing_period",
+		"updated_at":     "pr.updated_at",
+		"student_name":   "s.name",
+		"":               "pr.paid_at",
 	}
 
 	sortTypeMap := map[string]string{
@@ -137,7 +149,7 @@ func (r *reportRepo) GetRegistrations(ctx context.Context, req *entity.GetRegist
 		"":     "DESC",
 	}
 
-	query += ` ORDER BY ` + sortByMap[req.SortBy] + ` ` + sortTypeMap[req.SortType] + ` LIMIT ? OFFSET ?`
+	query += ` ORDER BY ` + sortByMap[req.SortBy] + ` ` + sortTypeMap[req.SortType] + `, pr.id ASC LIMIT ? OFFSET ?`
 	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
 
 	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
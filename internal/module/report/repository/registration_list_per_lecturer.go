@@ -46,7 +46,9 @@ func (r *reportRepo) GetRegistrationsPerLecturer(ctx context.Context, req *entit
 			students s ON pr.student_id = s.id
 		LEFT JOIN
 			lecturers l ON pr.lecturer_id = l.id
-		WHERE 1 = 1
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
 	`
 	if req.Q != "" {
 		query += ` AND (l.name ILIKE ? OR s.name ILIKE ?)`
@@ -64,7 +66,8 @@ func (r *reportRepo) GetRegistrationsPerLecturer(ctx context.Context, req *entit
 		ORDER BY
 			pr.lecturer_id ASC,
 			pr.student_id ASC,
-			p.name ASC
+			p.name ASC,
+			pr.program_id ASC
 		LIMIT ? OFFSET ?
 	`
 	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
@@ -95,7 +98,7 @@ func (r *reportRepo) GetRegistrationsPerLecturer(ctx context.Context, req *entit
 		return resp, nil
 	}
 
-	args = make([]any, 0, 3)
+	args = make([]any, 0, 4)
 
 	query = `
 		WITH months AS (
@@ -117,12 +120,14 @@ func (r *reportRepo) GetRegistrationsPerLecturer(ctx context.Context, req *entit
 			m.month_num,
 			pr.id AS registration_id,
 			pr.mentor_detail_fee AS hr_fee_for_lecturer,
-			pr.mentor_detail_fee_used AS used_amount,
+			mfu_sum.total_used AS used_amount,
+			` + registrationMentorFeeRemainingSQL + ` AS remaining_amount,
 			CASE
-				WHEN pr.mentor_detail_fee_used IS NOT NULL THEN TRUE
+				WHEN mfu_sum.total_used > 0 THEN TRUE
 				ELSE NULL
 			END AS is_used,
-			pr.notes_for_fund_distributions AS notes,
+			COALESCE(mfu_sum.last_notes, pr.notes_for_fund_distributions) AS notes,
+			TO_CHAR(mfu_sum.last_used_at AT TIME ZONE ?, 'YYYY-MM-DD') AS used_at,
 
 			pr.program_id,
 			pr.lecturer_id,
@@ -133,13 +138,15 @@ func (r *reportRepo) GetRegistrationsPerLecturer(ctx context.Context, req *entit
 			program_registrations pr
 		JOIN
 			months m
-			ON EXTRACT(MONTH FROM (pr.started_at AT TIME ZONE ?)) = m.month_num
+			ON EXTRACT(MONTH FROM pr.billing_period) = m.month_num
+		` + registrationMentorFeeUsagesJoinSQL + `
 		WHERE
 			pr.deleted_at IS NULL
-			AND EXTRACT(YEAR FROM (pr.started_at AT TIME ZONE ?)) = ?
+			AND ` + registrationApprovedSQL + `
+			AND EXTRACT(YEAR FROM pr.billing_period) = ?
 		`
 
-	args = append(args, req.Tz, req.Tz, req.Year)
+	args = append(args, req.Tz, req.Year)
 
 	if len(argsCombine) > 0 {
 		query += ` AND (`
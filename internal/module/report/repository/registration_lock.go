@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+// the fragments below expect program_registrations aliased as pr and tell
+// whether the registration is settled in a payout and must not change anymore
+const (
+	registrationCommissionPaidSQL = `EXISTS (
+		SELECT
+			1
+		FROM
+			commission_payout_items cpi
+		WHERE
+			cpi.registration_id = pr.id
+			AND cpi.unlocked_at IS NULL
+	)`
+
+	registrationLecturerStatementSQL = `EXISTS (
+		SELECT
+			1
+		FROM
+			lecturer_payout_statement_items lpsi
+		JOIN
+			lecturer_payout_statements lps
+			ON lpsi.statement_id = lps.id
+		WHERE
+			lpsi.registration_id = pr.id
+			AND lps.status != 'draft'
+	)`
+
+	registrationPeriodClosedSQL = `EXISTS (
+		SELECT
+			1
+		FROM
+			accounting_periods ap
+		WHERE
+			ap.period = pr.billing_period
+			AND ap.status = 'closed'
+	)`
+
+	registrationLockedSQL = `(` + registrationCommissionPaidSQL + ` OR ` + registrationLecturerStatementSQL + ` OR ` + registrationPeriodClosedSQL + `)`
+)
+
+// lockPeriod takes the advisory lock of an accounting period until the end of
+// the transaction, the period is formatted as YYYY-MM. Changes to the
+// registrations of a period take it shared and closing or reopening the period
+// takes it exclusively, so a period can not close while its registrations change.
+func lockPeriod(ctx context.Context, q sqlx.ExtContext, period string, exclusive bool) error {
+	query := `SELECT pg_advisory_xact_lock_shared(HASHTEXT('accounting_period:' || ?))`
+	if exclusive {
+		query = `SELECT pg_advisory_xact_lock(HASHTEXT('accounting_period:' || ?))`
+	}
+
+	_, err := q.ExecContext(ctx, q.Rebind(query), period)
+	if err != nil {
+		log.Error().Err(err).Str("period", period).Bool("exclusive", exclusive).Msg("repo::lockPeriod - failed to lock accounting period")
+		return err
+	}
+
+	return nil
+}
+
+// checkRegistrationUnlocked rejects changes to a registration whose values are
+// already settled elsewhere, e.g. a marketer commission that has been paid out.
+// It holds the period lock of the registration until the end of the
+// transaction, a missing registration is left to the caller to report.
+func checkRegistrationUnlocked(ctx context.Context, q sqlx.ExtContext, registrationId string) error {
+	var (
+		period string
+		lock   struct {
+			CommissionPaid    bool `db:"commission_paid"`
+			LecturerStatement bool `db:"lecturer_statement"`
+			PeriodClosed      bool `db:"period_closed"`
+		}
+	)
+
+	query := `SELECT TO_CHAR(pr.billing_period, 'YYYY-MM') FROM program_registrations pr WHERE pr.id = ?`
+
+	err := sqlx.GetContext(ctx, q, &period, q.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::checkRegistrationUnlocked - failed to fetch billing period")
+		return err
+	}
+
+	if err = lockPeriod(ctx, q, period, false); err != nil {
+		return err
+	}
+
+	query = `
+		SELECT
+			` + registrationCommissionPaidSQL + ` AS commission_paid,
+			` + registrationLecturerStatementSQL + ` AS lecturer_statement,
+			` + registrationPeriodClosedSQL + ` AS period_closed
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+	`
+
+	err = sqlx.GetContext(ctx, q, &lock, q.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::checkRegistrationUnlocked - failed to check locks")
+		return err
+	}
+
+	if lock.PeriodClosed {
+		log.Warn().Any("registration_id", registrationId).Msg("repo::checkRegistrationUnlocked - accounting period of the registration is closed")
+		return errmsg.NewCustomErrors(409).SetMessage("Periode akuntansi registrasi sudah ditutup, buka kembali periode terlebih dahulu")
+	}
+
+	if lock.CommissionPaid {
+		log.Warn().Any("registration_id", registrationId).Msg("repo::checkRegistrationUnlocked - registration is in a commission payout batch")
+		return errmsg.NewCustomErrors(409).SetMessage("Registrasi sudah masuk batch pembayaran komisi, buka kunci batch terlebih dahulu")
+	}
+
+	if lock.LecturerStatement {
+		log.Warn().Any("registration_id", registrationId).Msg("repo::checkRegistrationUnlocked - registration is in an approved lecturer statement")
+		return errmsg.NewCustomErrors(409).SetMessage("Registrasi sudah masuk slip honor pengajar yang disetujui, buka kembali slip terlebih dahulu")
+	}
+
+	return nil
+}
+
+// checkPeriodOpen rejects new registrations in a closed accounting period and
+// holds the period lock until the end of the transaction, the billing period
+// is formatted as YYYY-MM
+func checkPeriodOpen(ctx context.Context, q sqlx.ExtContext, billingPeriod string) error {
+	var closed bool
+
+	if err := lockPeriod(ctx, q, billingPeriod, false); err != nil {
+		return err
+	}
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					accounting_periods ap
+				WHERE
+					ap.period = TO_DATE(?, 'YYYY-MM')
+					AND ap.status = 'closed'
+			)
+	`
+
+	err := sqlx.GetContext(ctx, q, &closed, q.Rebind(query), billingPeriod)
+	if err != nil {
+		log.Error().Err(err).Str("billing_period", billingPeriod).Msg("repo::checkPeriodOpen - failed to check accounting period")
+		return err
+	}
+
+	if closed {
+		log.Warn().Str("billing_period", billingPeriod).Msg("repo::checkPeriodOpen - accounting period is closed")
+		return errmsg.NewCustomErrors(409).SetMessage("Periode akuntansi " + billingPeriod + " sudah ditutup, buka kembali periode terlebih dahulu")
+	}
+
+	return nil
+}
@@ -0,0 +1,581 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// registrationPlannedMeetingsSQL counts the sessions of program_registrations
+	// aliased as pr
+	registrationPlannedMeetingsSQL = `(SELECT COUNT(*) FROM ` + registrationSessionsSQL + ` sd)`
+
+	// userLecturerSQL limits program_registrations aliased as pr to the ones of
+	// the lecturer bound to the user of its placeholder
+	userLecturerSQL = `EXISTS (
+		SELECT
+			1
+		FROM
+			users u
+		WHERE
+			u.lecturer_id = pr.lecturer_id
+			AND u.id = ?
+	)`
+)
+
+// syncPlannedMeetings recounts program_meetings from the days of the registration
+func syncPlannedMeetings(ctx context.Context, q sqlx.ExtContext, registrationId string) error {
+	query := `
+		UPDATE
+			program_registrations pr
+		SET
+			program_meetings = ` + registrationPlannedMeetingsSQL + `
+		WHERE
+			pr.id = ?
+	`
+
+	_, err := q.ExecContext(ctx, q.Rebind(query), registrationId)
+	if err != nil {
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::syncPlannedMeetings - failed to update planned meetings")
+		return err
+	}
+
+	return nil
+}
+
+// syncCompletedMeetings recounts program_meetings_completed from the recorded
+// meetings and returns both counters of the registration
+func syncCompletedMeetings(ctx context.Context, q sqlx.ExtContext, registrationId string) (*entity.RegistrationMeetingCount, error) {
+	var count = new(entity.RegistrationMeetingCount)
+
+	query := `
+		UPDATE
+			program_registrations pr
+		SET
+			program_meetings_completed = (
+				SELECT
+					COUNT(*)
+				FROM
+					registration_meetings rm
+				WHERE
+					rm.registration_id = pr.id
+			),
+			updated_at = NOW()
+		WHERE
+			pr.id = ?
+		RETURNING
+			pr.id AS registration_id,
+			pr.program_meetings,
+			pr.program_meetings_completed
+	`
+
+	err := sqlx.GetContext(ctx, q, count, q.Rebind(query), registrationId)
+	if err != nil {
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::syncCompletedMeetings - failed to update completed meetings")
+		return nil, err
+	}
+
+	return count, nil
+}
+
+// meetingRegistration is a registration locked for recording its meetings
+type meetingRegistration struct {
+	LecturerId    *string `db:"lecturer_id"`
+	StudentId     string  `db:"student_id"`
+	StudentName   string  `db:"student_name"`
+	BillingPeriod string  `db:"billing_period"`
+	IsLecturer    bool    `db:"is_lecturer"` // the user is the lecturer of the registration
+}
+
+// lockMeetingRegistration locks the registration for update, a user with the
+// lecturer role may only change the meetings of their own registrations
+func lockMeetingRegistration(ctx context.Context, tx *sqlx.Tx, registrationId, userId, role string) (*meetingRegistration, error) {
+	return getMeetingRegistration(ctx, tx, registrationId, userId, role, " FOR UPDATE OF pr")
+}
+
+// getMeetingRegistration returns the registration of meetings, a user with the
+// lecturer role may only see the meetings of their own registrations
+func getMeetingRegistration(ctx context.Context, q sqlx.ExtContext, registrationId, userId, role, lock string) (*meetingRegistration, error) {
+	var reg = new(meetingRegistration)
+
+	query := `
+		SELECT
+			pr.lecturer_id,
+			pr.student_id,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			s.name AS student_name,
+			COALESCE(pr.lecturer_id = u.lecturer_id, FALSE) AS is_lecturer
+		FROM
+			program_registrations pr
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		LEFT JOIN
+			users u
+			ON u.id = ?
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	` + lock
+
+	err := sqlx.GetContext(ctx, q, reg, q.Rebind(query), userId, registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("registration_id", registrationId).Msg("repo::getMeetingRegistration - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+		}
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::getMeetingRegistration - failed to fetch data")
+		return nil, err
+	}
+
+	if role == types.RoleLecturer && !reg.IsLecturer {
+		log.Warn().Str("registration_id", registrationId).Str("user_id", userId).Msg("repo::getMeetingRegistration - user is not the lecturer of the registration")
+		return nil, errmsg.NewCustomErrors(403).SetMessage("Pertemuan hanya dapat diakses oleh pengajar registrasi ini")
+	}
+
+	return reg, nil
+}
+
+// resolveMeetingAttendees matches the attendees with the main and additional
+// students of the registration and returns them with their current names
+func resolveMeetingAttendees(ctx context.Context, tx *sqlx.Tx, registrationId string, reg *meetingRegistration, attendees []entity.AddStudent) ([]entity.AddStudent, error) {
+	var additional []entity.AddStudent
+
+	query := `
+		SELECT
+			student_id,
+			name
+		FROM
+			pr_additional_students
+		WHERE
+			pr_id = ?
+			AND deleted_at IS NULL
+	`
+
+	err := tx.SelectContext(ctx, &additional, tx.Rebind(query), registrationId)
+	if err != nil {
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::resolveMeetingAttendees - failed to fetch additional students")
+		return nil, err
+	}
+
+	resolved, errs := matchMeetingAttendees(reg, additional, attendees)
+	if errs != nil {
+		log.Warn().Str("registration_id", registrationId).Any("attendees", attendees).Msg("repo::resolveMeetingAttendees - invalid attendees")
+		return nil, errs
+	}
+
+	return resolved, nil
+}
+
+// matchMeetingAttendees matches the attendees by student id, or by name for
+// additional students without one, each student attends at most once
+func matchMeetingAttendees(reg *meetingRegistration, additional, attendees []entity.AddStudent) ([]entity.AddStudent, *errmsg.CustomError) {
+	var (
+		byStudentId = map[string]string{reg.StudentId: reg.StudentName}
+		byName      = make(map[string]string)
+		seen        = make(map[string]bool)
+		resolved    = make([]entity.AddStudent, 0, len(attendees))
+		errs        = errmsg.NewCustomErrors(422)
+	)
+	for _, s := range additional {
+		if s.StudentId != nil {
+			byStudentId[*s.StudentId] = *s.Name
+		} else {
+			byName[strings.ToLower(strings.TrimSpace(*s.Name))] = *s.Name
+		}
+	}
+
+	for i, a := range attendees {
+		var (
+			key   string
+			field string
+			name  string
+			ok    bool
+		)
+		if a.StudentId != nil {
+			key, field = "id:"+*a.StudentId, fmt.Sprintf("attendees[%d].student_id", i)
+			name, ok = byStudentId[*a.StudentId]
+		} else {
+			key, field = "name:"+strings.ToLower(strings.TrimSpace(*a.Name)), fmt.Sprintf("attendees[%d].name", i)
+			name, ok = byName[strings.ToLower(strings.TrimSpace(*a.Name))]
+		}
+
+		if !ok {
+			errs.Add(field, "bukan siswa dari registrasi ini")
+			continue
+		}
+		if seen[key] {
+			errs.Add(field, "siswa sudah tercatat hadir")
+			continue
+		}
+		seen[key] = true
+
+		resolved = append(resolved, entity.AddStudent{StudentId: a.StudentId, Name: &name})
+	}
+
+	if errs.HasErrors() {
+		return nil, errs
+	}
+
+	return resolved, nil
+}
+
+func (r *reportRepo) CreateRegistrationMeeting(ctx context.Context, req *entity.CreateRegistrationMeetingReq) (*entity.CreateRegistrationMeetingResp, error) {
+	var (
+		resp = new(entity.CreateRegistrationMeetingResp)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationMeeting - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	reg, err := lockMeetingRegistration(ctx, tx, req.RegistrationId, req.UserId, req.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = req.ValidateBillingPeriod(reg.BillingPeriod); err != nil {
+		log.Warn().Any("req", req).Str("billing_period", reg.BillingPeriod).Msg("repo::CreateRegistrationMeeting - meeting is outside the billing period")
+		return nil, err
+	}
+
+	attendees, err := resolveMeetingAttendees(ctx, tx, req.RegistrationId, reg, req.Attendees)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		INSERT INTO registration_meetings (
+			id,
+			registration_id,
+			lecturer_id,
+			user_id,
+			met_on,
+			duration_minutes,
+			notes
+		) VALUES (?, ?, ?, ?, TO_DATE(?, 'YYYY-MM-DD'), ?, ?)
+	`
+
+	resp.Id = ulid.Make().String()
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		resp.Id,
+		req.RegistrationId,
+		reg.LecturerId,
+		req.UserId,
+		req.MetOn,
+		req.DurationMinutes,
+		req.Notes,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationMeeting - failed to insert data")
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO registration_meeting_attendees (
+			id,
+			meeting_id,
+			student_id,
+			name
+		) VALUES (?, ?, ?, ?)
+	`
+
+	for _, a := range attendees {
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), ulid.Make().String(), resp.Id, a.StudentId, a.Name)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationMeeting - failed to insert attendee")
+			return nil, err
+		}
+	}
+
+	count, err := syncCompletedMeetings(ctx, tx, req.RegistrationId)
+	if err != nil {
+		return nil, err
+	}
+	resp.RegistrationMeetingCount = *count
+
+	if err = audit.Created(ctx, tx, audit.RegistrationMeeting, resp.Id); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationMeeting - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetRegistrationMeetings(ctx context.Context, req *entity.GetRegistrationMeetingsReq) (*entity.GetRegistrationMeetingsResp, error) {
+	var (
+		resp      = new(entity.GetRegistrationMeetingsResp)
+		attendees []struct {
+			MeetingId string `db:"meeting_id"`
+			entity.AddStudent
+		}
+	)
+	resp.Items = make([]entity.RegistrationMeeting, 0)
+
+	if _, err := getMeetingRegistration(ctx, r.db, req.RegistrationId, req.UserId, req.Role, ""); err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT
+			pr.id AS registration_id,
+			pr.program_meetings,
+			pr.program_meetings_completed
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, &resp.RegistrationMeetingCount, r.db.Rebind(query), req.RegistrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetRegistrationMeetings - registration not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationMeetings - failed to fetch registration")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			rm.id,
+			rm.lecturer_id,
+			l.name AS lecturer_name,
+			rm.user_id,
+			u.name AS user_name,
+			TO_CHAR(rm.met_on, 'YYYY-MM-DD') AS met_on,
+			rm.duration_minutes,
+			rm.notes,
+			rm.created_at
+		FROM
+			registration_meetings rm
+		LEFT JOIN
+			lecturers l
+			ON rm.lecturer_id = l.id
+		LEFT JOIN
+			users u
+			ON rm.user_id = u.id
+		WHERE
+			rm.registration_id = ?
+		ORDER BY
+			rm.met_on DESC, rm.id DESC
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Items, r.db.Rebind(query), req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationMeetings - failed to fetch meetings")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			rma.meeting_id,
+			rma.student_id,
+			rma.name
+		FROM
+			registration_meeting_attendees rma
+		JOIN
+			registration_meetings rm
+			ON rma.meeting_id = rm.id
+		WHERE
+			rm.registration_id = ?
+		ORDER BY
+			rma.name
+	`
+
+	err = r.db.SelectContext(ctx, &attendees, r.db.Rebind(query), req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationMeetings - failed to fetch attendees")
+		return nil, err
+	}
+
+	byMeeting := make(map[string][]entity.AddStudent)
+	for _, a := range attendees {
+		byMeeting[a.MeetingId] = append(byMeeting[a.MeetingId], a.AddStudent)
+	}
+
+	for i := range resp.Items {
+		resp.Items[i].Attendees = byMeeting[resp.Items[i].Id]
+		if resp.Items[i].Attendees == nil {
+			resp.Items[i].Attendees = make([]entity.AddStudent, 0)
+		}
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) DeleteRegistrationMeeting(ctx context.Context, req *entity.DeleteRegistrationMeetingReq) (*entity.RegistrationMeetingCount, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationMeeting - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err = lockMeetingRegistration(ctx, tx, req.RegistrationId, req.UserId, req.Role); err != nil {
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.RegistrationMeeting, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		DELETE FROM
+			registration_meetings
+		WHERE
+			id = ?
+			AND registration_id = ?
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id, req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationMeeting - failed to delete data")
+		return nil, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationMeeting - failed to get affected rows")
+		return nil, err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::DeleteRegistrationMeeting - data not found")
+		return nil, errmsg.NewCustomErrors(404).SetMessage("Pertemuan tidak ditemukan")
+	}
+
+	count, err := syncCompletedMeetings(ctx, tx, req.RegistrationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationMeeting - failed to commit transaction")
+		return nil, err
+	}
+
+	return count, nil
+}
+
+// GetAttendances compares the planned and delivered meetings of the approved
+// registrations of a billing period, grouped per lecturer
+func (r *reportRepo) GetAttendances(ctx context.Context, req *entity.GetAttendancesReq) (*entity.GetAttendancesResp, error) {
+	var (
+		data = make([]entity.RegistrationAttendance, 0)
+		resp = new(entity.GetAttendancesResp)
+		args = make([]any, 0, 2)
+	)
+	resp.BillingPeriod = req.BillingPeriod
+	resp.Lecturers = make([]entity.LecturerAttendance, 0)
+
+	query := `
+		SELECT
+			pr.id AS registration_id,
+			pr.lecturer_id,
+			l.name AS lecturer_name,
+			s.name AS student_name,
+			pr.program_name,
+			pr.program_meetings AS planned,
+			COUNT(rm.id) AS delivered,
+			COALESCE(SUM(rm.duration_minutes), 0) AS total_minutes,
+			COALESCE(SUM((
+				SELECT
+					COUNT(*)
+				FROM
+					registration_meeting_attendees rma
+				WHERE
+					rma.meeting_id = rm.id
+			)), 0) AS total_attendees,
+			TO_CHAR(MAX(rm.met_on), 'YYYY-MM-DD') AS last_met_on
+		FROM
+			program_registrations pr
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		LEFT JOIN
+			lecturers l
+			ON pr.lecturer_id = l.id
+		LEFT JOIN
+			registration_meetings rm
+			ON rm.registration_id = pr.id
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+			AND pr.billing_period = TO_DATE(?, 'YYYY-MM')
+	`
+	args = append(args, req.BillingPeriod)
+
+	if req.LecturerId != "" {
+		query += ` AND pr.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	if req.Role == types.RoleLecturer {
+		query += ` AND ` + userLecturerSQL
+		args = append(args, req.UserId)
+	}
+
+	query += `
+		GROUP BY
+			pr.id, l.name, s.name
+		ORDER BY
+			l.name NULLS LAST, pr.lecturer_id, s.name, pr.id
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetAttendances - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		n := len(resp.Lecturers)
+		if n == 0 || !sameLecturer(resp.Lecturers[n-1].LecturerId, item.LecturerId) {
+			resp.Lecturers = append(resp.Lecturers, entity.LecturerAttendance{
+				LecturerId:    item.LecturerId,
+				LecturerName:  item.LecturerName,
+				Registrations: make([]entity.RegistrationAttendance, 0),
+			})
+			n++
+		}
+
+		lecturer := &resp.Lecturers[n-1]
+		lecturer.Registrations = append(lecturer.Registrations, item)
+		lecturer.AttendanceTotals.Add(item)
+		resp.AttendanceTotals.Add(item)
+	}
+
+	return resp, nil
+}
+
+func sameLecturer(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestMatchMeetingAttendees(t *testing.T) {
+	reg := &meetingRegistration{StudentId: "01HMAIN", StudentName: "Ani"}
+	additional := []entity.AddStudent{
+		{StudentId: strPtr("01HSIBLING"), Name: strPtr("Budi")},
+		{Name: strPtr("Citra Dewi")},
+	}
+
+	tests := []struct {
+		name       string
+		attendees  []entity.AddStudent
+		wantNames  []string
+		wantFields []string
+	}{
+		{
+			name:      "main student by id",
+			attendees: []entity.AddStudent{{StudentId: strPtr("01HMAIN")}},
+			wantNames: []string{"Ani"},
+		},
+		{
+			name:      "additional student by id",
+			attendees: []entity.AddStudent{{StudentId: strPtr("01HSIBLING")}},
+			wantNames: []string{"Budi"},
+		},
+		{
+			name:      "additional student by name ignoring case and spaces",
+			attendees: []entity.AddStudent{{Name: strPtr("  citra DEWI ")}},
+			wantNames: []string{"Citra Dewi"},
+		},
+		{
+			name: "everyone",
+			attendees: []entity.AddStudent{
+				{StudentId: strPtr("01HMAIN")},
+				{StudentId: strPtr("01HSIBLING")},
+				{Name: strPtr("Citra Dewi")},
+			},
+			wantNames: []string{"Ani", "Budi", "Citra Dewi"},
+		},
+		{
+			name:       "unknown student id",
+			attendees:  []entity.AddStudent{{StudentId: strPtr("01HOTHER")}},
+			wantFields: []string{"attendees[0].student_id"},
+		},
+		{
+			name:       "unknown student name",
+			attendees:  []entity.AddStudent{{StudentId: strPtr("01HMAIN")}, {Name: strPtr("Ani")}},
+			wantFields: []string{"attendees[1].name"},
+		},
+		{
+			name:       "duplicate student id",
+			attendees:  []entity.AddStudent{{StudentId: strPtr("01HMAIN")}, {StudentId: strPtr("01HMAIN")}},
+			wantFields: []string{"attendees[1].student_id"},
+		},
+		{
+			name:       "duplicate student name",
+			attendees:  []entity.AddStudent{{Name: strPtr("Citra Dewi")}, {Name: strPtr("citra dewi")}},
+			wantFields: []string{"attendees[1].name"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := matchMeetingAttendees(reg, additional, tt.attendees)
+			if len(tt.wantFields) > 0 {
+				if assert.NotNil(t, err) {
+					assert.Equal(t, 422, err.Code)
+					assert.Len(t, err.Errors, len(tt.wantFields))
+					for _, field := range tt.wantFields {
+						assert.Contains(t, err.Errors, field)
+					}
+				}
+				assert.Nil(t, resolved)
+				return
+			}
+
+			assert.Nil(t, err)
+			names := make([]string, 0, len(resolved))
+			for i, s := range resolved {
+				assert.Equal(t, tt.attendees[i].StudentId, s.StudentId)
+				names = append(names, *s.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
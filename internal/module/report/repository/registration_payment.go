@@ -0,0 +1,306 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+// the fragments below expect program_registrations aliased as pr and are shared by
+// every query that needs the billed amount or payment status of a registration.
+// The total bill sums the billed fee columns of the fee calculator.
+var (
+	registrationTotalBillSQL = totalBillSQL()
+
+	registrationPaymentsJoinSQL = `
+		LEFT JOIN (
+			SELECT
+				rp.registration_id,
+				SUM(rp.amount) AS total_paid
+			FROM
+				registration_payments rp
+			WHERE
+				rp.deleted_at IS NULL
+			GROUP BY
+				rp.registration_id
+		) pay
+			ON pay.registration_id = pr.id
+	`
+
+	registrationTotalPaidSQL = `COALESCE(pay.total_paid, 0)`
+
+	registrationOutstandingSQL = `GREATEST(` + registrationTotalBillSQL + ` - ` + registrationTotalPaidSQL + `, 0)`
+
+	registrationPaymentStatusSQL = `(
+		CASE
+			WHEN ` + registrationTotalPaidSQL + ` >= ` + registrationTotalBillSQL + ` THEN 'paid'
+			WHEN ` + registrationTotalPaidSQL + ` > 0 THEN 'partially_paid'
+			ELSE 'unpaid'
+		END
+	)`
+)
+
+// totalBillSQL sums the billed fee columns of program_registrations aliased as pr
+func totalBillSQL() string {
+	parts := make([]string, 0)
+	for _, column := range entity.BilledFeeColumns() {
+		parts = append(parts, `COALESCE(pr.`+column+`, 0)`)
+	}
+
+	return `(` + strings.Join(parts, ` + `) + `)`
+}
+
+func (r *reportRepo) CreateRegistrationPayment(ctx context.Context, req *entity.CreateRegistrationPaymentReq) (*entity.CreateRegistrationPaymentResp, error) {
+	var (
+		resp = new(entity.CreateRegistrationPaymentResp)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationPayment - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the period lock is taken before the registration row, like every change
+	if err = checkRegistrationUnlocked(ctx, tx, req.RegistrationId); err != nil {
+		return nil, err
+	}
+
+	balance, err := r.getRegistrationPaymentBalance(ctx, tx, req.RegistrationId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	amount := decimal.NewFromFloat(req.Amount)
+	if amount.GreaterThan(balance.OutstandingBalance) {
+		log.Warn().Any("req", req).Any("balance", balance).Msg("repo::CreateRegistrationPayment - amount exceeds outstanding balance")
+		return nil, errmsg.NewCustomErrors(422).SetMessage("Nominal pembayaran melebihi sisa tagihan sebesar " + balance.OutstandingBalance.StringFixed(2))
+	}
+
+	query := `
+		INSERT INTO registration_payments (
+			id,
+			registration_id,
+			user_id,
+			amount,
+			method,
+			reference,
+			notes,
+			paid_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP AT TIME ZONE ?)
+	`
+
+	resp.Id = ulid.Make().String()
+	_, err = tx.ExecContext(ctx, tx.Rebind(query),
+		resp.Id,
+		req.RegistrationId,
+		req.UserId,
+		req.Amount,
+		req.Method,
+		req.Reference,
+		req.Notes,
+		req.PaidAt,
+		req.Timezone,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationPayment - failed to insert data")
+		return nil, err
+	}
+
+	if err = audit.Created(ctx, tx, audit.Payment, resp.Id); err != nil {
+		return nil, err
+	}
+
+	balance, err = r.getRegistrationPaymentBalance(ctx, tx, req.RegistrationId, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::CreateRegistrationPayment - failed to commit transaction")
+		return nil, err
+	}
+
+	resp.RegistrationPaymentBalance = *balance
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetRegistrationPayments(ctx context.Context, req *entity.GetRegistrationPaymentsReq) (*entity.GetRegistrationPaymentsResp, error) {
+	var (
+		resp = new(entity.GetRegistrationPaymentsResp)
+	)
+	resp.Items = make([]entity.RegistrationPaymentItem, 0)
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationPayments - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	balance, err := r.getRegistrationPaymentBalance(ctx, tx, req.RegistrationId, false)
+	if err != nil {
+		return nil, err
+	}
+	resp.RegistrationPaymentBalance = *balance
+
+	query := `
+		SELECT
+			rp.id,
+			rp.user_id,
+			u.name AS user_name,
+			rp.amount,
+			rp.method,
+			rp.reference,
+			rp.notes,
+			rp.paid_at,
+			rp.created_at
+		FROM
+			registration_payments rp
+		LEFT JOIN
+			users u
+			ON rp.user_id = u.id
+		WHERE
+			rp.registration_id = ?
+			AND rp.deleted_at IS NULL
+		ORDER BY
+			rp.paid_at ASC, rp.created_at ASC
+	`
+
+	err = tx.SelectContext(ctx, &resp.Items, tx.Rebind(query), req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationPayments - failed to fetch data")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) DeleteRegistrationPayment(ctx context.Context, req *entity.DeleteRegistrationPaymentReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationPayment - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	if err = checkRegistrationUnlocked(ctx, tx, req.RegistrationId); err != nil {
+		return err
+	}
+
+	if err = checkReceiptNotIssued(ctx, tx, req.RegistrationId); err != nil {
+		return err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Payment, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			registration_payments
+		SET
+			deleted_at = NOW(),
+			deleted_by = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND registration_id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.UserId, req.Id, req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationPayment - failed to delete data")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationPayment - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::DeleteRegistrationPayment - data not found")
+		return errmsg.NewCustomErrors(404).SetMessage("Pembayaran tidak ditemukan")
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::DeleteRegistrationPayment - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+// getRegistrationPaymentBalance optionally locks the registration row so concurrent
+// payments are checked against an up to date outstanding balance.
+func (r *reportRepo) getRegistrationPaymentBalance(ctx context.Context, tx *sqlx.Tx, registrationId string, forUpdate bool) (*entity.RegistrationPaymentBalance, error) {
+	var (
+		balance = new(entity.RegistrationPaymentBalance)
+		lock    string
+	)
+
+	if forUpdate {
+		lock = ` FOR UPDATE`
+	}
+
+	query := `
+		SELECT
+			pr.id
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	` + lock
+
+	var id string
+	err := tx.GetContext(ctx, &id, tx.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("registration_id", registrationId).Msg("repo::getRegistrationPaymentBalance - registration not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+		}
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::getRegistrationPaymentBalance - failed to fetch registration")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			pr.id AS registration_id,
+			` + registrationTotalBillSQL + ` AS total_bill,
+			` + registrationTotalPaidSQL + ` AS total_paid,
+			` + registrationOutstandingSQL + ` AS outstanding_balance,
+			` + registrationPaymentStatusSQL + ` AS payment_status
+		FROM
+			program_registrations pr
+		` + registrationPaymentsJoinSQL + `
+		WHERE
+			pr.id = ?
+	`
+
+	err = tx.GetContext(ctx, balance, tx.Rebind(query), registrationId)
+	if err != nil {
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::getRegistrationPaymentBalance - failed to fetch balance")
+		return nil, err
+	}
+
+	return balance, nil
+}
@@ -0,0 +1,286 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+func (r *reportRepo) IssueReceipt(ctx context.Context, req *entity.IssueReceiptReq) (*entity.ReceiptData, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::IssueReceipt - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	balance, err := r.getRegistrationPaymentBalance(ctx, tx, req.RegistrationId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var receiptId string
+	err = tx.GetContext(ctx, &receiptId, tx.Rebind(`SELECT id FROM receipts WHERE registration_id = ? FOR UPDATE`), req.RegistrationId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Any("req", req).Msg("repo::IssueReceipt - failed to check receipt")
+		return nil, err
+	}
+
+	isReissue := err == nil
+	if isReissue {
+		// a reissued receipt keeps its original number, amount and date
+		err = r.reissueReceipt(ctx, tx, req, receiptId)
+	} else {
+		if errReceipt := entity.ReceiptError(balance); errReceipt != nil {
+			log.Warn().Any("req", req).Any("balance", balance).Msg("repo::IssueReceipt - registration is not paid by a payment")
+			return nil, errReceipt
+		}
+		receiptId, err = r.insertReceipt(ctx, tx, req)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.getReceipt(ctx, tx, `rc.id = ?`, receiptId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::IssueReceipt - failed to fetch receipt")
+		return nil, err
+	}
+	data.IsReissue = isReissue
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::IssueReceipt - failed to commit transaction")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (r *reportRepo) GetReceiptByNumber(ctx context.Context, req *entity.VerifyReceiptReq) (*entity.ReceiptData, error) {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetReceiptByNumber - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	data, err := r.getReceipt(ctx, tx, `rc.receipt_number = ?`, req.Number)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetReceiptByNumber - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Kwitansi tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetReceiptByNumber - failed to fetch data")
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// checkReceiptNotIssued rejects changes to the bill or payments of a registration
+// that has a receipt, the receipt would no longer match them. The registration
+// row is locked so a receipt can not be issued meanwhile, a missing
+// registration is left to the caller to report.
+func checkReceiptNotIssued(ctx context.Context, tx *sqlx.Tx, registrationId string) error {
+	var issued bool
+
+	query := `
+		SELECT
+			EXISTS (
+				SELECT
+					1
+				FROM
+					receipts rc
+				WHERE
+					rc.registration_id = pr.id
+			)
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+		FOR UPDATE OF pr
+	`
+
+	err := tx.GetContext(ctx, &issued, tx.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		log.Error().Err(err).Str("registration_id", registrationId).Msg("repo::checkReceiptNotIssued - failed to check receipt")
+		return err
+	}
+
+	if issued {
+		log.Warn().Str("registration_id", registrationId).Msg("repo::checkReceiptNotIssued - receipt is issued")
+		return errmsg.NewCustomErrors(409).SetMessage("Kwitansi registrasi sudah diterbitkan, tagihan dan pembayarannya tidak dapat diubah lagi")
+	}
+
+	return nil
+}
+
+func (r *reportRepo) insertReceipt(ctx context.Context, tx *sqlx.Tx, req *entity.IssueReceiptReq) (string, error) {
+	var (
+		id       = ulid.Make().String()
+		issuedAt = time.Now().In(time.FixedZone("Asia/Makassar", 8*3600))
+		seq      int
+	)
+
+	querySeq := `
+		INSERT INTO receipt_sequences (year, last_number)
+		VALUES (?, 1)
+		ON CONFLICT (year) DO UPDATE SET last_number = receipt_sequences.last_number + 1
+		RETURNING last_number
+	`
+
+	err := tx.GetContext(ctx, &seq, tx.Rebind(querySeq), issuedAt.Year())
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::insertReceipt - failed to get next sequence")
+		return "", err
+	}
+
+	query := `
+		INSERT INTO receipts (
+			id,
+			registration_id,
+			receipt_number,
+			year,
+			sequence,
+			amount,
+			paid_at,
+			issued_by,
+			issued_at
+		)
+		SELECT
+			?, pr.id, ?, ?, ?,
+			` + registrationTotalPaidSQL + `,
+			(
+				SELECT MAX(rp.paid_at)
+				FROM registration_payments rp
+				WHERE rp.registration_id = pr.id AND rp.deleted_at IS NULL
+			),
+			?, ?
+		FROM
+			program_registrations pr
+		` + registrationPaymentsJoinSQL + `
+		WHERE
+			pr.id = ?
+			AND ` + registrationApprovedSQL + `
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query),
+		id,
+		entity.FormatReceiptNumber(req.NumberPrefix, req.SequenceDigits, issuedAt, seq),
+		issuedAt.Year(),
+		seq,
+		req.UserId,
+		issuedAt,
+		req.RegistrationId,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::insertReceipt - failed to insert receipt")
+		return "", err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::insertReceipt - failed to get affected rows")
+		return "", err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::insertReceipt - registration is not approved")
+		return "", errmsg.NewCustomErrors(422).SetMessage("Registrasi belum disetujui, kwitansi belum dapat dibuat")
+	}
+
+	if err = audit.Created(ctx, tx, audit.Receipt, id); err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+func (r *reportRepo) reissueReceipt(ctx context.Context, tx *sqlx.Tx, req *entity.IssueReceiptReq, receiptId string) error {
+	trail, err := audit.Track(ctx, tx, audit.Receipt, receiptId)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			receipts
+		SET
+			reissue_count = reissue_count + 1,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), receiptId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::reissueReceipt - failed to update receipt")
+		return err
+	}
+
+	query = `
+		INSERT INTO receipt_reissues (id, receipt_id, user_id, reason)
+		VALUES (?, ?, ?, ?)
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), ulid.Make().String(), receiptId, req.UserId, req.Reason)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::reissueReceipt - failed to record reissue")
+		return err
+	}
+
+	return trail.Save(ctx, tx)
+}
+
+func (r *reportRepo) getReceipt(ctx context.Context, tx *sqlx.Tx, where string, arg any) (*entity.ReceiptData, error) {
+	var (
+		data = new(entity.ReceiptData)
+	)
+
+	query := `
+		SELECT
+			rc.id,
+			rc.registration_id,
+			rc.receipt_number,
+			rc.amount,
+			rc.paid_at,
+			rc.issued_at,
+			u.name AS issued_by_name,
+			rc.reissue_count,
+			s.name AS student_name,
+			s.identifier AS student_identifier,
+			pr.program_name,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			(pr.deleted_at IS NOT NULL OR ` + registrationTotalPaidSQL + ` < rc.amount) AS is_revoked
+		FROM
+			receipts rc
+		JOIN
+			program_registrations pr
+			ON rc.registration_id = pr.id
+		` + registrationPaymentsJoinSQL + `
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		JOIN
+			users u
+			ON rc.issued_by = u.id
+		WHERE
+			` + where
+
+	err := tx.GetContext(ctx, data, tx.Rebind(query), arg)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
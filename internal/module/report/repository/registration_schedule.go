@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/types"
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// registrationSessionsSQL expands the days of program_registrations aliased
+	// as pr over its billing month, days holds ISO weekdays where 1 is monday
+	registrationSessionsSQL = `(
+		SELECT
+			d::DATE AS session_date
+		FROM
+			GENERATE_SERIES(pr.billing_period, pr.billing_period + INTERVAL '1 month' - INTERVAL '1 day', INTERVAL '1 day') d
+		WHERE
+			EXTRACT(ISODOW FROM d)::INT = ANY(pr.days)
+	)`
+
+	// scheduledSessionsSQL selects the sessions of the approved registrations
+	// that are not deleted as entity.ScheduledSession, callers append their
+	// filters
+	scheduledSessionsSQL = `
+		SELECT
+			TO_CHAR(sd.session_date, 'YYYY-MM-DD') AS session_date,
+			EXTRACT(ISODOW FROM sd.session_date)::INT AS weekday,
+			pr.id AS registration_id,
+			pr.lecturer_id,
+			l.name AS lecturer_name,
+			s.name AS student_name,
+			pr.program_name,
+			EXISTS (
+				SELECT
+					1
+				FROM
+					registration_meetings rm
+				WHERE
+					rm.registration_id = pr.id
+					AND rm.met_on = sd.session_date
+			) AS met
+		FROM
+			program_registrations pr
+		CROSS JOIN LATERAL
+			` + registrationSessionsSQL + ` sd
+		JOIN
+			students s
+			ON pr.student_id = s.id
+		LEFT JOIN
+			lecturers l
+			ON pr.lecturer_id = l.id
+		WHERE
+			pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+	`
+)
+
+func (r *reportRepo) GetRegistrationSchedule(ctx context.Context, req *entity.GetRegistrationScheduleReq) (*entity.GetRegistrationScheduleResp, error) {
+	var resp = new(entity.GetRegistrationScheduleResp)
+	resp.Sessions = make([]entity.ScheduledSession, 0)
+
+	query := `
+		SELECT
+			pr.id AS registration_id,
+			TO_CHAR(pr.billing_period, 'YYYY-MM') AS billing_period,
+			pr.days,
+			pr.program_meetings
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	`
+	args := []any{req.Id}
+
+	// a lecturer only sees the schedule of their own registrations
+	if req.Role == types.RoleLecturer {
+		query += ` AND ` + userLecturerSQL
+		args = append(args, req.UserId)
+	}
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), args...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetRegistrationSchedule - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Registrasi tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationSchedule - failed to fetch registration")
+		return nil, err
+	}
+
+	query = scheduledSessionsSQL + ` AND pr.id = ? ORDER BY sd.session_date`
+
+	err = r.db.SelectContext(ctx, &resp.Sessions, r.db.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetRegistrationSchedule - failed to fetch sessions")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetLecturerLoads groups the sessions between the dates per day and lecturer,
+// registrations without a lecturer are left out
+func (r *reportRepo) GetLecturerLoads(ctx context.Context, req *entity.GetLecturerLoadsReq) (*entity.GetLecturerLoadsResp, error) {
+	var (
+		data = make([]entity.ScheduledSession, 0)
+		resp = new(entity.GetLecturerLoadsResp)
+		args = make([]any, 0, 5)
+	)
+	resp.From = req.From
+	resp.To = req.To
+	resp.MaxSessions = req.MaxSessions
+	resp.Items = make([]entity.LecturerDayLoad, 0)
+
+	query := scheduledSessionsSQL + `
+		AND pr.lecturer_id IS NOT NULL
+		AND pr.billing_period BETWEEN DATE_TRUNC('month', TO_DATE(?, 'YYYY-MM-DD')) AND TO_DATE(?, 'YYYY-MM-DD')
+		AND sd.session_date BETWEEN TO_DATE(?, 'YYYY-MM-DD') AND TO_DATE(?, 'YYYY-MM-DD')
+	`
+	args = append(args, req.From, req.To, req.From, req.To)
+
+	if req.LecturerId != "" {
+		query += ` AND pr.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	query += ` ORDER BY sd.session_date, l.name, pr.lecturer_id, s.name, pr.id`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerLoads - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		n := len(resp.Items)
+		if n == 0 || resp.Items[n-1].Date != item.Date || !sameLecturer(resp.Items[n-1].LecturerId, item.LecturerId) {
+			resp.Items = append(resp.Items, entity.LecturerDayLoad{
+				Date:         item.Date,
+				LecturerId:   item.LecturerId,
+				LecturerName: item.LecturerName,
+				Items:        make([]entity.ScheduledSession, 0),
+			})
+			n++
+		}
+
+		load := &resp.Items[n-1]
+		load.Items = append(load.Items, item)
+		load.Sessions++
+		if load.Sessions == req.MaxSessions+1 {
+			load.Conflict = true
+			resp.TotalConflicts++
+		}
+	}
+
+	return resp, nil
+}
+
+// GetCalendarLecturer returns the lecturer and whether it is bound to the user,
+// userId may be empty
+func (r *reportRepo) GetCalendarLecturer(ctx context.Context, lecturerId, userId string) (*entity.CalendarLecturer, error) {
+	var lecturer = new(entity.CalendarLecturer)
+
+	query := `
+		SELECT
+			l.id,
+			l.name,
+			COALESCE(l.id = u.lecturer_id, FALSE) AS is_user,
+			l.calendar_version
+		FROM
+			lecturers l
+		LEFT JOIN
+			users u
+			ON u.id = ?
+		WHERE
+			l.id = ?
+			AND l.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, lecturer, r.db.Rebind(query), userId, lecturerId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("lecturer_id", lecturerId).Msg("repo::GetCalendarLecturer - data not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Str("lecturer_id", lecturerId).Msg("repo::GetCalendarLecturer - failed to fetch data")
+		return nil, err
+	}
+
+	return lecturer, nil
+}
+
+// ResetCalendarVersion bumps the calendar version of the lecturer so the feed
+// links signed before stop working, and returns the new version
+func (r *reportRepo) ResetCalendarVersion(ctx context.Context, lecturerId string) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Str("lecturer_id", lecturerId).Msg("repo::ResetCalendarVersion - failed to begin transaction")
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, audit.Lecturer, lecturerId)
+	if err != nil {
+		return 0, err
+	}
+
+	query := `
+		UPDATE
+			lecturers
+		SET
+			calendar_version = calendar_version + 1,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+		RETURNING calendar_version
+	`
+
+	var version int
+	err = tx.GetContext(ctx, &version, tx.Rebind(query), lecturerId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("lecturer_id", lecturerId).Msg("repo::ResetCalendarVersion - data not found")
+			return 0, errmsg.NewCustomErrors(404).SetMessage("Pengajar tidak ditemukan")
+		}
+		log.Error().Err(err).Str("lecturer_id", lecturerId).Msg("repo::ResetCalendarVersion - failed to update lecturer")
+		return 0, err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return 0, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Str("lecturer_id", lecturerId).Msg("repo::ResetCalendarVersion - failed to commit transaction")
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// GetLecturerSessions returns the sessions of the lecturer in the billing
+// periods from req.FromPeriod on
+func (r *reportRepo) GetLecturerSessions(ctx context.Context, req *entity.GetLecturerCalendarReq) ([]entity.ScheduledSession, error) {
+	var data = make([]entity.ScheduledSession, 0)
+
+	query := scheduledSessionsSQL + `
+		AND pr.lecturer_id = ?
+		AND pr.billing_period >= TO_DATE(?, 'YYYY-MM')
+		ORDER BY sd.session_date, s.name, pr.id
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), req.LecturerId, req.FromPeriod)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetLecturerSessions - failed to fetch data")
+		return nil, err
+	}
+
+	return data, nil
+}
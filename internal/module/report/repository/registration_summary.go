@@ -4,62 +4,268 @@ import (
 	"codebase-app/internal/module/report/entity"
 	"context"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/rs/zerolog/log"
 )
 
+// summaryTotalsSQL sums every fee component over the facts aliased as sf joined
+// with their registration, profit is calculated by the service. Fees are summed
+// once per registration and the cash received per payment
+var summaryTotalsSQL = `
+	COALESCE(SUM(` + registrationTotalBillSQL + `) FILTER (WHERE sf.is_registration), 0) AS total_billed,
+	COALESCE(SUM(sf.cash_received), 0) AS total_cash_received,
+	COALESCE(SUM(` + registrationOutstandingSQL + `) FILTER (WHERE sf.is_registration), 0) AS total_outstanding,
+	COALESCE(SUM(pr.program_fee) FILTER (WHERE sf.is_registration), 0) AS total_program_fee,
+	COALESCE(SUM(pr.administration_fee) FILTER (WHERE sf.is_registration), 0) AS total_administration_fee,
+	COALESCE(SUM(pr.foreign_learning_fee) FILTER (WHERE sf.is_registration), 0) AS total_foreign_learning_fee,
+	COALESCE(SUM(pr.night_learning_fee) FILTER (WHERE sf.is_registration), 0) AS total_night_learning_fee,
+	COALESCE(SUM(pr.hr_fee) FILTER (WHERE sf.is_registration), 0) AS total_hr_fee,
+	COALESCE(SUM(pr.overpayment_fee) FILTER (WHERE sf.is_registration), 0) AS total_overpayment_fee,
+	COALESCE(SUM(pr.marketer_commission_fee) FILTER (WHERE sf.is_registration), 0) AS total_marketer_commission_fee,
+	COALESCE(SUM(pr.marketer_gifts_fee) FILTER (WHERE sf.is_registration), 0) AS total_marketer_gifts_fee,
+	COALESCE(SUM(pr.closing_fee_for_office) FILTER (WHERE sf.is_registration), 0) AS total_closing_fee_for_office,
+	COALESCE(SUM(pr.closing_fee_for_reward) FILTER (WHERE sf.is_registration), 0) AS total_closing_fee_for_reward
+`
+
 func (r *reportRepo) GetSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.GetSummariesResp, error) {
+	return getSummaries(ctx, r.db, req)
+}
+
+// getSummaries reads the summary with q so it can be taken inside a transaction
+func getSummaries(ctx context.Context, q sqlx.ExtContext, req *entity.GetSummariesReq) (*entity.GetSummariesResp, error) {
 	var (
 		resp = new(entity.GetSummariesResp)
-		args = make([]any, 0, 3)
 	)
 
+	facts, args := summaryFactsSQL(req)
+
 	query := `
 		SELECT
-			COALESCE(SUM(pr.hr_fee), 0) AS total_hr_fee,
-			COALESCE(SUM(pr.overpayment_fee), 0) AS total_overpayment_fee,
-			COALESCE(SUM(pr.marketer_commission_fee), 0) AS total_marketer_commission_fee,
-			COALESCE(SUM(pr.marketer_gifts_fee), 0) AS total_marketer_gifts_fee,
-			COALESCE(SUM(pr.closing_fee_for_reward), 0) AS total_closing_fee_for_reward,
-			COALESCE(
-				SUM(
-					COALESCE(pr.administration_fee, 0)
-					+ COALESCE(pr.program_fee, 0)
-					+ COALESCE(pr.overpayment_fee, 0)
-					+ COALESCE(pr.night_learning_fee, 0)
-					+ COALESCE(pr.foreign_learning_fee, 0)
-					- COALESCE(pr.marketer_commission_fee, 0)
-					- COALESCE(pr.marketer_gifts_fee, 0)
-					- COALESCE(pr.hr_fee, 0)
-					- COALESCE(pr.overpayment_fee, 0)
-					- COALESCE(pr.closing_fee_for_office, 0)
-					- COALESCE(pr.closing_fee_for_reward, 0)
-				)
-			, 0) AS total_profit
+			` + summaryTotalsSQL + `
+		FROM (` + facts + `) sf
+		JOIN
+			program_registrations pr
+			ON sf.registration_id = pr.id
+		` + registrationPaymentsJoinSQL
+
+	err := sqlx.GetContext(ctx, q, &resp.SummaryTotals, q.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::getSummaries - failed to get summaries")
+		return nil, err
+	}
+
+	if fields := req.GroupByFields(); len(fields) > 0 {
+		resp.Groups, err = getSummaryGroups(ctx, q, req, fields)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp.PaidAtFrom = req.PaidAtFrom
+	resp.PaidAtTo = req.PaidAtTo
+	resp.BillingPeriodFrom = req.BillingPeriodFrom
+	resp.BillingPeriodTo = req.BillingPeriodTo
+
+	return resp, nil
+}
+
+// summaryFactsSQL selects one fact per approved registration in the range and
+// one per payment received in the range, with the month each one counts in.
+// With a billing period the range is the billing period of the registration
+// and its payments are counted whenever they were made, otherwise registrations
+// are picked by their paid_at and payments by their own paid_at, both in the
+// requested timezone
+func summaryFactsSQL(req *entity.GetSummariesReq) (string, []any) {
+	if req.BillingPeriodFrom != "" && req.BillingPeriodTo != "" {
+		billingPeriodSQL := `pr.billing_period BETWEEN TO_DATE(?, 'YYYY-MM') AND TO_DATE(?, 'YYYY-MM')`
+
+		query := `
+			SELECT
+				pr.id AS registration_id,
+				TO_CHAR(pr.billing_period, 'YYYY-MM') AS month,
+				TRUE AS is_registration,
+				0 AS cash_received
+			FROM
+				program_registrations pr
+			WHERE
+				pr.deleted_at IS NULL
+				AND ` + registrationApprovedSQL + `
+				AND ` + billingPeriodSQL + `
+			UNION ALL
+			SELECT
+				pr.id,
+				TO_CHAR(pr.billing_period, 'YYYY-MM'),
+				FALSE,
+				rp.amount
+			FROM
+				registration_payments rp
+			JOIN
+				program_registrations pr
+				ON rp.registration_id = pr.id
+			WHERE
+				rp.deleted_at IS NULL
+				AND pr.deleted_at IS NULL
+				AND ` + registrationApprovedSQL + `
+				AND ` + billingPeriodSQL + `
+		`
+		args := []any{req.BillingPeriodFrom, req.BillingPeriodTo, req.BillingPeriodFrom, req.BillingPeriodTo}
+
+		return query, args
+	}
+
+	query := `
+		SELECT
+			pr.id AS registration_id,
+			TO_CHAR(pr.paid_at AT TIME ZONE ?, 'YYYY-MM') AS month,
+			TRUE AS is_registration,
+			0 AS cash_received
 		FROM
 			program_registrations pr
 		WHERE
 			pr.deleted_at IS NULL
-			AND pr.paid_at AT TIME ZONE ? BETWEEN
-			(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC') AND
-			(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC' + time '23:59:59.999999')
+			AND ` + registrationApprovedSQL + `
+			AND ` + summaryPaidAtSQL("pr.paid_at") + `
+		UNION ALL
+		SELECT
+			pr.id,
+			TO_CHAR(rp.paid_at AT TIME ZONE ?, 'YYYY-MM'),
+			FALSE,
+			rp.amount
+		FROM
+			registration_payments rp
+		JOIN
+			program_registrations pr
+			ON rp.registration_id = pr.id
+		WHERE
+			rp.deleted_at IS NULL
+			AND pr.deleted_at IS NULL
+			AND ` + registrationApprovedSQL + `
+			AND ` + summaryPaidAtSQL("rp.paid_at") + `
 	`
-	args = append(args, req.Timezone, req.PaidAtFrom, req.PaidAtTo)
-
-	err := r.db.QueryRowContext(ctx, r.db.Rebind(query), args...).Scan(
-		&resp.TotalHrFee,
-		&resp.TotalOverpaymentFee,
-		&resp.TotalMarketerCommission,
-		&resp.TotalMarketerGifts,
-		&resp.TotalClosingFeeForReward,
-		&resp.TotalProfit,
+	args := []any{
+		req.Timezone, req.Timezone, req.PaidAtFrom, req.PaidAtTo,
+		req.Timezone, req.Timezone, req.PaidAtFrom, req.PaidAtTo,
+	}
+
+	return query, args
+}
+
+// summaryPaidAtSQL tells whether the column is within the paid_at date range,
+// it binds the timezone and both dates
+func summaryPaidAtSQL(column string) string {
+	return column + ` AT TIME ZONE ? BETWEEN
+		(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC') AND
+		(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC' + time '23:59:59.999999')`
+}
+
+// summaryGroupSQL returns the id and name expressions of a group_by dimension,
+// month follows the same basis as the facts
+func summaryGroupSQL(field string) (id, name string) {
+	switch field {
+	case entity.SummaryGroupByMarketer:
+		return "pr.marketer_id", "m.name"
+	case entity.SummaryGroupByLecturer:
+		return "pr.lecturer_id", "l.name"
+	case entity.SummaryGroupByProgram:
+		return "pr.program_id", "pr.program_name"
+	case entity.SummaryGroupByStudentManager:
+		return "m.student_manager_id", "sm.name"
+	default:
+		return "f.month", "f.month"
+	}
+}
+
+func getSummaryGroups(ctx context.Context, q sqlx.ExtContext, req *entity.GetSummariesReq, fields []string) ([]entity.SummaryGroup, error) {
+	type dao struct {
+		Key1Id     *string `db:"key1_id"`
+		Key1Name   *string `db:"key1_name"`
+		Key2Id     *string `db:"key2_id"`
+		Key2Name   *string `db:"key2_name"`
+		IsSubtotal bool    `db:"is_subtotal"`
+		entity.SummaryTotals
+	}
+
+	var (
+		data = make([]dao, 0)
 	)
+
+	id1, name1 := summaryGroupSQL(fields[0])
+	id2, name2 := "NULL::TEXT", "NULL::TEXT"
+	groupBy := `GROUP BY sf.key1_id, sf.key2_id`
+	if len(fields) > 1 {
+		id2, name2 = summaryGroupSQL(fields[1])
+		groupBy = `GROUP BY GROUPING SETS ((sf.key1_id, sf.key2_id), (sf.key1_id))`
+	}
+
+	facts, args := summaryFactsSQL(req)
+
+	// keys are computed in a subquery so the grouping does not depend on bound parameters,
+	// names are aggregated so a renamed program or person stays in one group
+	query := `
+		SELECT
+			sf.key1_id,
+			MAX(sf.key1_name) AS key1_name,
+			sf.key2_id,
+			MAX(sf.key2_name) AS key2_name,
+			GROUPING(sf.key2_id) = 1 AS is_subtotal,
+			` + summaryTotalsSQL + `
+		FROM (
+			SELECT
+				f.registration_id,
+				f.is_registration,
+				f.cash_received,
+				` + id1 + ` AS key1_id,
+				` + name1 + ` AS key1_name,
+				` + id2 + ` AS key2_id,
+				` + name2 + ` AS key2_name
+			FROM (` + facts + `) f
+			JOIN
+				program_registrations pr
+				ON f.registration_id = pr.id
+			JOIN
+				marketers m
+				ON pr.marketer_id = m.id
+			LEFT JOIN
+				student_managers sm
+				ON m.student_manager_id = sm.id
+			LEFT JOIN
+				lecturers l
+				ON pr.lecturer_id = l.id
+		) sf
+		JOIN
+			program_registrations pr
+			ON sf.registration_id = pr.id
+		` + registrationPaymentsJoinSQL + `
+		` + groupBy + `
+		ORDER BY
+			key1_name ASC NULLS LAST,
+			sf.key1_id ASC NULLS LAST,
+			is_subtotal ASC,
+			key2_name ASC NULLS LAST,
+			sf.key2_id ASC NULLS LAST
+	`
+
+	err := sqlx.SelectContext(ctx, q, &data, q.Rebind(query), args...)
 	if err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::GetSummaries - failed to get summaries")
+		log.Error().Err(err).Any("req", req).Msg("repo::getSummaryGroups - failed to get summary groups")
 		return nil, err
 	}
 
-	resp.PaidAtFrom = req.PaidAtFrom
-	resp.PaidAtTo = req.PaidAtTo
+	groups := make([]entity.SummaryGroup, 0, len(data))
+	for _, d := range data {
+		group := entity.SummaryGroup{
+			Group: map[string]entity.SummaryGroupKey{
+				fields[0]: {Id: d.Key1Id, Name: d.Key1Name},
+			},
+			IsSubtotal:    d.IsSubtotal,
+			SummaryTotals: d.SummaryTotals,
+		}
+		if len(fields) > 1 && !d.IsSubtotal {
+			group.Group[fields[1]] = entity.SummaryGroupKey{Id: d.Key2Id, Name: d.Key2Name}
+		}
 
-	return resp, nil
+		groups = append(groups, group)
+	}
+
+	return groups, nil
 }
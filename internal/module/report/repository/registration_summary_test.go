@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummaryFactsSQLArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		req  entity.GetSummariesReq
+		want []any
+	}{
+		{
+			name: "registrations and payments by their own paid_at",
+			req:  entity.GetSummariesReq{PaidAtFrom: "2026-10-01", PaidAtTo: "2026-10-31", Timezone: "Asia/Makassar"},
+			want: []any{
+				"Asia/Makassar", "Asia/Makassar", "2026-10-01", "2026-10-31",
+				"Asia/Makassar", "Asia/Makassar", "2026-10-01", "2026-10-31",
+			},
+		},
+		{
+			name: "billing period wins over paid_at",
+			req:  entity.GetSummariesReq{BillingPeriodFrom: "2026-09", BillingPeriodTo: "2026-10", PaidAtFrom: "2026-10-01", PaidAtTo: "2026-10-31", Timezone: "Asia/Makassar"},
+			want: []any{"2026-09", "2026-10", "2026-09", "2026-10"},
+		},
+		{
+			name: "half a billing period range falls back to paid_at",
+			req:  entity.GetSummariesReq{BillingPeriodFrom: "2026-09", PaidAtFrom: "2026-10-01", PaidAtTo: "2026-10-31", Timezone: "UTC"},
+			want: []any{
+				"UTC", "UTC", "2026-10-01", "2026-10-31",
+				"UTC", "UTC", "2026-10-01", "2026-10-31",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, args := summaryFactsSQL(&tt.req)
+
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
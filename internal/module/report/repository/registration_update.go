@@ -2,6 +2,7 @@ package repository
 
 import (
 	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
 	"context"
 
 	"github.com/lib/pq"
@@ -29,6 +30,22 @@ func (r *reportRepo) UpdateRegistration(ctx context.Context, req *entity.UpdateR
 		}
 	}()
 
+	if err = checkRegistrationUnlocked(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+
+	if err = checkReceiptNotIssued(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the edit goes back to draft and has to be submitted and approved again.
+	// A manual hr fee split no longer adds up once the hr fee changes, so it
+	// goes back to following its rule. The right side reads the old row.
 	query := `
 		UPDATE program_registrations SET
 			program_id = ?,
@@ -46,8 +63,16 @@ func (r *reportRepo) UpdateRegistration(ctx context.Context, req *entity.UpdateR
 			marketer_gifts_fee = ?,
 			closing_fee_for_office = ?,
 			closing_fee_for_reward = ?,
+			hr_fee_split_manual = hr_fee_split_manual AND hr_fee IS NOT DISTINCT FROM ?,
 			days = ?,
 			notes = ?,
+			approval_status = 'draft',
+			submitted_at = NULL,
+			submitted_by = NULL,
+			reviewed_at = NULL,
+			reviewed_by = NULL,
+			review_comment = NULL,
+			updated_by = ?,
 			updated_at = NOW()
 		WHERE
 			id = ?
@@ -58,14 +83,26 @@ func (r *reportRepo) UpdateRegistration(ctx context.Context, req *entity.UpdateR
 		req.ProgramId, req.LecturerId, req.MarketerId, req.StudentId,
 		req.ProgramId, req.ProgramFee, req.AdministrationFee, req.FLFee, req.NLFee,
 		req.MarketerCommissionFee, req.OverpaymentFee, req.HRFee, req.MarketerGiftsFee,
-		req.ClosingFeeForOffice, req.ClosingFeeForReward, pq.Array(req.Days), req.Notes,
-		req.Id,
+		req.ClosingFeeForOffice, req.ClosingFeeForReward, req.HRFee, pq.Array(req.Days), req.Notes,
+		req.UserId, req.Id,
 	)
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UpdateRegistration - failed to update data")
 		return nil, err
 	}
 
+	// the hr fee may have changed, splits that follow a rule are recalculated,
+	// including a manual split that was reset above
+	_, err = applyHRFeeSplitRules(ctx, tx, `pr.id = ? AND pr.hr_fee_split_manual = FALSE`, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// the days may have changed
+	if err = syncPlannedMeetings(ctx, tx, req.Id); err != nil {
+		return nil, err
+	}
+
 	query = `
 		DELETE FROM pr_additional_students WHERE pr_id = ?
 	`
@@ -91,6 +128,10 @@ func (r *reportRepo) UpdateRegistration(ctx context.Context, req *entity.UpdateR
 		}
 	}
 
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
 	resp := new(entity.UpdateRegistrationResp)
 	resp.Id = req.Id
 
@@ -2,83 +2,373 @@ package repository
 
 import (
 	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
 
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
-	"github.com/shopspring/decimal"
 )
 
-func (r *reportRepo) UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) error {
+// the fragments below expect program_registrations aliased as pr and are shared by
+// every query that needs the drawn or remaining hr fee of the lecturer.
+const (
+	registrationMentorFeeUsagesJoinSQL = `
+		LEFT JOIN (
+			SELECT
+				mfu.registration_id,
+				SUM(mfu.amount) AS total_used,
+				MAX(mfu.used_at) FILTER (WHERE mfu.reversal_of IS NULL) AS last_used_at,
+				(ARRAY_AGG(mfu.notes ORDER BY mfu.used_at DESC, mfu.created_at DESC) FILTER (WHERE mfu.notes IS NOT NULL))[1] AS last_notes
+			FROM
+				mentor_fee_usages mfu
+			GROUP BY
+				mfu.registration_id
+		) mfu_sum
+			ON mfu_sum.registration_id = pr.id
+	`
+
+	registrationMentorFeeUsedSQL = `COALESCE(mfu_sum.total_used, 0)`
+
+	registrationMentorFeeRemainingSQL = `(COALESCE(pr.mentor_detail_fee, 0) - ` + registrationMentorFeeUsedSQL + `)`
+)
+
+func (r *reportRepo) UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) (*entity.UseHRfeeForLecturerResp, error) {
+	var (
+		resp = new(entity.UseHRfeeForLecturerResp)
+	)
+
 	Tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLLecturer - failed to begin transaction")
-		return err
+		return nil, err
 	}
 	defer Tx.Rollback()
 
-	queryGetMentorDetailFee := `
+	if err = checkRegistrationUnlocked(ctx, Tx, req.RegistrationId); err != nil {
+		return nil, err
+	}
+
+	balance, err := getMentorFeeBalance(ctx, Tx, req.RegistrationId, true)
+	if err != nil {
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, Tx, audit.Registration, req.RegistrationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if errDraw := balance.DrawError(*req.UsedAmount); errDraw != nil {
+		log.Warn().Any("req", req).Any("balance", balance).Msg("repo::UseHRfeeForLecturer - amount can not be drawn")
+		return nil, errDraw
+	}
+
+	query := `
+		INSERT INTO mentor_fee_usages (
+			id,
+			registration_id,
+			user_id,
+			amount,
+			notes,
+			used_at
+		) VALUES (?, ?, ?, ?, ?, COALESCE(TO_DATE(NULLIF(?, ''), 'YYYY-MM-DD')::TIMESTAMP AT TIME ZONE ?, NOW()))
+	`
+
+	resp.Id = ulid.Make().String()
+	_, err = Tx.ExecContext(ctx, Tx.Rebind(query),
+		resp.Id,
+		req.RegistrationId,
+		req.UserId,
+		req.UsedAmount,
+		req.Notes,
+		req.UsedAt,
+		req.Timezone,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - failed to insert usage")
+		return nil, err
+	}
+
+	balance, err = syncMentorFeeUsed(ctx, Tx, req.RegistrationId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = audit.Created(ctx, Tx, audit.MentorFeeUsage, resp.Id); err != nil {
+		return nil, err
+	}
+
+	if err = trail.Save(ctx, Tx); err != nil {
+		return nil, err
+	}
+
+	if err = Tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - failed to commit transaction")
+		return nil, err
+	}
+
+	resp.MentorFeeBalance = *balance
+
+	return resp, nil
+}
+
+func (r *reportRepo) GetMentorFeeUsages(ctx context.Context, req *entity.GetMentorFeeUsagesReq) (*entity.GetMentorFeeUsagesResp, error) {
+	var (
+		resp = new(entity.GetMentorFeeUsagesResp)
+	)
+	resp.Items = make([]entity.MentorFeeUsageItem, 0)
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetMentorFeeUsages - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	balance, err := getMentorFeeBalance(ctx, tx, req.RegistrationId, false)
+	if err != nil {
+		return nil, err
+	}
+	resp.MentorFeeBalance = *balance
+
+	query := `
 		SELECT
-			mentor_detail_fee
+			mfu.id,
+			mfu.user_id,
+			u.name AS user_name,
+			mfu.amount,
+			mfu.notes,
+			mfu.used_at,
+			mfu.reversal_of,
+			rev.id AS reversed_by,
+			mfu.created_at
 		FROM
-			program_registrations
+			mentor_fee_usages mfu
+		LEFT JOIN
+			users u
+			ON mfu.user_id = u.id
+		LEFT JOIN
+			mentor_fee_usages rev
+			ON rev.reversal_of = mfu.id
 		WHERE
-			id = ?
+			mfu.registration_id = ?
+		ORDER BY
+			mfu.used_at ASC, mfu.created_at ASC
 	`
-	var mentorDetailFee decimal.Decimal
 
-	err = Tx.GetContext(ctx, &mentorDetailFee, Tx.Rebind(queryGetMentorDetailFee), req.RegistrationId)
+	err = tx.SelectContext(ctx, &resp.Items, tx.Rebind(query), req.RegistrationId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetMentorFeeUsages - failed to fetch data")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *reportRepo) ReverseMentorFeeUsage(ctx context.Context, req *entity.ReverseMentorFeeUsageReq) (*entity.ReverseMentorFeeUsageResp, error) {
+	var (
+		resp = new(entity.ReverseMentorFeeUsageResp)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReverseMentorFeeUsage - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err = checkRegistrationUnlocked(ctx, tx, req.RegistrationId); err != nil {
+		return nil, err
+	}
+
+	if _, err = getMentorFeeBalance(ctx, tx, req.RegistrationId, true); err != nil {
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.RegistrationId)
+	if err != nil {
+		return nil, err
+	}
+
+	queryCheck := `
+		SELECT
+			mfu.reversal_of IS NOT NULL AS is_reversal,
+			EXISTS (
+				SELECT
+					1
+				FROM
+					mentor_fee_usages rev
+				WHERE
+					rev.reversal_of = mfu.id
+			) AS is_reversed
+		FROM
+			mentor_fee_usages mfu
+		WHERE
+			mfu.id = ?
+			AND mfu.registration_id = ?
+	`
+
+	var usage entity.MentorFeeUsageState
+	err = tx.GetContext(ctx, &usage, tx.Rebind(queryCheck), req.Id, req.RegistrationId)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			log.Warn().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - mentor detail fee not found")
-			return errmsg.NewCustomErrors(404).SetMessage("Laporan tidak ditemukan")
+			log.Warn().Any("req", req).Msg("repo::ReverseMentorFeeUsage - usage not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Penggunaan dana pengajar tidak ditemukan")
 		}
-		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - failed to get mentor detail fee")
-		return err
+		log.Error().Err(err).Any("req", req).Msg("repo::ReverseMentorFeeUsage - failed to fetch usage")
+		return nil, err
 	}
 
-	if req.UsedAmount == nil && req.Notes == nil {
-		log.Warn().Any("req", req).Msg("repo::UseHRfeeForLecturer - used amount and notes are nil")
-		return errmsg.NewCustomErrors(400).SetMessage("Jumlah yang digunakan atau catatan harus diisi")
+	if errReverse := usage.ReverseError(); errReverse != nil {
+		log.Warn().Any("req", req).Any("usage", usage).Msg("repo::ReverseMentorFeeUsage - usage can not be reversed")
+		return nil, errReverse
 	}
 
-	if req.UsedAmount != nil && req.Notes != nil {
-		log.Warn().Any("req", req).Msg("repo::UseHRfeeForLecturer - used amount and notes are not nil")
-		return errmsg.NewCustomErrors(400).SetMessage("Jumlah yang digunakan dan catatan tidak boleh diisi bersamaan")
+	query := `
+		INSERT INTO mentor_fee_usages (
+			id,
+			registration_id,
+			user_id,
+			amount,
+			notes,
+			used_at,
+			reversal_of
+		)
+		SELECT
+			?,
+			mfu.registration_id,
+			?,
+			-mfu.amount,
+			?,
+			NOW(),
+			mfu.id
+		FROM
+			mentor_fee_usages mfu
+		WHERE
+			mfu.id = ?
+	`
+
+	resp.Id = ulid.Make().String()
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), resp.Id, req.UserId, req.Notes, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReverseMentorFeeUsage - failed to insert reversal")
+		return nil, err
+	}
+
+	balance, err := syncMentorFeeUsed(ctx, tx, req.RegistrationId)
+	if err != nil {
+		return nil, err
 	}
 
-	if req.UsedAmount != nil && req.UsedAmount.GreaterThan(mentorDetailFee) {
-		log.Warn().Any("req", req).Msg("repo::UseHRfeeForLecturer - used amount greater than mentor detail fee")
-		return errmsg.NewCustomErrors(400).SetMessage("Jumlah yang digunakan melebihi jumlah yang tersedia")
+	if err = audit.Created(ctx, tx, audit.MentorFeeUsage, resp.Id); err != nil {
+		return nil, err
 	}
 
-	if req.UsedAmount != nil && req.UsedAmount.LessThanOrEqual(decimal.Zero) {
-		log.Warn().Any("req", req).Msg("repo::UseHRfeeForLecturer - used amount less than or equal to 0")
-		return errmsg.NewCustomErrors(400).SetMessage("Jumlah yang digunakan harus lebih dari 0")
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReverseMentorFeeUsage - failed to commit transaction")
+		return nil, err
+	}
+
+	resp.MentorFeeBalance = *balance
+
+	return resp, nil
+}
+
+// getMentorFeeBalance optionally locks the registration row so concurrent draws
+// are checked against an up to date remaining amount.
+func getMentorFeeBalance(ctx context.Context, tx *sqlx.Tx, registrationId string, forUpdate bool) (*entity.MentorFeeBalance, error) {
+	var (
+		balance = new(entity.MentorFeeBalance)
+		lock    string
+	)
+
+	if forUpdate {
+		lock = ` FOR UPDATE`
 	}
 
 	query := `
-		UPDATE
-			program_registrations
-		SET
-			mentor_detail_fee_used = ?,
-			notes_for_fund_distributions = ?
+		SELECT
+			pr.id
+		FROM
+			program_registrations pr
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	` + lock
+
+	var id string
+	err := tx.GetContext(ctx, &id, tx.Rebind(query), registrationId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("registration_id", registrationId).Msg("repo::getMentorFeeBalance - registration not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Laporan tidak ditemukan")
+		}
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::getMentorFeeBalance - failed to fetch registration")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			pr.id AS registration_id,
+			pr.mentor_detail_fee AS hr_fee_for_lecturer,
+			` + registrationMentorFeeUsedSQL + ` AS total_used,
+			` + registrationMentorFeeRemainingSQL + ` AS remaining_amount
+		FROM
+			program_registrations pr
+		` + registrationMentorFeeUsagesJoinSQL + `
 		WHERE
-			id = ?
-			AND deleted_at IS NULL
+			pr.id = ?
 	`
 
-	_, err = Tx.ExecContext(ctx, Tx.Rebind(query), req.UsedAmount, req.Notes, req.RegistrationId)
+	err = tx.GetContext(ctx, balance, tx.Rebind(query), registrationId)
 	if err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - failed to update mentor detail fee used")
-		return err
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::getMentorFeeBalance - failed to fetch balance")
+		return nil, err
 	}
 
-	if err = Tx.Commit(); err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::UseHRfeeForLecturer - failed to commit transaction")
-		return err
+	return balance, nil
+}
+
+// syncMentorFeeUsed copies the ledger total and the last draw back onto the
+// registration, the columns are kept for readers that predate the ledger
+func syncMentorFeeUsed(ctx context.Context, tx *sqlx.Tx, registrationId string) (*entity.MentorFeeBalance, error) {
+	query := `
+		UPDATE
+			program_registrations pr
+		SET
+			mentor_detail_fee_used = (
+				SELECT
+					NULLIF(SUM(mfu.amount), 0)
+				FROM
+					mentor_fee_usages mfu
+				WHERE
+					mfu.registration_id = pr.id
+			),
+			used_at = (
+				SELECT
+					MAX(mfu.used_at)
+				FROM
+					mentor_fee_usages mfu
+				WHERE
+					mfu.registration_id = pr.id
+					AND mfu.reversal_of IS NULL
+			)
+		WHERE
+			pr.id = ?
+	`
+
+	_, err := tx.ExecContext(ctx, tx.Rebind(query), registrationId)
+	if err != nil {
+		log.Error().Err(err).Any("registration_id", registrationId).Msg("repo::syncMentorFeeUsed - failed to update registration")
+		return nil, err
 	}
 
-	return nil
+	return getMentorFeeBalance(ctx, tx, registrationId, false)
 }
@@ -4,6 +4,7 @@ import (
 	"codebase-app/internal/adapter"
 	"codebase-app/internal/module/report/entity"
 	"codebase-app/internal/module/report/ports"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 	"database/sql"
@@ -116,6 +117,7 @@ func (r *reportRepo) GetLecturerPrograms(ctx context.Context, req *entity.GetLec
 				ON prt.marketer_id = m.id
 			WHERE
 				prt.lecturer_id IN (?)
+				AND prt.deleted_at IS NULL
 			`
 
 		query, args, err := sqlx.In(query, lecturerIds)
@@ -153,10 +155,32 @@ func (r *reportRepo) DistributeHRFee(ctx context.Context, req *entity.HRDistribu
 	}
 	defer tx.Rollback()
 
-	// get HR fee
-	queryFee := `SELECT hr_fee from program_registrations WHERE id = ? AND deleted_at IS NULL`
-	var hrFee decimal.Decimal
-	err = tx.GetContext(ctx, &hrFee, r.db.Rebind(queryFee), req.RegistrationId)
+	if err = checkRegistrationUnlocked(ctx, tx, req.RegistrationId); err != nil {
+		return err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Registration, req.RegistrationId)
+	if err != nil {
+		return err
+	}
+
+	// get HR fee and the amount the lecturer already drew from it
+	queryFee := `
+		SELECT
+			pr.hr_fee,
+			` + registrationMentorFeeUsedSQL + ` AS total_used
+		FROM
+			program_registrations pr
+		` + registrationMentorFeeUsagesJoinSQL + `
+		WHERE
+			pr.id = ?
+			AND pr.deleted_at IS NULL
+	`
+	var fee struct {
+		HRFee     decimal.Decimal `db:"hr_fee"`
+		TotalUsed decimal.Decimal `db:"total_used"`
+	}
+	err = tx.GetContext(ctx, &fee, r.db.Rebind(queryFee), req.RegistrationId)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			log.Warn().Any("req", req).Msg("repo::DistributeHRFee - HR fee not found")
@@ -169,17 +193,24 @@ func (r *reportRepo) DistributeHRFee(ctx context.Context, req *entity.HRDistribu
 	hrFeeForMentor := decimal.NewFromFloat(req.HRFeeForMentor)
 	hrFeeForHR := decimal.NewFromFloat(req.HRFeeForHR)
 
-	if hrFeeForMentor.Add(hrFeeForHR).GreaterThan(hrFee) || hrFeeForMentor.Add(hrFeeForHR).LessThan(hrFee) {
+	if hrFeeForMentor.Add(hrFeeForHR).GreaterThan(fee.HRFee) || hrFeeForMentor.Add(hrFeeForHR).LessThan(fee.HRFee) {
 		log.Warn().Any("req", req).Msg("repo::DistributeHRFee - HR fee is greater than total HR fee")
 		return errmsg.NewCustomErrors(400).SetMessage("Distribusi Pengeluaran SDM tidak boleh melebihi atau kurang dari total biaya SDM")
 	}
 
+	if hrFeeForMentor.LessThan(fee.TotalUsed) {
+		log.Warn().Any("req", req).Any("total_used", fee.TotalUsed).Msg("repo::DistributeHRFee - HR fee for mentor is less than the used amount")
+		return errmsg.NewCustomErrors(422).SetMessage("Biaya SDM untuk pengajar tidak boleh kurang dari dana yang sudah digunakan sebesar " + fee.TotalUsed.StringFixed(2))
+	}
+
 	query := `
 		UPDATE
 			program_registrations
 		SET
 			mentor_detail_fee = ?,
-			hr_detail_fee = ?
+			hr_detail_fee = ?,
+			hr_fee_split_rule_id = NULL,
+			hr_fee_split_manual = TRUE
 		WHERE
 			id = ?
 			AND deleted_at IS NULL
@@ -191,6 +222,10 @@ func (r *reportRepo) DistributeHRFee(ctx context.Context, req *entity.HRDistribu
 		return err
 	}
 
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		log.Error().Err(err).Any("req", req).Msg("repo::DistributeHRFee - failed to commit transaction")
@@ -0,0 +1,251 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *reportRepo) GetArrears(ctx context.Context, req *entity.GetArrearsReq) (*entity.GetArrearsResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.ArrearItem
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetArrearsResp)
+	)
+	resp.Items = make([]entity.ArrearItem, 0)
+	resp.PeriodFrom = req.PeriodFrom
+	resp.PeriodTo = req.PeriodTo
+	resp.AsOf = req.AsOf
+
+	cte, args := arrearsQuery(req)
+
+	querySummary := cte + `
+		SELECT
+			COALESCE(SUM(a.amount_owed), 0) AS total_owed,
+			COUNT(*) FILTER (WHERE a.status = 'missing') AS total_missing_months,
+			COUNT(*) FILTER (WHERE a.status = 'unpaid') AS total_unpaid_months,
+			COUNT(*) FILTER (WHERE a.status = 'partially_paid') AS total_partially_paid_months,
+			COALESCE(SUM(a.amount_owed) FILTER (WHERE a.aging_bucket = '0-30'), 0) AS owed_0_30,
+			COALESCE(SUM(a.amount_owed) FILTER (WHERE a.aging_bucket = '31-60'), 0) AS owed_31_60,
+			COALESCE(SUM(a.amount_owed) FILTER (WHERE a.aging_bucket = '61-90'), 0) AS owed_61_90,
+			COALESCE(SUM(a.amount_owed) FILTER (WHERE a.aging_bucket = '90+'), 0) AS owed_90_plus
+		FROM
+			arrears a
+	`
+
+	err := r.db.GetContext(ctx, &resp.Summary, r.db.Rebind(querySummary), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetArrears - failed to fetch summary")
+		return nil, err
+	}
+
+	sortByMap := map[string]string{
+		"days_overdue":   "a.days_overdue",
+		"amount_owed":    "a.amount_owed",
+		"student_name":   "a.student_name",
+		"billing_period": "a.billing_period",
+		"":               "a.days_overdue",
+	}
+
+	sortTypeMap := map[string]string{
+		"asc":  "ASC",
+		"desc": "DESC",
+		"":     "DESC",
+	}
+
+	query := cte + `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			a.template_id,
+			a.registration_id,
+			a.program_id,
+			a.marketer_id,
+			a.lecturer_id,
+			a.student_id,
+			a.program_name,
+			a.marketer_name,
+			a.lecturer_name,
+			a.student_name,
+			a.billing_period,
+			a.status,
+			a.monthly_fee,
+			a.total_bill,
+			a.total_paid,
+			a.amount_owed,
+			a.days_overdue,
+			a.aging_bucket
+		FROM
+			arrears a
+		ORDER BY ` + sortByMap[req.SortBy] + ` ` + sortTypeMap[req.SortType] + `, a.student_name ASC, a.billing_period ASC
+		LIMIT ? OFFSET ?
+	`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err = r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetArrears - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.ArrearItem)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// arrearsQuery builds the "arrears" CTE: one row per active template and billing
+// period that has no registration or still has an outstanding balance.
+func arrearsQuery(req *entity.GetArrearsReq) (string, []any) {
+	var (
+		args = make([]any, 0, 16)
+	)
+
+	monthlyFee := `(
+		COALESCE(prt.program_fee, 0)
+		+ COALESCE(prt.foreign_learning_fee, 0)
+		+ COALESCE(prt.night_learning_fee, 0)
+		+ COALESCE(prt.overpayment_fee, 0)
+	)`
+
+	query := `
+		WITH periods AS (
+			SELECT
+				GENERATE_SERIES(TO_DATE(?, 'YYYY-MM'), TO_DATE(?, 'YYYY-MM'), INTERVAL '1 month')::DATE AS billing_period
+		),
+		template_periods AS (
+			SELECT
+				prt.id AS template_id,
+				reg.registration_id,
+				prt.program_id,
+				prt.marketer_id,
+				prt.lecturer_id,
+				prt.student_id,
+				p.name AS program_name,
+				m.name AS marketer_name,
+				l.name AS lecturer_name,
+				s.name AS student_name,
+				TO_CHAR(per.billing_period, 'YYYY-MM') AS billing_period,
+				` + monthlyFee + ` AS monthly_fee,
+				CASE
+					WHEN reg.registrations = 0 THEN ` + monthlyFee + `
+					ELSE reg.total_bill
+				END AS total_bill,
+				COALESCE(reg.total_paid, 0) AS total_paid,
+				CASE
+					WHEN reg.registrations = 0 THEN 'missing'
+					WHEN COALESCE(reg.total_paid, 0) > 0 THEN 'partially_paid'
+					ELSE 'unpaid'
+				END AS status,
+				GREATEST(TO_DATE(?, 'YYYY-MM-DD') - per.billing_period, 0) AS days_overdue
+			FROM
+				program_registration_templates prt
+			CROSS JOIN
+				periods per
+			JOIN
+				programs p
+				ON prt.program_id = p.id
+			JOIN
+				marketers m
+				ON prt.marketer_id = m.id
+			JOIN
+				students s
+				ON prt.student_id = s.id
+			LEFT JOIN
+				lecturers l
+				ON prt.lecturer_id = l.id
+			LEFT JOIN LATERAL (
+				SELECT
+					COUNT(pr.id) AS registrations,
+					MIN(pr.id) AS registration_id,
+					SUM(` + registrationTotalBillSQL + `) AS total_bill,
+					SUM(` + registrationTotalPaidSQL + `) AS total_paid
+				FROM
+					program_registrations pr
+				` + registrationPaymentsJoinSQL + `
+				WHERE
+					pr.template_id = prt.id
+					AND pr.billing_period = per.billing_period
+					AND pr.deleted_at IS NULL
+					AND ` + registrationApprovedSQL + `
+			) reg ON TRUE
+			WHERE
+				prt.deleted_at IS NULL
+				AND prt.program_fee IS NOT NULL
+				AND per.billing_period >= DATE_TRUNC('month', prt.created_at AT TIME ZONE ?)::DATE
+	`
+	args = append(args, req.PeriodFrom, req.PeriodTo, req.AsOf, req.Timezone)
+
+	if req.Q != "" {
+		query += ` AND (
+			p.name ILIKE '%' || ? || '%' OR
+			s.name ILIKE '%' || ? || '%'
+		)`
+		args = append(args, req.Q, req.Q)
+	}
+
+	if req.MarketerId != "" {
+		query += ` AND prt.marketer_id = ?`
+		args = append(args, req.MarketerId)
+	}
+
+	if req.LecturerId != "" {
+		query += ` AND prt.lecturer_id = ?`
+		args = append(args, req.LecturerId)
+	}
+
+	if req.StudentId != "" {
+		query += ` AND prt.student_id = ?`
+		args = append(args, req.StudentId)
+	}
+
+	if req.ProgramId != "" {
+		query += ` AND prt.program_id = ?`
+		args = append(args, req.ProgramId)
+	}
+
+	query += `
+		),
+		arrears AS (
+			SELECT
+				*
+			FROM (
+				SELECT
+					tp.*,
+					tp.total_bill - tp.total_paid AS amount_owed,
+					CASE
+						WHEN tp.days_overdue <= 30 THEN '0-30'
+						WHEN tp.days_overdue <= 60 THEN '31-60'
+						WHEN tp.days_overdue <= 90 THEN '61-90'
+						ELSE '90+'
+					END AS aging_bucket
+				FROM
+					template_periods tp
+			) t
+			WHERE
+				t.amount_owed > 0
+	`
+
+	if req.Status != "" {
+		query += ` AND t.status = ?`
+		args = append(args, req.Status)
+	}
+
+	if req.AgingBucket != "" {
+		query += ` AND t.aging_bucket = ?`
+		args = append(args, req.AgingBucket)
+	}
+
+	query += `
+		)
+	`
+
+	return query, args
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrearsQueryArgs(t *testing.T) {
+	base := entity.GetArrearsReq{PeriodFrom: "2026-01", PeriodTo: "2026-10", AsOf: "2026-10-18", Timezone: "Asia/Makassar"}
+
+	tests := []struct {
+		name   string
+		modify func(req *entity.GetArrearsReq)
+		want   []any
+	}{
+		{
+			name:   "without filters",
+			modify: func(req *entity.GetArrearsReq) {},
+			want:   []any{"2026-01", "2026-10", "2026-10-18", "Asia/Makassar"},
+		},
+		{
+			name: "template filters before the status filters",
+			modify: func(req *entity.GetArrearsReq) {
+				req.Q = "Ani"
+				req.MarketerId = "01HMARKETER"
+				req.LecturerId = "01HLECTURER"
+				req.StudentId = "01HSTUDENT"
+				req.ProgramId = "01HPROGRAM"
+				req.Status = entity.ArrearStatusPartiallyPaid
+				req.AgingBucket = entity.AgingBucket31To60
+			},
+			want: []any{
+				"2026-01", "2026-10", "2026-10-18", "Asia/Makassar",
+				"Ani", "Ani", "01HMARKETER", "01HLECTURER", "01HSTUDENT", "01HPROGRAM",
+				entity.ArrearStatusPartiallyPaid, entity.AgingBucket31To60,
+			},
+		},
+		{
+			name: "only an aging bucket",
+			modify: func(req *entity.GetArrearsReq) {
+				req.AgingBucket = entity.AgingBucketOver90
+			},
+			want: []any{"2026-01", "2026-10", "2026-10-18", "Asia/Makassar", entity.AgingBucketOver90},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := base
+			tt.modify(&req)
+
+			_, args := arrearsQuery(&req)
+
+			assert.Equal(t, tt.want, args)
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GetTimeseries returns one bucket per interval between req.From and req.To,
+// empty buckets are filled with zeros
+func (r *reportRepo) GetTimeseries(ctx context.Context, req *entity.GetTimeseriesReq) ([]entity.TimeseriesBucket, error) {
+	var (
+		data = make([]entity.TimeseriesBucket, 0)
+	)
+
+	query := `
+		WITH buckets AS (
+			SELECT
+				GENERATE_SERIES(
+					DATE_TRUNC(?, TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP),
+					TO_DATE(?, 'YYYY-MM-DD')::TIMESTAMP,
+					('1 ' || ?)::INTERVAL
+				)::DATE AS period
+		),
+		registrations AS (
+			SELECT
+				DATE_TRUNC(?, pr.paid_at AT TIME ZONE ?)::DATE AS period,
+				COUNT(pr.id) AS total_registrations,
+				SUM(pr.program_fee) AS program_fee,
+				COALESCE(SUM(pr.administration_fee), 0) AS administration_fee,
+				COALESCE(SUM(pr.foreign_learning_fee), 0) AS foreign_learning_fee,
+				COALESCE(SUM(pr.night_learning_fee), 0) AS night_learning_fee,
+				SUM(pr.marketer_commission_fee) AS marketer_commission_fee,
+				SUM(pr.marketer_gifts_fee) AS marketer_gifts_fee,
+				SUM(pr.hr_fee) AS hr_fee,
+				COALESCE(SUM(pr.overpayment_fee), 0) AS overpayment_fee,
+				COALESCE(SUM(pr.closing_fee_for_office), 0) AS closing_fee_for_office,
+				COALESCE(SUM(pr.closing_fee_for_reward), 0) AS closing_fee_for_reward
+			FROM
+				program_registrations pr
+			WHERE
+				pr.deleted_at IS NULL
+				AND ` + registrationApprovedSQL + `
+				AND pr.paid_at AT TIME ZONE ? BETWEEN
+				(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC') AND
+				(TO_TIMESTAMP(?, 'YYYY-MM-DD') AT TIME ZONE 'UTC' + time '23:59:59.999999')
+			GROUP BY
+				1
+		)
+		SELECT
+			TO_CHAR(b.period, 'YYYY-MM-DD') AS period,
+			COALESCE(d.total_registrations, 0) AS total_registrations,
+			COALESCE(d.program_fee, 0) AS program_fee,
+			COALESCE(d.administration_fee, 0) AS administration_fee,
+			COALESCE(d.foreign_learning_fee, 0) AS foreign_learning_fee,
+			COALESCE(d.night_learning_fee, 0) AS night_learning_fee,
+			COALESCE(d.marketer_commission_fee, 0) AS marketer_commission_fee,
+			COALESCE(d.marketer_gifts_fee, 0) AS marketer_gifts_fee,
+			COALESCE(d.hr_fee, 0) AS hr_fee,
+			COALESCE(d.overpayment_fee, 0) AS overpayment_fee,
+			COALESCE(d.closing_fee_for_office, 0) AS closing_fee_for_office,
+			COALESCE(d.closing_fee_for_reward, 0) AS closing_fee_for_reward
+		FROM
+			buckets b
+		LEFT JOIN
+			registrations d
+			ON b.period = d.period
+		ORDER BY
+			b.period ASC
+	`
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query),
+		req.Interval, req.From, req.To, req.Interval,
+		req.Interval, req.Timezone,
+		req.Timezone, req.From, req.To,
+	)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetTimeseries - failed to get timeseries")
+		return nil, err
+	}
+
+	return data, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (r *reportRepo) ArchiveTemplate(ctx context.Context, req *entity.ArchiveTemplateReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ArchiveTemplate - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	trail, err := audit.Track(ctx, tx, audit.Template, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			program_registration_templates
+		SET
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ArchiveTemplate - failed to archive data")
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ArchiveTemplate - failed to get affected rows")
+		return err
+	}
+
+	if affected == 0 {
+		log.Warn().Any("req", req).Msg("repo::ArchiveTemplate - data not found")
+		return errmsg.NewCustomErrors(404).SetMessage("Template tidak ditemukan")
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ArchiveTemplate - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
+
+func (r *reportRepo) UnarchiveTemplate(ctx context.Context, req *entity.UnarchiveTemplateReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnarchiveTemplate - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	queryCombination := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM program_registration_templates prt
+			WHERE
+				prt.program_id = archived.program_id
+				AND prt.marketer_id = archived.marketer_id
+				AND prt.student_id = archived.student_id
+				AND prt.lecturer_id IS NOT DISTINCT FROM archived.lecturer_id
+				AND prt.id != archived.id
+				AND prt.deleted_at IS NULL
+		)
+		FROM
+			program_registration_templates archived
+		WHERE
+			archived.id = ?
+			AND archived.deleted_at IS NOT NULL
+	`
+
+	var isCombinationExist bool
+	err = tx.GetContext(ctx, &isCombinationExist, tx.Rebind(queryCombination), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::UnarchiveTemplate - archived data not found")
+			return errmsg.NewCustomErrors(404).SetMessage("Template yang diarsipkan tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::UnarchiveTemplate - failed to check combination")
+		return err
+	}
+
+	if isCombinationExist {
+		log.Warn().Any("req", req).Msg("repo::UnarchiveTemplate - combination already exist")
+		return errmsg.NewCustomErrors(409).SetMessage("Template aktif dengan kombinasi program, marketer, pengajar, dan santri tersebut sudah ada")
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Template, req.Id)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE
+			program_registration_templates
+		SET
+			deleted_at = NULL,
+			updated_at = NOW()
+		WHERE
+			id = ?
+			AND deleted_at IS NOT NULL
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnarchiveTemplate - failed to unarchive data")
+		return err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::UnarchiveTemplate - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
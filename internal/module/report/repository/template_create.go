@@ -2,9 +2,11 @@ package repository
 
 import (
 	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 
+	"github.com/jmoiron/sqlx"
 	"github.com/lib/pq"
 	"github.com/oklog/ulid/v2"
 	"github.com/rs/zerolog/log"
@@ -30,6 +32,28 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 		}
 	}()
 
+	isCombinationExist, err := r.templateCombinationExists(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if isCombinationExist {
+		log.Warn().Any("req", req).Msg("repo::CreateTemplate - combination already exist")
+		return nil, errmsg.NewCustomErrors(409).SetMessage(templateCombinationExistMsg)
+	}
+
+	resp := new(entity.CreateTemplateResp)
+	resp.Id, err = r.insertTemplate(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+const templateCombinationExistMsg = "Template dengan kombinasi program, marketer, pengajar, dan santri tersebut sudah ada. Silahkan cek kembali atau update data yang sudah ada"
+
+func (r *reportRepo) templateCombinationExists(ctx context.Context, tx *sqlx.Tx, req *entity.CreateTemplateReq) (bool, error) {
 	isCombinationExist := false
 
 	queryCheckCombination := `
@@ -50,33 +74,32 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 		)
 	`
 
-	err = tx.GetContext(ctx, &isCombinationExist, tx.Rebind(queryCheckCombination),
+	err := tx.GetContext(ctx, &isCombinationExist, tx.Rebind(queryCheckCombination),
 		req.ProgramId, req.MarketerId, req.StudentId, req.LecturerId, req.LecturerId,
 	)
 	if err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::CreateTemplate - failed to check combination")
-		return nil, err
+		log.Error().Err(err).Any("req", req).Msg("repo::templateCombinationExists - failed to check combination")
+		return false, err
 	}
 
-	if isCombinationExist {
-		log.Warn().Any("req", req).Msg("repo::CreateTemplate - combination already exist")
-		return nil, errmsg.NewCustomErrors(409).SetMessage("Template dengan kombinasi program, marketer, pengajar, dan santri tersebut sudah ada. Silahkan cek kembali atau update data yang sudah ada")
-	}
+	return isCombinationExist, nil
+}
 
-	var (
-		Id   = ulid.Make().String()
-		resp = new(entity.CreateTemplateResp)
-	)
-	resp.Id = Id
+// insertTemplate creates a template with the program fee, hr fee and marketer
+// commission of its program in effect in the current billing period, the
+// template follows the program price until its fees are changed
+func (r *reportRepo) insertTemplate(ctx context.Context, tx *sqlx.Tx, req *entity.CreateTemplateReq) (string, error) {
+	Id := ulid.Make().String()
 
 	query := `
 		WITH program AS (
 			SELECT
-				p.price AS program_fee,
-				p.lecturer_fee AS hr_fee,
-				p.commission_fee AS marketer_commission_fee
+				` + programPriceSQL + ` AS program_fee,
+				` + programLecturerFeeSQL + ` AS hr_fee,
+				` + programCommissionFeeSQL + ` AS marketer_commission_fee
 			FROM
 				programs p
+			` + programPriceJoinSQL + `
 			WHERE
 				p.id = ?
 				AND p.deleted_at IS NULL
@@ -100,7 +123,8 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 			hr_fee,
 			marketer_gifts_fee,
 			closing_fee_for_office,
-			closing_fee_for_reward
+			closing_fee_for_reward,
+			follows_program_price
 		) VALUES (
 			?, ?, ?, ?, ?, ?, ?, ?,
 			(SELECT program_fee FROM program),
@@ -110,12 +134,13 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 			(SELECT marketer_commission_fee FROM program),
 			?,
 			(SELECT hr_fee FROM program),
-			?, ?, ?
+			?, ?, ?,
+			TRUE
 		)
 	`
 
-	_, err = tx.ExecContext(ctx, tx.Rebind(query),
-		req.ProgramId,
+	_, err := tx.ExecContext(ctx, tx.Rebind(query),
+		entity.CurrentBillingPeriod(), req.ProgramId,
 		Id, req.UserId, req.ProgramId, req.LecturerId, req.MarketerId, req.StudentId,
 		pq.Array(req.Days), req.Notes,
 
@@ -128,8 +153,8 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 		req.ClosingFeeForReward,
 	)
 	if err != nil {
-		log.Error().Err(err).Any("req", req).Msg("repo::CreateTemplate - failed to insert data")
-		return nil, err
+		log.Error().Err(err).Any("req", req).Msg("repo::insertTemplate - failed to insert data")
+		return "", err
 	}
 
 	for _, item := range req.AdditionalStudents {
@@ -143,10 +168,14 @@ func (r *reportRepo) CreateTemplate(ctx context.Context, req *entity.CreateTempl
 			ulid.Make().String(), Id, item.StudentId, item.Name,
 		)
 		if err != nil {
-			log.Error().Err(err).Any("req", req).Msg("repo::CreateTemplate - failed to insert additional students")
-			return nil, err
+			log.Error().Err(err).Any("req", req).Msg("repo::insertTemplate - failed to insert additional students")
+			return "", err
 		}
 	}
 
-	return resp, nil
+	if err = audit.Created(ctx, tx, audit.Template, Id); err != nil {
+		return "", err
+	}
+
+	return Id, nil
 }
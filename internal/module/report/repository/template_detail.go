@@ -35,6 +35,7 @@ func (r *reportRepo) GetTemplate(ctx context.Context, req *entity.GetTemplateReq
 			prt.marketer_gifts_fee,
 			prt.closing_fee_for_office,
 			prt.closing_fee_for_reward,
+			prt.follows_program_price,
 			prt.notes,
 			prt.created_at,
 			prt.updated_at,
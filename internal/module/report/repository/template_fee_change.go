@@ -0,0 +1,381 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+// templateFeeColumns are the fee columns of a template that only change through
+// an approved template_fee_changes row, both tables name them the same
+var templateFeeColumns = []string{
+	"program_fee",
+	"administration_fee",
+	"foreign_learning_fee",
+	"night_learning_fee",
+	"marketer_commission_fee",
+	"overpayment_fee",
+	"hr_fee",
+	"marketer_gifts_fee",
+	"closing_fee_for_office",
+	"closing_fee_for_reward",
+}
+
+// templateFeesSQL lists the fee columns of the table aliased as alias, each
+// column is prefixed with prefix so it scans into a nested entity.TemplateFees
+func templateFeesSQL(alias, prefix string) string {
+	columns := make([]string, 0, len(templateFeeColumns))
+	for _, column := range templateFeeColumns {
+		columns = append(columns, alias+"."+column+` AS "`+prefix+"."+column+`"`)
+	}
+	return strings.Join(columns, ", ")
+}
+
+// queueTemplateFeeChange records fees proposed by the user as the pending
+// change of the template when they differ from its current fees, or when the
+// template would start following the program price. It returns the id of the
+// pending change, or nil when nothing changes.
+func queueTemplateFeeChange(ctx context.Context, tx *sqlx.Tx, templateId, userId string, fees entity.TemplateFees, followsProgramPrice bool) (*string, error) {
+	var (
+		values  = fees.Values()
+		changed bool
+	)
+
+	query := `
+		SELECT
+			(
+				prt.program_fee,
+				prt.administration_fee,
+				prt.foreign_learning_fee,
+				prt.night_learning_fee,
+				prt.marketer_commission_fee,
+				prt.overpayment_fee,
+				prt.hr_fee,
+				prt.marketer_gifts_fee,
+				prt.closing_fee_for_office,
+				prt.closing_fee_for_reward
+			) IS DISTINCT FROM (
+				?::DECIMAL, ?::DECIMAL, ?::DECIMAL, ?::DECIMAL, ?::DECIMAL,
+				?::DECIMAL, ?::DECIMAL, ?::DECIMAL, ?::DECIMAL, ?::DECIMAL
+			)
+			OR (?::BOOLEAN AND NOT prt.follows_program_price)
+		FROM
+			program_registration_templates prt
+		WHERE
+			prt.id = ?
+			AND prt.deleted_at IS NULL
+	`
+
+	err := tx.GetContext(ctx, &changed, tx.Rebind(query), append(values, followsProgramPrice, templateId)...)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Str("template_id", templateId).Msg("repo::queueTemplateFeeChange - template not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Template tidak ditemukan")
+		}
+		log.Error().Err(err).Str("template_id", templateId).Msg("repo::queueTemplateFeeChange - failed to compare fees")
+		return nil, err
+	}
+
+	if !changed {
+		return nil, nil
+	}
+
+	// a newer edit replaces the pending change instead of queueing another one
+	var id string
+	query = `
+		SELECT
+			id
+		FROM
+			template_fee_changes
+		WHERE
+			template_id = ?
+			AND status = 'pending'
+		FOR UPDATE
+	`
+
+	err = tx.GetContext(ctx, &id, tx.Rebind(query), templateId)
+	if err != nil && err != sql.ErrNoRows {
+		log.Error().Err(err).Str("template_id", templateId).Msg("repo::queueTemplateFeeChange - failed to fetch pending change")
+		return nil, err
+	}
+
+	if id == "" {
+		id = ulid.Make().String()
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.TemplateFeeChange, id)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		INSERT INTO template_fee_changes (
+			id,
+			template_id,
+			user_id,
+			status,
+			program_fee,
+			administration_fee,
+			foreign_learning_fee,
+			night_learning_fee,
+			marketer_commission_fee,
+			overpayment_fee,
+			hr_fee,
+			marketer_gifts_fee,
+			closing_fee_for_office,
+			closing_fee_for_reward,
+			follows_program_price
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			program_fee = EXCLUDED.program_fee,
+			administration_fee = EXCLUDED.administration_fee,
+			foreign_learning_fee = EXCLUDED.foreign_learning_fee,
+			night_learning_fee = EXCLUDED.night_learning_fee,
+			marketer_commission_fee = EXCLUDED.marketer_commission_fee,
+			overpayment_fee = EXCLUDED.overpayment_fee,
+			hr_fee = EXCLUDED.hr_fee,
+			marketer_gifts_fee = EXCLUDED.marketer_gifts_fee,
+			closing_fee_for_office = EXCLUDED.closing_fee_for_office,
+			closing_fee_for_reward = EXCLUDED.closing_fee_for_reward,
+			follows_program_price = EXCLUDED.follows_program_price,
+			updated_at = NOW()
+	`
+
+	args := append([]any{id, templateId, userId, entity.TemplateFeeChangeStatusPending}, values...)
+	args = append(args, followsProgramPrice)
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Str("template_id", templateId).Msg("repo::queueTemplateFeeChange - failed to save pending change")
+		return nil, err
+	}
+
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func (r *reportRepo) GetTemplateFeeChanges(ctx context.Context, req *entity.GetTemplateFeeChangesReq) (*entity.GetTemplateFeeChangesResp, error) {
+	type dao struct {
+		TotalData int `db:"total_data"`
+		entity.TemplateFeeChange
+	}
+	var (
+		data = make([]dao, 0, req.Paginate)
+		resp = new(entity.GetTemplateFeeChangesResp)
+		args = make([]any, 0, 4)
+	)
+	resp.Items = make([]entity.TemplateFeeChange, 0)
+
+	query := `
+		SELECT
+			COUNT(*) OVER() AS total_data,
+			tfc.id,
+			tfc.template_id,
+			s.name AS student_name,
+			p.name AS program_name,
+			tfc.user_id,
+			u.name AS user_name,
+			tfc.status,
+			` + templateFeesSQL("prt", "current") + `,
+			` + templateFeesSQL("tfc", "proposed") + `,
+			tfc.follows_program_price,
+			tfc.reviewed_at,
+			tfc.reviewed_by,
+			ru.name AS reviewed_by_name,
+			tfc.review_comment,
+			tfc.created_at,
+			tfc.updated_at
+		FROM
+			template_fee_changes tfc
+		JOIN
+			program_registration_templates prt
+			ON tfc.template_id = prt.id
+		JOIN
+			students s
+			ON prt.student_id = s.id
+		JOIN
+			programs p
+			ON prt.program_id = p.id
+		LEFT JOIN
+			users u
+			ON tfc.user_id = u.id
+		LEFT JOIN
+			users ru
+			ON tfc.reviewed_by = ru.id
+		WHERE
+			1 = 1
+	`
+
+	if req.TemplateId != "" {
+		query += ` AND tfc.template_id = ?`
+		args = append(args, req.TemplateId)
+	}
+
+	if req.Status != "" {
+		query += ` AND tfc.status = ?`
+		args = append(args, req.Status)
+	}
+
+	query += ` ORDER BY tfc.updated_at DESC, tfc.id DESC LIMIT ? OFFSET ?`
+	args = append(args, req.Paginate, (req.Page-1)*req.Paginate)
+
+	err := r.db.SelectContext(ctx, &data, r.db.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetTemplateFeeChanges - failed to fetch data")
+		return nil, err
+	}
+
+	for _, item := range data {
+		resp.Meta.TotalData = item.TotalData
+		resp.Items = append(resp.Items, item.TemplateFeeChange)
+	}
+
+	resp.Meta.CountTotalPage(req.Page, req.Paginate, resp.Meta.TotalData)
+
+	return resp, nil
+}
+
+// ReviewTemplateFeeChange decides a pending fee change, an approval copies the
+// proposed fees onto the template
+func (r *reportRepo) ReviewTemplateFeeChange(ctx context.Context, req *entity.ReviewTemplateFeeChangeReq) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewTemplateFeeChange - failed to begin transaction")
+		return err
+	}
+	defer tx.Rollback()
+
+	var change = new(entity.TemplateFeeChangeApproval)
+
+	query := `
+		SELECT
+			tfc.template_id,
+			prt.user_id AS template_user_id,
+			tfc.user_id,
+			tfc.status
+		FROM
+			template_fee_changes tfc
+		JOIN
+			program_registration_templates prt
+			ON tfc.template_id = prt.id
+		WHERE
+			tfc.id = ?
+		FOR UPDATE OF tfc
+	`
+
+	err = tx.GetContext(ctx, change, tx.Rebind(query), req.Id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::ReviewTemplateFeeChange - data not found")
+			return errmsg.NewCustomErrors(404).SetMessage("Perubahan biaya template tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewTemplateFeeChange - failed to fetch data")
+		return err
+	}
+
+	if !change.CanReview() {
+		log.Warn().Any("req", req).Str("status", change.Status).Msg("repo::ReviewTemplateFeeChange - change is already reviewed")
+		return errmsg.NewCustomErrors(409).SetMessage("Perubahan biaya template sudah disetujui atau ditolak")
+	}
+
+	if change.IsMaker(req.UserId) {
+		log.Warn().Any("req", req).Msg("repo::ReviewTemplateFeeChange - maker can not review their own change")
+		return errmsg.NewCustomErrors(403).SetMessage("Perubahan biaya tidak dapat disetujui atau ditolak oleh pembuat template atau pengajunya sendiri")
+	}
+
+	changeTrail, err := audit.Track(ctx, tx, audit.TemplateFeeChange, req.Id)
+	if err != nil {
+		return err
+	}
+
+	templateTrail, err := audit.Track(ctx, tx, audit.Template, change.TemplateId)
+	if err != nil {
+		return err
+	}
+
+	status := entity.TemplateFeeChangeStatusRejected
+	if req.Approve {
+		status = entity.TemplateFeeChangeStatusApproved
+
+		// a propagated program price makes the template follow the program price,
+		// another program fee, marketer commission or hr fee is negotiated and
+		// the template stops following it
+
+		query = `
+			UPDATE
+				program_registration_templates prt
+			SET
+				program_fee = tfc.program_fee,
+				administration_fee = tfc.administration_fee,
+				foreign_learning_fee = tfc.foreign_learning_fee,
+				night_learning_fee = tfc.night_learning_fee,
+				marketer_commission_fee = tfc.marketer_commission_fee,
+				overpayment_fee = tfc.overpayment_fee,
+				hr_fee = tfc.hr_fee,
+				marketer_gifts_fee = tfc.marketer_gifts_fee,
+				closing_fee_for_office = tfc.closing_fee_for_office,
+				closing_fee_for_reward = tfc.closing_fee_for_reward,
+				follows_program_price = tfc.follows_program_price OR (
+					prt.follows_program_price
+					AND (tfc.program_fee, tfc.marketer_commission_fee, tfc.hr_fee)
+					IS NOT DISTINCT FROM (prt.program_fee, prt.marketer_commission_fee, prt.hr_fee)
+				),
+				updated_at = NOW()
+			FROM
+				template_fee_changes tfc
+			WHERE
+				tfc.id = ?
+				AND prt.id = tfc.template_id
+		`
+
+		_, err = tx.ExecContext(ctx, tx.Rebind(query), req.Id)
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Msg("repo::ReviewTemplateFeeChange - failed to apply fees")
+			return err
+		}
+	}
+
+	query = `
+		UPDATE
+			template_fee_changes
+		SET
+			status = ?,
+			reviewed_at = NOW(),
+			reviewed_by = ?,
+			review_comment = ?,
+			updated_at = NOW()
+		WHERE
+			id = ?
+	`
+
+	_, err = tx.ExecContext(ctx, tx.Rebind(query), status, req.UserId, req.Comment, req.Id)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewTemplateFeeChange - failed to review change")
+		return err
+	}
+
+	if err = changeTrail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = templateTrail.Save(ctx, tx); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::ReviewTemplateFeeChange - failed to commit transaction")
+		return err
+	}
+
+	return nil
+}
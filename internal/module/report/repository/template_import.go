@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+// GetTemplateImportReferences returns every student, program, marketer and lecturer whose
+// id, identifier, email, phone or name (case-insensitive) is one of keys
+func (r *reportRepo) GetTemplateImportReferences(ctx context.Context, keys []string) (*entity.TemplateImportReferences, error) {
+	var (
+		resp = new(entity.TemplateImportReferences)
+	)
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		log.Error().Err(err).Msg("repo::GetTemplateImportReferences - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	queryStudents := `
+		SELECT id, name, identifier, NULL AS email, NULL AS phone
+		FROM students
+		WHERE
+			deleted_at IS NULL
+			AND (
+				LOWER(id) = ANY(?)
+				OR LOWER(identifier) = ANY(?)
+				OR LOWER(name) = ANY(?)
+			)
+	`
+
+	err = tx.SelectContext(ctx, &resp.Students, tx.Rebind(queryStudents),
+		pq.Array(keys), pq.Array(keys), pq.Array(keys),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("repo::GetTemplateImportReferences - failed to fetch students")
+		return nil, err
+	}
+
+	queryPrograms := `
+		SELECT id, name, NULL AS identifier, NULL AS email, NULL AS phone
+		FROM programs
+		WHERE
+			deleted_at IS NULL
+			AND (
+				LOWER(id) = ANY(?)
+				OR LOWER(name) = ANY(?)
+			)
+	`
+
+	err = tx.SelectContext(ctx, &resp.Programs, tx.Rebind(queryPrograms),
+		pq.Array(keys), pq.Array(keys),
+	)
+	if err != nil {
+		log.Error().Err(err).Msg("repo::GetTemplateImportReferences - failed to fetch programs")
+		return nil, err
+	}
+
+	for table, dest := range map[string]*[]entity.TemplateImportReference{
+		"marketers": &resp.Marketers,
+		"lecturers": &resp.Lecturers,
+	} {
+		query := `
+			SELECT id, name, NULL AS identifier, email, phone
+			FROM ` + table + `
+			WHERE
+				deleted_at IS NULL
+				AND (
+					LOWER(id) = ANY(?)
+					OR LOWER(email) = ANY(?)
+					OR LOWER(phone) = ANY(?)
+					OR LOWER(name) = ANY(?)
+				)
+		`
+
+		err = tx.SelectContext(ctx, dest, tx.Rebind(query),
+			pq.Array(keys), pq.Array(keys), pq.Array(keys), pq.Array(keys),
+		)
+		if err != nil {
+			log.Error().Err(err).Str("table", table).Msg("repo::GetTemplateImportReferences - failed to fetch data")
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// ImportTemplates checks the combination of every resolved row against the existing
+// templates and, unless it is a dry run or a row is invalid, creates all of them in one transaction
+func (r *reportRepo) ImportTemplates(ctx context.Context, req *entity.ImportTemplatesReq) (*entity.ImportTemplatesResp, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("filename", req.Filename).Msg("repo::ImportTemplates - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	isValid := true
+	for i := range req.Rows {
+		row := &req.Rows[i]
+
+		if row.Template != nil && len(row.Errors) == 0 {
+			exist, err := r.templateCombinationExists(ctx, tx, row.Template)
+			if err != nil {
+				return nil, err
+			}
+
+			if exist {
+				row.AddError("template", templateCombinationExistMsg)
+			}
+		}
+
+		if len(row.Errors) > 0 {
+			isValid = false
+		}
+	}
+
+	resp := &entity.ImportTemplatesResp{
+		DryRun:    req.DryRun,
+		TotalRows: len(req.Rows),
+		Rows:      make([]entity.TemplateImportRowResult, 0, len(req.Rows)),
+	}
+
+	for _, row := range req.Rows {
+		result := entity.TemplateImportRowResult{
+			Row:    row.Row,
+			Status: entity.TemplateImportRowValid,
+			Errors: row.Errors,
+		}
+
+		if len(row.Errors) > 0 {
+			result.Status = entity.TemplateImportRowInvalid
+			resp.InvalidRows++
+		} else {
+			resp.ValidRows++
+		}
+
+		if isValid && !req.DryRun {
+			result.Id, err = r.insertTemplate(ctx, tx, row.Template)
+			if err != nil {
+				return nil, err
+			}
+			result.Status = entity.TemplateImportRowCreated
+		}
+
+		resp.Rows = append(resp.Rows, result)
+	}
+
+	if !isValid || req.DryRun {
+		return resp, nil
+	}
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("filename", req.Filename).Msg("repo::ImportTemplates - failed to commit transaction")
+		return nil, err
+	}
+	resp.Committed = true
+
+	return resp, nil
+}
@@ -57,7 +57,8 @@ func (r *reportRepo) GetTemplates(ctx context.Context, req *entity.GetTemplatesR
 			COALESCE(prt.foreign_learning_fee, 0) +
 			COALESCE(prt.night_learning_fee, 0) +
 			COALESCE(prt.overpayment_fee, 0)
-			AS monthly_fee
+			AS monthly_fee,
+			` + templateOutdatedSQL + ` AS is_outdated
 		FROM
 			program_registration_templates prt
 		LEFT JOIN
@@ -75,9 +76,19 @@ func (r *reportRepo) GetTemplates(ctx context.Context, req *entity.GetTemplatesR
 		JOIN
 			programs p
 			ON prt.program_id = p.id
+		` + programPriceJoinSQL + `
 		WHERE
-			prt.deleted_at IS NULL
+			1 = 1
 	`
+	args = append(args, entity.CurrentBillingPeriod())
+
+	if !req.IncludeArchived {
+		query += ` AND prt.deleted_at IS NULL `
+	}
+
+	if req.Outdated {
+		query += ` AND ` + templateOutdatedSQL
+	}
 
 	if req.MarketerId != "" {
 		query += ` AND prt.marketer_id = ? `
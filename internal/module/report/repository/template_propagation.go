@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// templateOutdatedSQL tells whether program_registration_templates aliased as
+	// prt has other fees than the price of its program aliased as p in effect, it
+	// needs programPriceJoinSQL
+	templateOutdatedSQL = `(prt.program_fee, prt.marketer_commission_fee, prt.hr_fee)
+		IS DISTINCT FROM (` + programPriceSQL + `, ` + programCommissionFeeSQL + `, ` + programLecturerFeeSQL + `)`
+
+	templatePendingFeeChangeSQL = `EXISTS (
+		SELECT
+			1
+		FROM
+			template_fee_changes tfc
+		WHERE
+			tfc.template_id = prt.id
+			AND tfc.status = 'pending'
+	)`
+)
+
+// GetTemplatePropagation previews the active templates of a program that are
+// out of date with its fees in effect in the current billing period
+func (r *reportRepo) GetTemplatePropagation(ctx context.Context, req *entity.GetTemplatePropagationReq) (*entity.GetTemplatePropagationResp, error) {
+	var (
+		resp   = new(entity.GetTemplatePropagationResp)
+		period = entity.CurrentBillingPeriod()
+	)
+	resp.Items = make([]entity.TemplatePropagation, 0)
+
+	query := `
+		SELECT
+			p.id,
+			p.name,
+			` + programPriceSQL + ` AS "program.program_fee",
+			` + programCommissionFeeSQL + ` AS "program.marketer_commission_fee",
+			` + programLecturerFeeSQL + ` AS "program.hr_fee"
+		FROM
+			programs p
+		` + programPriceJoinSQL + `
+		WHERE
+			p.id = ?
+			AND p.deleted_at IS NULL
+	`
+
+	err := r.db.GetContext(ctx, resp, r.db.Rebind(query), period, req.ProgramId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::GetTemplatePropagation - program not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Program tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::GetTemplatePropagation - failed to fetch program")
+		return nil, err
+	}
+
+	query = `
+		SELECT
+			prt.id AS template_id,
+			s.name AS student_name,
+			l.name AS lecturer_name,
+			m.name AS marketer_name,
+			prt.program_fee AS "current.program_fee",
+			prt.marketer_commission_fee AS "current.marketer_commission_fee",
+			prt.hr_fee AS "current.hr_fee",
+			` + programPriceSQL + ` AS "new.program_fee",
+			` + programCommissionFeeSQL + ` AS "new.marketer_commission_fee",
+			` + programLecturerFeeSQL + ` AS "new.hr_fee",
+			` + templatePendingFeeChangeSQL + ` AS has_pending_fee_change
+		FROM
+			program_registration_templates prt
+		JOIN
+			programs p
+			ON prt.program_id = p.id
+		` + programPriceJoinSQL + `
+		JOIN
+			students s
+			ON prt.student_id = s.id
+		JOIN
+			marketers m
+			ON prt.marketer_id = m.id
+		LEFT JOIN
+			lecturers l
+			ON prt.lecturer_id = l.id
+		WHERE
+			prt.program_id = ?
+			AND prt.deleted_at IS NULL
+			AND ` + templateOutdatedSQL + `
+		ORDER BY
+			s.name, prt.id
+	`
+
+	err = r.db.SelectContext(ctx, &resp.Items, r.db.Rebind(query), period, req.ProgramId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::GetTemplatePropagation - failed to fetch templates")
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PropagateProgramFees queues a fee change with the fees of the program in
+// effect in the current billing period for each selected template, the
+// templates follow the program price once an approver accepts their change.
+// Either every change is queued or none is.
+func (r *reportRepo) PropagateProgramFees(ctx context.Context, req *entity.PropagateProgramFeesReq) (*entity.PropagateProgramFeesResp, error) {
+	var (
+		resp        = new(entity.PropagateProgramFeesResp)
+		templateIds = slices.Clone(req.TemplateIds)
+	)
+	slices.Sort(templateIds)
+	templateIds = slices.Compact(templateIds)
+	resp.ProgramId = req.ProgramId
+	resp.TemplateIds = templateIds
+	resp.PendingFeeChangeIds = make([]string, 0, len(templateIds))
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PropagateProgramFees - failed to begin transaction")
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// the program can not change while its fees are proposed
+	var exists bool
+	query := `
+		SELECT
+			TRUE
+		FROM
+			programs
+		WHERE
+			id = ?
+			AND deleted_at IS NULL
+		FOR SHARE
+	`
+
+	err = tx.GetContext(ctx, &exists, tx.Rebind(query), req.ProgramId)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			log.Warn().Any("req", req).Msg("repo::PropagateProgramFees - program not found")
+			return nil, errmsg.NewCustomErrors(404).SetMessage("Program tidak ditemukan")
+		}
+		log.Error().Err(err).Any("req", req).Msg("repo::PropagateProgramFees - failed to fetch program")
+		return nil, err
+	}
+
+	var templates []struct {
+		Id                  string              `db:"id"`
+		HasPendingFeeChange bool                `db:"has_pending_fee_change"`
+		Current             entity.TemplateFees `db:"current"`
+		Program             entity.ProgramFees  `db:"program"`
+	}
+	query, args, err := sqlx.In(`
+		SELECT
+			prt.id,
+			`+templatePendingFeeChangeSQL+` AS has_pending_fee_change,
+			`+templateFeesSQL("prt", "current")+`,
+			`+programPriceSQL+` AS "program.program_fee",
+			`+programCommissionFeeSQL+` AS "program.marketer_commission_fee",
+			`+programLecturerFeeSQL+` AS "program.hr_fee"
+		FROM
+			program_registration_templates prt
+		JOIN
+			programs p
+			ON prt.program_id = p.id
+		`+programPriceJoinSQL+`
+		WHERE
+			prt.id IN (?)
+			AND prt.program_id = ?
+			AND prt.deleted_at IS NULL
+		FOR UPDATE OF prt
+	`, entity.CurrentBillingPeriod(), templateIds, req.ProgramId)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PropagateProgramFees - failed to build query")
+		return nil, err
+	}
+
+	err = tx.SelectContext(ctx, &templates, tx.Rebind(query), args...)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PropagateProgramFees - failed to lock templates")
+		return nil, err
+	}
+
+	if len(templates) != len(templateIds) {
+		log.Warn().Any("req", req).Int("found", len(templates)).Msg("repo::PropagateProgramFees - some templates not found")
+		return nil, errmsg.NewCustomErrors(422).SetMessage("Sebagian template tidak ditemukan, sudah diarsipkan, atau bukan template program ini")
+	}
+
+	// a pending change is not replaced, it may hold other fees someone proposed
+	for _, template := range templates {
+		if template.HasPendingFeeChange {
+			log.Warn().Any("req", req).Str("template_id", template.Id).Msg("repo::PropagateProgramFees - template has a pending fee change")
+			return nil, errmsg.NewCustomErrors(409).SetMessage("Template " + template.Id + " masih memiliki perubahan biaya yang menunggu persetujuan")
+		}
+	}
+
+	for _, template := range templates {
+		id, err := queueTemplateFeeChange(ctx, tx, template.Id, req.UserId, template.Current.WithProgramFees(template.Program), true)
+		if err != nil {
+			return nil, err
+		}
+
+		if id != nil {
+			resp.PendingFeeChangeIds = append(resp.PendingFeeChangeIds, *id)
+		}
+	}
+	resp.TotalQueued = len(resp.PendingFeeChangeIds)
+
+	if err = tx.Commit(); err != nil {
+		log.Error().Err(err).Any("req", req).Msg("repo::PropagateProgramFees - failed to commit transaction")
+		return nil, err
+	}
+
+	return resp, nil
+}
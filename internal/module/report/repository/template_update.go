@@ -2,6 +2,7 @@ package repository
 
 import (
 	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/audit"
 	"codebase-app/pkg/errmsg"
 	"context"
 
@@ -61,6 +62,17 @@ func (r *reportRepo) UpdateTemplate(ctx context.Context, req *entity.UpdateTempl
 		return nil, errmsg.NewCustomErrors(409).SetMessage("Template dengan kombinasi program, marketer, pengajar, dan santri tersebut sudah ada. Silahkan cek kembali atau update data yang sudah ada")
 	}
 
+	// fees wait for an approver, the rest of the template is updated right away
+	pendingFeeChangeId, err := queueTemplateFeeChange(ctx, tx, req.Id, req.UserId, req.Fees(), false)
+	if err != nil {
+		return nil, err
+	}
+
+	trail, err := audit.Track(ctx, tx, audit.Template, req.Id)
+	if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE program_registration_templates SET
 			program_id = ?,
@@ -69,17 +81,6 @@ func (r *reportRepo) UpdateTemplate(ctx context.Context, req *entity.UpdateTempl
 			student_id = ?,
 			days = ?,
 			notes = ?,
-
-			program_fee = ?,
-			administration_fee = ?,
-			foreign_learning_fee = ?,
-			night_learning_fee = ?,
-			marketer_commission_fee = ?,
-			overpayment_fee = ?,
-			hr_fee = ?,
-			marketer_gifts_fee = ?,
-			closing_fee_for_office = ?,
-			closing_fee_for_reward = ?,
 			updated_at = NOW()
 		WHERE
 			id = ?
@@ -89,9 +90,6 @@ func (r *reportRepo) UpdateTemplate(ctx context.Context, req *entity.UpdateTempl
 	_, err = tx.ExecContext(ctx, tx.Rebind(query),
 		req.ProgramId, req.LecturerId, req.MarketerId, req.StudentId,
 		pq.Array(req.Days), req.Notes,
-		req.ProgramFee, req.AdministrationFee, req.FLFee, req.NLFee,
-		req.MarketerCommissionFee, req.OverpaymentFee, req.HRFee, req.MarketerGiftsFee,
-		req.ClosingFeeForOffice, req.ClosingFeeForReward,
 		req.Id,
 	)
 	if err != nil {
@@ -128,8 +126,13 @@ func (r *reportRepo) UpdateTemplate(ctx context.Context, req *entity.UpdateTempl
 		}
 	}
 
+	if err = trail.Save(ctx, tx); err != nil {
+		return nil, err
+	}
+
 	resp := new(entity.UpdateTemplateResp)
 	resp.Id = req.Id
+	resp.PendingFeeChangeId = pendingFeeChangeId
 
 	return resp, nil
 }
@@ -0,0 +1,26 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+func (s *reportService) GetAccountingPeriods(ctx context.Context, req *entity.GetAccountingPeriodsReq) (*entity.GetAccountingPeriodsResp, error) {
+	return s.repo.GetAccountingPeriods(ctx, req)
+}
+
+func (s *reportService) GetAccountingPeriod(ctx context.Context, req *entity.GetAccountingPeriodReq) (*entity.GetAccountingPeriodResp, error) {
+	return s.repo.GetAccountingPeriod(ctx, req)
+}
+
+// CloseAccountingPeriod stores the summary of the billing period as it is
+// reported at closing time together with the closing
+func (s *reportService) CloseAccountingPeriod(ctx context.Context, req *entity.CloseAccountingPeriodReq) error {
+	req.Summarize = applySummaries
+
+	return s.repo.CloseAccountingPeriod(ctx, req)
+}
+
+func (s *reportService) ReopenAccountingPeriod(ctx context.Context, req *entity.ReopenAccountingPeriodReq) error {
+	return s.repo.ReopenAccountingPeriod(ctx, req)
+}
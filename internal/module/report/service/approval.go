@@ -0,0 +1,37 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+var approvalStatusLabels = map[string]string{
+	entity.RegistrationStatusDraft:     "Draf",
+	entity.RegistrationStatusSubmitted: "Diajukan",
+	entity.RegistrationStatusApproved:  "Disetujui",
+	entity.RegistrationStatusRejected:  "Ditolak",
+}
+
+func (s *reportService) SubmitRegistration(ctx context.Context, req *entity.SubmitRegistrationReq) error {
+	return s.repo.SubmitRegistration(ctx, req)
+}
+
+func (s *reportService) ReviewRegistration(ctx context.Context, req *entity.ReviewRegistrationReq) error {
+	return s.repo.ReviewRegistration(ctx, req)
+}
+
+func (s *reportService) GetTemplateFeeChanges(ctx context.Context, req *entity.GetTemplateFeeChangesReq) (*entity.GetTemplateFeeChangesResp, error) {
+	return s.repo.GetTemplateFeeChanges(ctx, req)
+}
+
+func (s *reportService) ReviewTemplateFeeChange(ctx context.Context, req *entity.ReviewTemplateFeeChangeReq) error {
+	return s.repo.ReviewTemplateFeeChange(ctx, req)
+}
+
+func (s *reportService) GetTemplatePropagation(ctx context.Context, req *entity.GetTemplatePropagationReq) (*entity.GetTemplatePropagationResp, error) {
+	return s.repo.GetTemplatePropagation(ctx, req)
+}
+
+func (s *reportService) PropagateProgramFees(ctx context.Context, req *entity.PropagateProgramFeesReq) (*entity.PropagateProgramFeesResp, error) {
+	return s.repo.PropagateProgramFees(ctx, req)
+}
@@ -0,0 +1,173 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/security"
+	"codebase-app/pkg/types"
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// calendarPastMonths is how many billing periods before the current one are
+// kept in a calendar feed
+const calendarPastMonths = 3
+
+func (s *reportService) GetRegistrationSchedule(ctx context.Context, req *entity.GetRegistrationScheduleReq) (*entity.GetRegistrationScheduleResp, error) {
+	return s.repo.GetRegistrationSchedule(ctx, req)
+}
+
+func (s *reportService) GetLecturerLoads(ctx context.Context, req *entity.GetLecturerLoadsReq) (*entity.GetLecturerLoadsResp, error) {
+	return s.repo.GetLecturerLoads(ctx, req)
+}
+
+// GetLecturerCalendarLink returns the private feed link of a lecturer, a user
+// with the lecturer role only gets their own link
+func (s *reportService) GetLecturerCalendarLink(ctx context.Context, req *entity.GetLecturerCalendarLinkReq) (*entity.GetLecturerCalendarLinkResp, error) {
+	lecturer, err := s.repo.GetCalendarLecturer(ctx, req.LecturerId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role == types.RoleLecturer && !lecturer.IsUser {
+		log.Warn().Any("req", req).Msg("service::GetLecturerCalendarLink - user is not the lecturer")
+		return nil, errmsg.NewCustomErrors(403).SetMessage("Tautan kalender hanya dapat diambil oleh pengajar yang bersangkutan")
+	}
+
+	return lecturerCalendarLink(lecturer.Id, lecturer.Version)
+}
+
+// ResetLecturerCalendarLink revokes the feed links of a lecturer handed out
+// before, a user with the lecturer role only resets their own link
+func (s *reportService) ResetLecturerCalendarLink(ctx context.Context, req *entity.ResetLecturerCalendarLinkReq) (*entity.GetLecturerCalendarLinkResp, error) {
+	lecturer, err := s.repo.GetCalendarLecturer(ctx, req.LecturerId, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Role == types.RoleLecturer && !lecturer.IsUser {
+		log.Warn().Any("req", req).Msg("service::ResetLecturerCalendarLink - user is not the lecturer")
+		return nil, errmsg.NewCustomErrors(403).SetMessage("Tautan kalender hanya dapat diperbarui oleh pengajar yang bersangkutan")
+	}
+
+	version, err := s.repo.ResetCalendarVersion(ctx, lecturer.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return lecturerCalendarLink(lecturer.Id, version)
+}
+
+// lecturerCalendarLink signs the feed link of the lecturer at its version
+func lecturerCalendarLink(lecturerId string, version int) (*entity.GetLecturerCalendarLinkResp, error) {
+	key := config.Envs.Calendar.SigningKey
+	if key == "" {
+		log.Error().Msg("service::lecturerCalendarLink - calendar signing key is not configured")
+		return nil, errmsg.NewCustomErrors(500).SetMessage("Kunci tautan kalender belum dikonfigurasi")
+	}
+
+	q := url.Values{}
+	q.Set("signature", security.SignPayloadWithKey(key, entity.LecturerCalendarPayload(lecturerId, version)))
+
+	return &entity.GetLecturerCalendarLinkResp{
+		LecturerId: lecturerId,
+		Link:       config.Envs.App.BaseURL + "/reports/lecturer-calendars/" + lecturerId + "/sessions.ics?" + q.Encode(),
+	}, nil
+}
+
+func (s *reportService) GetLecturerCalendar(ctx context.Context, req *entity.GetLecturerCalendarReq) (*entity.ExportFile, error) {
+	lecturer, err := s.repo.GetCalendarLecturer(ctx, req.LecturerId, "")
+	if err != nil {
+		// a removed lecturer reads as an invalid link to the public feed
+		if errCustom, ok := err.(*errmsg.CustomError); ok && errCustom.Code == 404 {
+			return nil, errmsg.NewCustomErrors(403).SetMessage("Tautan kalender tidak valid")
+		}
+		return nil, err
+	}
+
+	key := config.Envs.Calendar.SigningKey
+	if key == "" || !security.VerifyPayloadWithKey(key, entity.LecturerCalendarPayload(lecturer.Id, lecturer.Version), req.Signature) {
+		log.Warn().Any("req", req).Msg("service::GetLecturerCalendar - signature mismatch")
+		return nil, errmsg.NewCustomErrors(403).SetMessage("Tautan kalender tidak valid")
+	}
+
+	now := time.Now().In(time.FixedZone("Asia/Makassar", 8*3600))
+	req.FromPeriod = time.Date(now.Year(), now.Month()-calendarPastMonths, 1, 0, 0, 0, 0, now.Location()).Format("2006-01")
+
+	sessions, err := s.repo.GetLecturerSessions(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ExportFile{
+		Filename:    "jadwal_" + lecturer.Id + ".ics",
+		ContentType: "text/calendar; charset=utf-8",
+		Content:     writeCalendar(config.Envs.App.Name, lecturer, sessions, now.UTC()),
+	}, nil
+}
+
+// writeCalendar renders the sessions as an iCalendar (RFC 5545) document with
+// one all day event per session, registrations have days but no times
+func writeCalendar(appName string, lecturer *entity.CalendarLecturer, sessions []entity.ScheduledSession, stamp time.Time) []byte {
+	var b strings.Builder
+
+	line := func(name, value string) {
+		content := name + ":" + value
+		// lines longer than 75 octets are folded, continuation lines start with a space
+		for len(content) > 75 {
+			cut := 75
+			for cut > 0 && content[cut]&0xC0 == 0x80 {
+				cut-- // do not split a UTF-8 sequence
+			}
+			b.WriteString(content[:cut] + "\r\n")
+			content = " " + content[cut:]
+		}
+		b.WriteString(content + "\r\n")
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//"+escapeCalendarText(appName)+"//Jadwal Pengajar//ID")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", escapeCalendarText("Jadwal "+lecturer.Name))
+	line("X-WR-TIMEZONE", "Asia/Makassar")
+
+	for _, session := range sessions {
+		date, err := time.Parse("2006-01-02", session.Date)
+		if err != nil {
+			continue
+		}
+
+		description := "Pertemuan belum dicatat"
+		if session.Met {
+			description = "Pertemuan sudah dicatat"
+		}
+
+		line("BEGIN", "VEVENT")
+		line("UID", session.RegistrationId+"-"+date.Format("20060102")+"@lecturer-calendar")
+		line("DTSTAMP", stamp.Format("20060102T150405Z"))
+		line("DTSTART;VALUE=DATE", date.Format("20060102"))
+		line("DTEND;VALUE=DATE", date.AddDate(0, 0, 1).Format("20060102"))
+		line("SUMMARY", escapeCalendarText(session.ProgramName+" - "+session.StudentName))
+		line("DESCRIPTION", escapeCalendarText(description))
+		line("TRANSP", "TRANSPARENT")
+		line("END", "VEVENT")
+	}
+
+	line("END", "VCALENDAR")
+
+	return []byte(b.String())
+}
+
+var calendarTextReplacer = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// escapeCalendarText escapes a TEXT value of an iCalendar property
+func escapeCalendarText(s string) string {
+	return calendarTextReplacer.Replace(s)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscapeCalendarText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "Jadwal Budi", "Jadwal Budi"},
+		{"backslash", `a\b`, `a\\b`},
+		{"semicolon and comma", "a;b,c", `a\;b\,c`},
+		{"newlines", "a\nb\r\nc", `a\nb\nc`},
+		{"escaped once", `\;`, `\\\;`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeCalendarText(tt.in))
+		})
+	}
+}
+
+func TestWriteCalendar(t *testing.T) {
+	lecturer := &entity.CalendarLecturer{Id: "01HLECTURER", Name: "Budi, S.Pd"}
+	sessions := []entity.ScheduledSession{
+		{Date: "2026-10-05", RegistrationId: "01HREG", ProgramName: "Matematika", StudentName: "Ani", Met: true},
+		{Date: "invalid", RegistrationId: "01HSKIP"},
+	}
+	stamp := time.Date(2026, 10, 18, 1, 2, 3, 0, time.UTC)
+
+	out := string(writeCalendar("Bimbel", lecturer, sessions, stamp))
+
+	assert.True(t, strings.HasSuffix(out, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n", "every line ends with CRLF")
+
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+	assert.Equal(t, "BEGIN:VCALENDAR", lines[0])
+	assert.Contains(t, lines, "PRODID:-//Bimbel//Jadwal Pengajar//ID")
+	assert.Contains(t, lines, `X-WR-CALNAME:Jadwal Budi\, S.Pd`)
+	assert.Contains(t, lines, "UID:01HREG-20261005@lecturer-calendar")
+	assert.Contains(t, lines, "DTSTAMP:20261018T010203Z")
+	assert.Contains(t, lines, "DTSTART;VALUE=DATE:20261005")
+	assert.Contains(t, lines, "DTEND;VALUE=DATE:20261006")
+	assert.Contains(t, lines, "SUMMARY:Matematika - Ani")
+	assert.Contains(t, lines, "DESCRIPTION:Pertemuan sudah dicatat")
+	assert.Equal(t, 1, strings.Count(out, "BEGIN:VEVENT"), "a session with an invalid date is skipped")
+	assert.NotContains(t, out, "01HSKIP")
+}
+
+func TestWriteCalendarFoldsLongLines(t *testing.T) {
+	lecturer := &entity.CalendarLecturer{Id: "01HLECTURER", Name: "Budi"}
+	sessions := []entity.ScheduledSession{
+		{Date: "2026-10-05", RegistrationId: "01HREG", ProgramName: strings.Repeat("é", 60), StudentName: "Ani"},
+	}
+
+	out := string(writeCalendar("Bimbel", lecturer, sessions, time.Now()))
+	lines := strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n")
+
+	var (
+		summary string
+		folded  int
+	)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75, "line %d is longer than 75 octets", i)
+		assert.True(t, strings.ToValidUTF8(line, "") == line, "line %d splits a UTF-8 sequence", i)
+
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summary = line
+			for _, next := range lines[i+1:] {
+				if !strings.HasPrefix(next, " ") {
+					break
+				}
+				summary += strings.TrimPrefix(next, " ")
+				folded++
+			}
+		}
+	}
+
+	assert.Greater(t, folded, 0)
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 60)+" - Ani", summary)
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+// GetCommissionStatements groups the registrations of a billing period by
+// marketer and their student manager, the rows come ordered by both so each
+// group is contiguous
+func (s *reportService) GetCommissionStatements(ctx context.Context, req *entity.GetCommissionStatementsReq) (*entity.GetCommissionStatementsResp, error) {
+	rows, err := s.repo.GetCommissionStatementRows(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &entity.GetCommissionStatementsResp{
+		BillingPeriod:   req.BillingPeriod,
+		StudentManagers: make([]entity.StudentManagerCommissionStatement, 0),
+	}
+
+	for _, row := range rows {
+		managers := resp.StudentManagers
+		if len(managers) == 0 || managers[len(managers)-1].Id != row.StudentManagerId {
+			resp.StudentManagers = append(resp.StudentManagers, entity.StudentManagerCommissionStatement{
+				Id:        row.StudentManagerId,
+				Name:      row.StudentManagerName,
+				Marketers: make([]entity.MarketerCommissionStatement, 0),
+			})
+		}
+		manager := &resp.StudentManagers[len(resp.StudentManagers)-1]
+
+		if len(manager.Marketers) == 0 || manager.Marketers[len(manager.Marketers)-1].Id != row.MarketerId {
+			manager.Marketers = append(manager.Marketers, entity.MarketerCommissionStatement{
+				Id:    row.MarketerId,
+				Name:  row.MarketerName,
+				Items: make([]entity.CommissionStatementItem, 0),
+			})
+		}
+		marketer := &manager.Marketers[len(manager.Marketers)-1]
+
+		marketer.Items = append(marketer.Items, row.CommissionStatementItem)
+		marketer.CommissionStatementTotals.Add(row.CommissionStatementItem)
+		manager.CommissionStatementTotals.Add(row.CommissionStatementItem)
+		resp.CommissionStatementTotals.Add(row.CommissionStatementItem)
+	}
+
+	return resp, nil
+}
+
+func (s *reportService) CreateCommissionPayoutBatch(ctx context.Context, req *entity.CreateCommissionPayoutBatchReq) (*entity.CreateCommissionPayoutBatchResp, error) {
+	return s.repo.CreateCommissionPayoutBatch(ctx, req)
+}
+
+func (s *reportService) GetCommissionPayoutBatches(ctx context.Context, req *entity.GetCommissionPayoutBatchesReq) (*entity.GetCommissionPayoutBatchesResp, error) {
+	return s.repo.GetCommissionPayoutBatches(ctx, req)
+}
+
+func (s *reportService) GetCommissionPayoutBatch(ctx context.Context, req *entity.GetCommissionPayoutBatchReq) (*entity.GetCommissionPayoutBatchResp, error) {
+	return s.repo.GetCommissionPayoutBatch(ctx, req)
+}
+
+func (s *reportService) UnlockCommissionPayoutBatch(ctx context.Context, req *entity.UnlockCommissionPayoutBatchReq) error {
+	return s.repo.UnlockCommissionPayoutBatch(ctx, req)
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"bytes"
+	"codebase-app/internal/module/report/entity"
+	"encoding/csv"
+	"strconv"
+
+	"github.com/shopspring/decimal"
+	"github.com/xuri/excelize/v2"
+)
+
+// exportPageSize is the page size used to read every row of a paginated report
+const exportPageSize = 500
+
+// exportTable is a report flattened into rows. Cells may be string, int, bool,
+// decimal.Decimal or *decimal.Decimal; decimals are written as money.
+type exportTable struct {
+	Name    string
+	Headers []string
+	Rows    [][]any
+}
+
+func (t *exportTable) render(format, filename string) (*entity.ExportFile, error) {
+	if format == entity.ExportFormatCSV {
+		return t.renderCSV(filename)
+	}
+
+	return t.renderXLSX(filename)
+}
+
+func (t *exportTable) renderCSV(filename string) (*entity.ExportFile, error) {
+	var (
+		buf = new(bytes.Buffer)
+		w   = csv.NewWriter(buf)
+	)
+
+	if err := w.Write(t.Headers); err != nil {
+		return nil, err
+	}
+
+	for _, row := range t.Rows {
+		record := make([]string, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case decimal.Decimal:
+				record[i] = v.StringFixed(2)
+			case *decimal.Decimal:
+				if v != nil {
+					record[i] = v.StringFixed(2)
+				}
+			case int:
+				record[i] = strconv.Itoa(v)
+			case bool:
+				record[i] = exportBool(v)
+			case string:
+				record[i] = v
+			case *string:
+				if v != nil {
+					record[i] = *v
+				}
+			}
+		}
+
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+
+	return &entity.ExportFile{
+		Filename:    filename + ".csv",
+		ContentType: "text/csv",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+func (t *exportTable) renderXLSX(filename string) (*entity.ExportFile, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	if err := f.SetSheetName("Sheet1", t.Name); err != nil {
+		return nil, err
+	}
+
+	sw, err := f.NewStreamWriter(t.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Bold: true},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"#E6E6E6"}},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// #,##0.00
+	moneyStyle, err := f.NewStyle(&excelize.Style{NumFmt: 4})
+	if err != nil {
+		return nil, err
+	}
+
+	if err = sw.SetColWidth(1, len(t.Headers), 18); err != nil {
+		return nil, err
+	}
+
+	header := make([]any, len(t.Headers))
+	for i, h := range t.Headers {
+		header[i] = excelize.Cell{StyleID: headerStyle, Value: h}
+	}
+	if err = sw.SetRow("A1", header); err != nil {
+		return nil, err
+	}
+
+	for r, row := range t.Rows {
+		values := make([]any, len(row))
+		for i, cell := range row {
+			switch v := cell.(type) {
+			case decimal.Decimal:
+				values[i] = excelize.Cell{StyleID: moneyStyle, Value: v.InexactFloat64()}
+			case *decimal.Decimal:
+				if v != nil {
+					values[i] = excelize.Cell{StyleID: moneyStyle, Value: v.InexactFloat64()}
+				}
+			case bool:
+				values[i] = exportBool(v)
+			case *string:
+				if v != nil {
+					values[i] = *v
+				}
+			default:
+				values[i] = v
+			}
+		}
+
+		axis, err := excelize.CoordinatesToCellName(1, r+2)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = sw.SetRow(axis, values); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = sw.Flush(); err != nil {
+		return nil, err
+	}
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ExportFile{
+		Filename:    filename + ".xlsx",
+		ContentType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+func exportBool(v bool) string {
+	if v {
+		return "Ya"
+	}
+
+	return "Tidak"
+}
+
+// decimalPtr converts the nullable float money columns used by list entities
+func decimalPtr(v *float64) *decimal.Decimal {
+	if v == nil {
+		return nil
+	}
+
+	d := decimal.NewFromFloat(*v)
+	return &d
+}
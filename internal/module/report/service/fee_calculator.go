@@ -0,0 +1,95 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+
+	"github.com/shopspring/decimal"
+)
+
+// calculateFees is the single source of the registration profit formula.
+// Gross revenue is everything billed to the student; the overpayment is billed
+// and returned, so it is counted as a cost as well.
+func calculateFees(f entity.RegistrationFees) entity.FeeBreakdown {
+	gross := f.TotalBill()
+
+	cost := decimal.Sum(
+		f.MarketerCommissionFee,
+		f.MarketerGiftsFee,
+		f.HRFee,
+		f.OverpaymentFee,
+		f.ClosingFeeForOffice,
+		f.ClosingFeeForReward,
+	)
+
+	return entity.FeeBreakdown{
+		Gross:     gross,
+		TotalCost: cost,
+		Profit:    gross.Sub(cost),
+	}
+}
+
+func applySummaryTotals(t *entity.SummaryTotals) {
+	fees := calculateFees(t.Fees())
+	t.TotalGrossRevenue = fees.Gross
+	t.TotalCost = fees.TotalCost
+	t.TotalProfit = fees.Profit
+}
+
+func applySummaries(resp *entity.GetSummariesResp) {
+	applySummaryTotals(&resp.SummaryTotals)
+	for i := range resp.Groups {
+		applySummaryTotals(&resp.Groups[i].SummaryTotals)
+	}
+}
+
+func applyTimeseriesFees(buckets []entity.TimeseriesBucket) {
+	for i := range buckets {
+		fees := calculateFees(buckets[i].Fees())
+		buckets[i].GrossRevenue = fees.Gross
+		buckets[i].TotalCost = fees.TotalCost
+		buckets[i].Profit = fees.Profit
+	}
+}
+
+// RecomputeFees compares the values stored from the fee fields of every
+// registration with the fields and lists the ones that disagree
+func (s *reportService) RecomputeFees(ctx context.Context, req *entity.RecomputeFeesReq) (*entity.RecomputeFeesResp, error) {
+	snapshots, err := s.repo.GetRegistrationFeeSnapshots(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &entity.RecomputeFeesResp{
+		BillingPeriod: req.BillingPeriod,
+		Checked:       len(snapshots),
+		Mismatches:    make([]entity.FeeMismatch, 0),
+	}
+
+	for _, snap := range snapshots {
+		// the hr fee split is stored when it is distributed and is not updated afterwards
+		if snap.HRFeeForMentor == nil && snap.HRFeeForHR == nil {
+			continue
+		}
+
+		split := decimal.Zero
+		if snap.HRFeeForMentor != nil {
+			split = split.Add(*snap.HRFeeForMentor)
+		}
+		if snap.HRFeeForHR != nil {
+			split = split.Add(*snap.HRFeeForHR)
+		}
+
+		if !split.Equal(snap.HRFee) {
+			resp.Mismatches = append(resp.Mismatches, entity.FeeMismatch{
+				RegistrationId: snap.Id,
+				BillingPeriod:  snap.BillingPeriod,
+				Field:          "hr_fee_distribution",
+				Stored:         split,
+				Expected:       snap.HRFee,
+			})
+		}
+	}
+
+	return resp, nil
+}
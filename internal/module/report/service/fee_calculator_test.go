@@ -0,0 +1,122 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func d(v string) decimal.Decimal {
+	return decimal.RequireFromString(v)
+}
+
+func TestCalculateFees(t *testing.T) {
+	tests := []struct {
+		name string
+		fees entity.RegistrationFees
+		want entity.FeeBreakdown
+	}{
+		{
+			name: "empty registration",
+			fees: entity.RegistrationFees{},
+			want: entity.FeeBreakdown{Gross: d("0"), TotalCost: d("0"), Profit: d("0")},
+		},
+		{
+			name: "program fee only",
+			fees: entity.RegistrationFees{ProgramFee: d("500000")},
+			want: entity.FeeBreakdown{Gross: d("500000"), TotalCost: d("0"), Profit: d("500000")},
+		},
+		{
+			name: "every fee and cost",
+			fees: entity.RegistrationFees{
+				ProgramFee:            d("500000"),
+				AdministrationFee:     d("50000"),
+				FLFee:                 d("100000"),
+				NLFee:                 d("75000"),
+				MarketerCommissionFee: d("50000"),
+				MarketerGiftsFee:      d("25000"),
+				HRFee:                 d("200000"),
+				ClosingFeeForOffice:   d("10000"),
+				ClosingFeeForReward:   d("15000"),
+			},
+			want: entity.FeeBreakdown{Gross: d("725000"), TotalCost: d("300000"), Profit: d("425000")},
+		},
+		{
+			name: "overpayment is billed and returned",
+			fees: entity.RegistrationFees{
+				ProgramFee:     d("500000"),
+				OverpaymentFee: d("20000"),
+				HRFee:          d("200000"),
+			},
+			want: entity.FeeBreakdown{Gross: d("520000"), TotalCost: d("220000"), Profit: d("300000")},
+		},
+		{
+			name: "costs above revenue give a loss",
+			fees: entity.RegistrationFees{
+				ProgramFee:            d("100000"),
+				MarketerCommissionFee: d("60000"),
+				HRFee:                 d("80000"),
+			},
+			want: entity.FeeBreakdown{Gross: d("100000"), TotalCost: d("140000"), Profit: d("-40000")},
+		},
+		{
+			name: "fractions are exact",
+			fees: entity.RegistrationFees{
+				ProgramFee:          d("0.1"),
+				AdministrationFee:   d("0.2"),
+				ClosingFeeForReward: d("0.3"),
+			},
+			want: entity.FeeBreakdown{Gross: d("0.3"), TotalCost: d("0.3"), Profit: d("0")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateFees(tt.fees)
+
+			assert.True(t, tt.want.Gross.Equal(got.Gross), "gross: want %s, got %s", tt.want.Gross, got.Gross)
+			assert.True(t, tt.want.TotalCost.Equal(got.TotalCost), "total cost: want %s, got %s", tt.want.TotalCost, got.TotalCost)
+			assert.True(t, tt.want.Profit.Equal(got.Profit), "profit: want %s, got %s", tt.want.Profit, got.Profit)
+		})
+	}
+}
+
+func TestRegisItemFees(t *testing.T) {
+	var (
+		administrationFee = 50000.0
+		overpaymentFee    = 20000.0
+	)
+
+	tests := []struct {
+		name string
+		item entity.RegisItem
+		want decimal.Decimal
+	}{
+		{
+			name: "nullable fees default to zero",
+			item: entity.RegisItem{ProgramFee: 500000, HRFee: 200000},
+			want: d("300000"),
+		},
+		{
+			name: "nullable fees are included when set",
+			item: entity.RegisItem{
+				ProgramFee:            500000,
+				AdministrationFee:     &administrationFee,
+				OverpaymentFee:        &overpaymentFee,
+				MarketerCommissionFee: 50000,
+				HRFee:                 200000,
+			},
+			want: d("300000"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calculateFees(tt.item.Fees()).Profit
+
+			assert.True(t, tt.want.Equal(got), "profit: want %s, got %s", tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+func (s *reportService) GetHistory(ctx context.Context, req *entity.GetHistoryReq) (*entity.GetHistoryResp, error) {
+	return s.repo.GetHistory(ctx, req)
+}
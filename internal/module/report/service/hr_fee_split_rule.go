@@ -0,0 +1,26 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+func (s *reportService) GetHRFeeSplitRules(ctx context.Context, req *entity.GetHRFeeSplitRulesReq) (*entity.GetHRFeeSplitRulesResp, error) {
+	return s.repo.GetHRFeeSplitRules(ctx, req)
+}
+
+func (s *reportService) CreateHRFeeSplitRule(ctx context.Context, req *entity.CreateHRFeeSplitRuleReq) (*entity.CreateHRFeeSplitRuleResp, error) {
+	return s.repo.CreateHRFeeSplitRule(ctx, req)
+}
+
+func (s *reportService) UpdateHRFeeSplitRule(ctx context.Context, req *entity.UpdateHRFeeSplitRuleReq) error {
+	return s.repo.UpdateHRFeeSplitRule(ctx, req)
+}
+
+func (s *reportService) DeleteHRFeeSplitRule(ctx context.Context, req *entity.DeleteHRFeeSplitRuleReq) error {
+	return s.repo.DeleteHRFeeSplitRule(ctx, req)
+}
+
+func (s *reportService) ApplyHRFeeSplitRules(ctx context.Context, req *entity.ApplyHRFeeSplitRulesReq) (*entity.ApplyHRFeeSplitRulesResp, error) {
+	return s.repo.ApplyHRFeeSplitRules(ctx, req)
+}
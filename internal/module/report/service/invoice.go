@@ -0,0 +1,209 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+var paymentStatusLabels = map[string]string{
+	entity.PaymentStatusPaid:          "Lunas",
+	entity.PaymentStatusPartiallyPaid: "Dibayar sebagian",
+	entity.PaymentStatusUnpaid:        "Belum dibayar",
+}
+
+func (s *reportService) GetInvoice(ctx context.Context, req *entity.GetInvoiceReq) (*entity.InvoiceResp, error) {
+	data, err := s.repo.GetInvoiceData(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, tr := newPDF("Invoice " + data.InvoiceNumber())
+	writeInvoicePage(pdf, tr, data)
+
+	link, err := savePrivatePDF(pdf, data.InvoiceFilename())
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::GetInvoice - failed to save invoice")
+		return nil, err
+	}
+
+	return &entity.InvoiceResp{
+		RegistrationId: data.RegistrationId,
+		InvoiceNumber:  data.InvoiceNumber(),
+		Filename:       data.InvoiceFilename(),
+		Link:           link.Link,
+		Expires:        link.Expires,
+	}, nil
+}
+
+func (s *reportService) GenerateInvoices(ctx context.Context, req *entity.GenerateInvoicesReq) (*entity.GenerateInvoicesResp, error) {
+	var (
+		resp = &entity.GenerateInvoicesResp{
+			BillingPeriod: req.BillingPeriod,
+			Items:         make([]entity.InvoiceResp, 0),
+			Failed:        make([]entity.GenerateInvoiceError, 0),
+		}
+	)
+
+	data, err := s.repo.GetInvoicesDataByPeriod(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return resp, nil
+	}
+
+	combined, trCombined := newPDF("Invoice " + formatPeriod(req.BillingPeriod))
+
+	for i := range data {
+		item := &data[i]
+
+		pdf, tr := newPDF("Invoice " + item.InvoiceNumber())
+		writeInvoicePage(pdf, tr, item)
+
+		link, err := savePrivatePDF(pdf, item.InvoiceFilename())
+		if err != nil {
+			log.Error().Err(err).Any("req", req).Str("registration_id", item.RegistrationId).Msg("service::GenerateInvoices - failed to save invoice")
+			resp.Failed = append(resp.Failed, entity.GenerateInvoiceError{
+				RegistrationId: item.RegistrationId,
+				Message:        "Gagal membuat invoice",
+			})
+			continue
+		}
+
+		resp.Items = append(resp.Items, entity.InvoiceResp{
+			RegistrationId: item.RegistrationId,
+			InvoiceNumber:  item.InvoiceNumber(),
+			Filename:       item.InvoiceFilename(),
+			Link:           link.Link,
+			Expires:        link.Expires,
+		})
+
+		writeInvoicePage(combined, trCombined, item)
+	}
+
+	if len(resp.Items) == 0 {
+		return resp, nil
+	}
+
+	filename := "invoices_" + req.BillingPeriod + ".pdf"
+	link, err := savePrivatePDF(combined, filename)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::GenerateInvoices - failed to save combined invoice")
+		return nil, err
+	}
+
+	resp.Combined = &entity.InvoiceResp{
+		InvoiceNumber: "INV/" + req.BillingPeriod,
+		Filename:      filename,
+		Link:          link.Link,
+		Expires:       link.Expires,
+	}
+
+	return resp, nil
+}
+
+// writeInvoicePage adds one page describing a registration invoice to pdf
+func writeInvoicePage(pdf *fpdf.Fpdf, tr func(string) string, data *entity.InvoiceData) {
+	const (
+		labelW = 40.0
+		lineH  = 7.0
+	)
+
+	pdf.AddPage()
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW/2, 10, "INVOICE", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentW/2, 10, tr(data.InvoiceNumber()), "", 1, "R", false, 0, "")
+	pdf.Line(left, pdf.GetY(), pageW-right, pdf.GetY())
+	pdf.Ln(4)
+
+	header := [][2]string{
+		{"Periode", formatPeriod(data.BillingPeriod)},
+		{"Tanggal terbit", formatDate(time.Now().In(time.FixedZone("Asia/Makassar", 8*3600)))},
+		{"Santri", data.StudentName + " (" + data.StudentIdentifier + ")"},
+		{"Program", data.ProgramName},
+		{"Marketer", data.MarketerName},
+	}
+	if data.LecturerName != nil {
+		header = append(header, [2]string{"Pengajar", *data.LecturerName})
+	}
+
+	for _, row := range header {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelW, lineH, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(contentW-labelW, lineH, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+
+	if len(data.Students) > 0 {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelW, lineH, tr("Santri tambahan"), "", 0, "L", false, 0, "")
+		for i, student := range data.Students {
+			if student.Name == nil {
+				continue
+			}
+			if i > 0 {
+				pdf.CellFormat(labelW, lineH, "", "", 0, "L", false, 0, "")
+			}
+			pdf.CellFormat(contentW-labelW, lineH, tr("- "+*student.Name), "", 1, "L", false, 0, "")
+		}
+	}
+
+	pdf.Ln(6)
+
+	// fee lines
+	amountW := 50.0
+	pdf.SetFillColor(230, 230, 230)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(contentW-amountW, lineH+1, tr("Keterangan"), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(amountW, lineH+1, tr("Jumlah"), "1", 1, "R", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 10)
+	lines := []struct {
+		label  string
+		amount decimal.Decimal
+	}{
+		{"Biaya program " + data.ProgramName, data.ProgramFee},
+		{"Biaya administrasi", data.AdministrationFee},
+		{"Biaya pembelajaran bahasa asing", data.FLFee},
+		{"Biaya pembelajaran malam", data.NLFee},
+		{"Kelebihan pembayaran", data.OverpaymentFee},
+	}
+	for i, line := range lines {
+		// the program fee is always printed, other fees only when charged
+		if i > 0 && line.amount.IsZero() {
+			continue
+		}
+		pdf.CellFormat(contentW-amountW, lineH, tr(line.label), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(amountW, lineH, formatRupiah(line.amount), "1", 1, "R", false, 0, "")
+	}
+
+	totals := [][2]string{
+		{"Total tagihan", formatRupiah(data.TotalBill)},
+		{"Sudah dibayar", formatRupiah(data.TotalPaid)},
+		{"Sisa tagihan", formatRupiah(data.OutstandingBalance)},
+	}
+	for i, row := range totals {
+		style := ""
+		if i == 0 || i == len(totals)-1 {
+			style = "B"
+		}
+		pdf.SetFont("Helvetica", style, 10)
+		pdf.CellFormat(contentW-amountW, lineH, tr(row[0]), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(amountW, lineH, row[1], "1", 1, "R", false, 0, "")
+	}
+
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "I", 10)
+	pdf.CellFormat(contentW, lineH, tr("Status pembayaran: "+paymentStatusLabels[data.PaymentStatus]), "", 1, "L", false, 0, "")
+}
@@ -0,0 +1,227 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/rs/zerolog/log"
+)
+
+var lecturerPayoutStatusLabels = map[string]string{
+	entity.LecturerPayoutStatusDraft:    "Draft",
+	entity.LecturerPayoutStatusApproved: "Disetujui",
+	entity.LecturerPayoutStatusPaid:     "Dibayar",
+}
+
+func (s *reportService) GenerateLecturerStatements(ctx context.Context, req *entity.GenerateLecturerStatementsReq) (*entity.GenerateLecturerStatementsResp, error) {
+	return s.repo.GenerateLecturerStatements(ctx, req)
+}
+
+func (s *reportService) GetLecturerStatements(ctx context.Context, req *entity.GetLecturerStatementsReq) (*entity.GetLecturerStatementsResp, error) {
+	return s.repo.GetLecturerStatements(ctx, req)
+}
+
+func (s *reportService) GetLecturerStatement(ctx context.Context, req *entity.GetLecturerStatementReq) (*entity.GetLecturerStatementResp, error) {
+	return s.repo.GetLecturerStatement(ctx, req)
+}
+
+func (s *reportService) ApproveLecturerStatement(ctx context.Context, req *entity.ApproveLecturerStatementReq) error {
+	return s.repo.ApproveLecturerStatement(ctx, req)
+}
+
+func (s *reportService) ReopenLecturerStatement(ctx context.Context, req *entity.ReopenLecturerStatementReq) error {
+	return s.repo.ReopenLecturerStatement(ctx, req)
+}
+
+func (s *reportService) CreateLecturerPayoutBatch(ctx context.Context, req *entity.CreateLecturerPayoutBatchReq) (*entity.CreateLecturerPayoutBatchResp, error) {
+	return s.repo.CreateLecturerPayoutBatch(ctx, req)
+}
+
+func (s *reportService) GetLecturerPayoutBatches(ctx context.Context, req *entity.GetLecturerPayoutBatchesReq) (*entity.GetLecturerPayoutBatchesResp, error) {
+	return s.repo.GetLecturerPayoutBatches(ctx, req)
+}
+
+func (s *reportService) GetLecturerPayoutBatch(ctx context.Context, req *entity.GetLecturerPayoutBatchReq) (*entity.GetLecturerPayoutBatchResp, error) {
+	return s.repo.GetLecturerPayoutBatch(ctx, req)
+}
+
+func (s *reportService) ApproveLecturerPayoutBatch(ctx context.Context, req *entity.ApproveLecturerPayoutBatchReq) error {
+	return s.repo.ApproveLecturerPayoutBatch(ctx, req)
+}
+
+func (s *reportService) PayLecturerPayoutBatch(ctx context.Context, req *entity.PayLecturerPayoutBatchReq) error {
+	return s.repo.PayLecturerPayoutBatch(ctx, req)
+}
+
+func (s *reportService) DeleteLecturerPayoutBatch(ctx context.Context, req *entity.DeleteLecturerPayoutBatchReq) error {
+	return s.repo.DeleteLecturerPayoutBatch(ctx, req)
+}
+
+// GetLecturerPayoutBankFile renders the transfers of an approved batch as a CSV
+// for the bank, every lecturer in it must have a bank account
+func (s *reportService) GetLecturerPayoutBankFile(ctx context.Context, req *entity.GetLecturerPayoutBankFileReq) (*entity.ExportFile, error) {
+	data, err := s.repo.GetLecturerPayoutBankFile(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Batch.Status == entity.LecturerPayoutStatusDraft {
+		log.Warn().Any("req", req).Msg("service::GetLecturerPayoutBankFile - batch is not approved")
+		return nil, errmsg.NewCustomErrors(409).SetMessage("Batch pembayaran honor pengajar belum disetujui")
+	}
+
+	errs := errmsg.NewCustomErrors(422).SetMessage("Rekening bank pengajar belum lengkap")
+	for _, transfer := range data.Transfers {
+		if transfer.BankName == nil || transfer.BankAccountNumber == nil || transfer.BankAccountName == nil {
+			errs.Add(transfer.LecturerId, transfer.LecturerName+" belum memiliki rekening bank")
+		}
+	}
+	if errs.HasErrors() {
+		log.Warn().Any("req", req).Any("errors", errs.Errors).Msg("service::GetLecturerPayoutBankFile - missing bank accounts")
+		return nil, errs
+	}
+
+	table := exportTable{
+		Name:    "Transfer",
+		Headers: []string{"No", "Nama Pengajar", "Bank", "No Rekening", "Nama Rekening", "Jumlah", "Keterangan"},
+		Rows:    make([][]any, 0, len(data.Transfers)),
+	}
+
+	description := "Honor pengajar " + formatPeriod(data.Batch.BillingPeriod)
+	for i, transfer := range data.Transfers {
+		table.Rows = append(table.Rows, []any{
+			i + 1,
+			transfer.LecturerName,
+			transfer.BankName,
+			transfer.BankAccountNumber,
+			transfer.BankAccountName,
+			transfer.TotalAmount,
+			description,
+		})
+	}
+
+	return table.render(entity.ExportFormatCSV, "transfer_honor_pengajar_"+data.Batch.BillingPeriod+"_"+data.Batch.Id)
+}
+
+func (s *reportService) GetLecturerPayslip(ctx context.Context, req *entity.GetLecturerPayslipReq) (*entity.LecturerPayslipResp, error) {
+	data, err := s.repo.GetLecturerStatement(ctx, &entity.GetLecturerStatementReq{
+		UserId: req.UserId,
+		Id:     req.Id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, tr := newPDF("Slip Honor " + data.LecturerName + " " + formatPeriod(data.BillingPeriod))
+	writeLecturerPayslipPage(pdf, tr, data)
+
+	filename := "payslip_" + data.BillingPeriod + "_" + data.Id + ".pdf"
+	link, err := savePrivatePDF(pdf, filename)
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::GetLecturerPayslip - failed to save payslip")
+		return nil, err
+	}
+
+	return &entity.LecturerPayslipResp{
+		StatementId: data.Id,
+		Filename:    filename,
+		Link:        link.Link,
+		Expires:     link.Expires,
+	}, nil
+}
+
+// writeLecturerPayslipPage adds the payslip of a lecturer statement to pdf, one
+// line per student and program like the registration per lecturer report
+func writeLecturerPayslipPage(pdf *fpdf.Fpdf, tr func(string) string, data *entity.GetLecturerStatementResp) {
+	const (
+		labelW = 40.0
+		lineH  = 7.0
+	)
+
+	pdf.AddPage()
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW/2, 10, "SLIP HONOR", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentW/2, 10, tr(lecturerPayoutStatusLabels[data.Status]), "", 1, "R", false, 0, "")
+	pdf.Line(left, pdf.GetY(), pageW-right, pdf.GetY())
+	pdf.Ln(4)
+
+	header := [][2]string{
+		{"Periode", formatPeriod(data.BillingPeriod)},
+		{"Tanggal cetak", formatDate(time.Now().In(time.FixedZone("Asia/Makassar", 8*3600)))},
+		{"Pengajar", data.LecturerName},
+	}
+	if data.BankName != nil && data.BankAccountNumber != nil {
+		account := *data.BankName + " " + *data.BankAccountNumber
+		if data.BankAccountName != nil {
+			account += " a.n. " + *data.BankAccountName
+		}
+		header = append(header, [2]string{"Rekening", account})
+	}
+
+	for _, row := range header {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelW, lineH, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.CellFormat(contentW-labelW, lineH, tr(row[1]), "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(6)
+
+	var (
+		noW     = 10.0
+		amountW = 32.0
+		notesW  = 30.0
+		nameW   = (contentW - noW - amountW*2 - notesW) / 2
+	)
+
+	pdf.SetFillColor(230, 230, 230)
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(noW, lineH+1, "No", "1", 0, "C", true, 0, "")
+	pdf.CellFormat(nameW, lineH+1, tr("Santri"), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(nameW, lineH+1, tr("Program"), "1", 0, "L", true, 0, "")
+	pdf.CellFormat(amountW, lineH+1, tr("Fee Pengajar"), "1", 0, "R", true, 0, "")
+	pdf.CellFormat(amountW, lineH+1, tr("Dibayarkan"), "1", 0, "R", true, 0, "")
+	pdf.CellFormat(notesW, lineH+1, tr("Catatan"), "1", 1, "L", true, 0, "")
+
+	pdf.SetFont("Helvetica", "", 9)
+	for i, item := range data.Items {
+		program := item.ProgramName
+		if item.IsFL {
+			program += " (FL)"
+		}
+		if item.IsNL {
+			program += " (NL)"
+		}
+
+		var notes string
+		if item.Notes != nil {
+			notes = *item.Notes
+		}
+
+		pdf.CellFormat(noW, lineH, strconv.Itoa(i+1), "1", 0, "C", false, 0, "")
+		pdf.CellFormat(nameW, lineH, tr(item.StudentName), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(nameW, lineH, tr(program), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(amountW, lineH, formatRupiah(item.HRFeeForLecturer), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(amountW, lineH, formatRupiah(item.Amount), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(notesW, lineH, tr(notes), "1", 1, "L", false, 0, "")
+	}
+
+	pdf.SetFont("Helvetica", "B", 9)
+	pdf.CellFormat(noW+nameW*2, lineH, tr("Total"), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(amountW, lineH, formatRupiah(data.TotalHRFee), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(amountW, lineH, formatRupiah(data.TotalAmount), "1", 0, "R", false, 0, "")
+	pdf.CellFormat(notesW, lineH, "", "1", 1, "L", false, 0, "")
+
+	pdf.Ln(4)
+	pdf.SetFont("Helvetica", "I", 10)
+	pdf.MultiCell(contentW, lineH, tr("Terbilang: "+spellRupiah(data.TotalAmount.IntPart())), "", "L", false)
+}
@@ -0,0 +1,22 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+)
+
+func (s *reportService) CreateRegistrationMeeting(ctx context.Context, req *entity.CreateRegistrationMeetingReq) (*entity.CreateRegistrationMeetingResp, error) {
+	return s.repo.CreateRegistrationMeeting(ctx, req)
+}
+
+func (s *reportService) GetRegistrationMeetings(ctx context.Context, req *entity.GetRegistrationMeetingsReq) (*entity.GetRegistrationMeetingsResp, error) {
+	return s.repo.GetRegistrationMeetings(ctx, req)
+}
+
+func (s *reportService) DeleteRegistrationMeeting(ctx context.Context, req *entity.DeleteRegistrationMeetingReq) (*entity.RegistrationMeetingCount, error) {
+	return s.repo.DeleteRegistrationMeeting(ctx, req)
+}
+
+func (s *reportService) GetAttendances(ctx context.Context, req *entity.GetAttendancesReq) (*entity.GetAttendancesResp, error) {
+	return s.repo.GetAttendances(ctx, req)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/pkg/security"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/shopspring/decimal"
+)
+
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// newPDF returns an A4 portrait document using the core Helvetica font,
+// tr translates UTF-8 text to the cp1252 encoding used by core fonts.
+func newPDF(title string) (pdf *fpdf.Fpdf, tr func(string) string) {
+	pdf = fpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(title, true)
+	pdf.SetCreator(config.Envs.App.Name, true)
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+
+	return pdf, pdf.UnicodeTranslatorFromDescriptor("")
+}
+
+// savePrivatePDF writes pdf into the private local storage and returns a signed
+// link served by the /storage/private/:filename route.
+func savePrivatePDF(pdf *fpdf.Fpdf, filename string) (security.SignedURL, error) {
+	dir := config.Envs.App.LocalStoragePrivatePath
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return security.SignedURL{}, err
+	}
+
+	if err := pdf.OutputFileAndClose(filepath.Join(dir, filename)); err != nil {
+		return security.SignedURL{}, err
+	}
+
+	return security.GenerateSignedURL(
+		config.Envs.App.BaseURL+"/storage/private/"+filename,
+		time.Duration(config.Envs.Guard.SharedLinkExp)*time.Minute,
+	)
+}
+
+// formatRupiah formats an amount as Rp 1.250.000, cents are only printed when present
+func formatRupiah(amount decimal.Decimal) string {
+	var (
+		sign    string
+		rounded = amount.Round(2)
+	)
+
+	if rounded.IsNegative() {
+		sign = "-"
+		rounded = rounded.Abs()
+	}
+
+	parts := strings.SplitN(rounded.StringFixed(2), ".", 2)
+	integer, fraction := parts[0], parts[1]
+
+	var b strings.Builder
+	for i, c := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+
+	if fraction != "00" {
+		b.WriteString("," + fraction)
+	}
+
+	return sign + "Rp " + b.String()
+}
+
+// formatPeriod turns a YYYY-MM billing period into "Oktober 2026"
+func formatPeriod(period string) string {
+	t, err := time.Parse("2006-01", period)
+	if err != nil {
+		return period
+	}
+
+	return indonesianMonths[t.Month()-1] + " " + t.Format("2006")
+}
+
+// formatDate turns a time into "18 Oktober 2026"
+func formatDate(t time.Time) string {
+	return t.Format("2") + " " + indonesianMonths[t.Month()-1] + " " + t.Format("2006")
+}
+
+var spelledUnits = [...]string{
+	"", "satu", "dua", "tiga", "empat", "lima", "enam",
+	"tujuh", "delapan", "sembilan", "sepuluh", "sebelas",
+}
+
+// spellRupiah spells an amount in Indonesian words for receipts, e.g. "dua ribu lima ratus rupiah"
+func spellRupiah(amount int64) string {
+	if amount == 0 {
+		return "nol rupiah"
+	}
+
+	if amount < 0 {
+		return "minus " + spellRupiah(-amount)
+	}
+
+	return strings.Join(strings.Fields(spellNumber(amount)), " ") + " rupiah"
+}
+
+func spellNumber(n int64) string {
+	switch {
+	case n < 12:
+		return spelledUnits[n]
+	case n < 20:
+		return spellNumber(n-10) + " belas"
+	case n < 100:
+		return spellNumber(n/10) + " puluh " + spellNumber(n%10)
+	case n < 200:
+		return "seratus " + spellNumber(n-100)
+	case n < 1000:
+		return spellNumber(n/100) + " ratus " + spellNumber(n%100)
+	case n < 2000:
+		return "seribu " + spellNumber(n-1000)
+	case n < 1_000_000:
+		return spellNumber(n/1000) + " ribu " + spellNumber(n%1000)
+	case n < 1_000_000_000:
+		return spellNumber(n/1_000_000) + " juta " + spellNumber(n%1_000_000)
+	case n < 1_000_000_000_000:
+		return spellNumber(n/1_000_000_000) + " miliar " + spellNumber(n%1_000_000_000)
+	default:
+		return spellNumber(n/1_000_000_000_000) + " triliun " + spellNumber(n%1_000_000_000_000)
+	}
+}
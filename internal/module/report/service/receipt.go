@@ -0,0 +1,144 @@
+package service
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"codebase-app/pkg/security"
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/go-pdf/fpdf"
+	"github.com/go-pdf/fpdf/contrib/barcode"
+	"github.com/rs/zerolog/log"
+)
+
+func (s *reportService) IssueReceipt(ctx context.Context, req *entity.IssueReceiptReq) (*entity.IssueReceiptResp, error) {
+	req.NumberPrefix = config.Envs.Receipt.NumberPrefix
+	req.SequenceDigits = config.Envs.Receipt.SequenceDigits
+
+	data, err := s.repo.IssueReceipt(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf, tr := newPDF("Kwitansi " + data.ReceiptNumber)
+	writeReceiptPage(pdf, tr, data)
+
+	link, err := savePrivatePDF(pdf, data.Filename())
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::IssueReceipt - failed to save receipt")
+		return nil, err
+	}
+
+	return &entity.IssueReceiptResp{
+		RegistrationId: data.RegistrationId,
+		ReceiptNumber:  data.ReceiptNumber,
+		IsReissue:      data.IsReissue,
+		ReissueCount:   data.ReissueCount,
+		Filename:       data.Filename(),
+		Link:           link.Link,
+		Expires:        link.Expires,
+	}, nil
+}
+
+func (s *reportService) VerifyReceipt(ctx context.Context, req *entity.VerifyReceiptReq) (*entity.VerifyReceiptResp, error) {
+	data, err := s.repo.GetReceiptByNumber(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if !security.VerifyPayload(data.VerificationPayload(), req.Signature) {
+		log.Warn().Any("req", req).Msg("service::VerifyReceipt - signature mismatch")
+		return nil, errmsg.NewCustomErrors(400).SetMessage("Kwitansi tidak valid")
+	}
+
+	if data.IsRevoked {
+		log.Warn().Any("req", req).Msg("service::VerifyReceipt - receipt is revoked")
+	}
+
+	return &entity.VerifyReceiptResp{
+		Valid:         !data.IsRevoked,
+		ReceiptNumber: data.ReceiptNumber,
+		Amount:        data.Amount,
+		PaidAt:        data.PaidDate(),
+		StudentName:   data.StudentName,
+		ProgramName:   data.ProgramName,
+		BillingPeriod: data.BillingPeriod,
+		IssuedAt:      data.IssuedAt,
+		ReissueCount:  data.ReissueCount,
+	}, nil
+}
+
+// receiptVerificationURL is encoded in the receipt QR code
+func receiptVerificationURL(data *entity.ReceiptData) string {
+	q := url.Values{}
+	q.Set("number", data.ReceiptNumber)
+	q.Set("signature", security.SignPayload(data.VerificationPayload()))
+
+	return config.Envs.App.BaseURL + "/reports/receipts/verify?" + q.Encode()
+}
+
+// writeReceiptPage adds one page with the receipt (kwitansi) of a registration to pdf
+func writeReceiptPage(pdf *fpdf.Fpdf, tr func(string) string, data *entity.ReceiptData) {
+	const (
+		labelW = 45.0
+		lineH  = 8.0
+		qrSize = 35.0
+	)
+
+	pdf.AddPage()
+	pageW, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentW := pageW - left - right
+	loc := time.FixedZone("Asia/Makassar", 8*3600)
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.CellFormat(contentW/2, 10, "KWITANSI", "", 0, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(contentW/2, 10, tr("No. "+data.ReceiptNumber), "", 1, "R", false, 0, "")
+	pdf.Line(left, pdf.GetY(), pageW-right, pdf.GetY())
+	pdf.Ln(4)
+
+	rows := [][2]string{
+		{"Telah terima dari", data.StudentName + " (" + data.StudentIdentifier + ")"},
+		{"Uang sejumlah", formatRupiah(data.Amount)},
+		{"Terbilang", spellRupiah(data.Amount.IntPart())},
+		{"Untuk pembayaran", data.ProgramName + " periode " + formatPeriod(data.BillingPeriod)},
+		{"Tanggal pelunasan", formatDate(data.PaidAt.In(loc))},
+	}
+
+	for _, row := range rows {
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(labelW, lineH, tr(row[0]), "", 0, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.MultiCell(contentW-labelW, lineH, tr(row[1]), "", "L", false)
+	}
+
+	pdf.Ln(8)
+	y := pdf.GetY()
+
+	key := barcode.RegisterQR(pdf, receiptVerificationURL(data), qr.M, qr.Unicode)
+	barcode.Barcode(pdf, key, left, y, qrSize, qrSize, false)
+
+	pdf.SetXY(left+qrSize+5, y)
+	pdf.SetFont("Helvetica", "", 8)
+	pdf.MultiCell(contentW/2-qrSize, 5, tr("Pindai kode QR untuk memastikan keaslian nomor, nominal, dan tanggal kwitansi ini."), "", "L", false)
+
+	pdf.SetXY(pageW-right-60, y)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(60, lineH, tr(formatDate(data.IssuedAt.In(loc))), "", 2, "C", false, 0, "")
+	pdf.Ln(16)
+	pdf.SetX(pageW - right - 60)
+	pdf.SetFont("Helvetica", "B", 10)
+	pdf.CellFormat(60, lineH, tr(data.IssuedByName), "T", 1, "C", false, 0, "")
+
+	if data.ReissueCount > 0 {
+		pdf.SetY(y + qrSize + 6)
+		pdf.SetFont("Helvetica", "I", 8)
+		pdf.CellFormat(contentW, 5, tr("Cetak ulang ke-"+strconv.Itoa(data.ReissueCount)+", dicetak "+formatDate(time.Now().In(loc))), "", 1, "L", false, 0, "")
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/shopspring/decimal"
+)
+
+func (s *reportService) ExportRegistrations(ctx context.Context, req *entity.GetRegistrationsReq) (*entity.ExportFile, error) {
+	table := exportTable{
+		Name: "Registrasi",
+		Headers: []string{
+			"Periode", "Tanggal Bayar", "NIS", "Santri", "Santri Tambahan", "Program", "Pengajar", "Marketer",
+			"Biaya Program", "Biaya Administrasi", "Biaya Bahasa Asing", "Biaya Malam", "Kelebihan Bayar",
+			"Komisi Marketer", "Hadiah Marketer", "HR Fee", "HR Fee Pengajar", "HR Fee HR",
+			"Closing Kantor", "Closing Reward", "Profit",
+			"Total Tagihan", "Terbayar", "Sisa Tagihan", "Status Pembayaran", "Status Persetujuan", "Catatan",
+		},
+		Rows: make([][]any, 0),
+	}
+
+	// read every page so the export honours the same filters and sorting as the list
+	pageReq := *req
+	pageReq.Page = 1
+	pageReq.Paginate = exportPageSize
+
+	for {
+		resp, err := s.GetRegistrations(ctx, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Items {
+			names := make([]string, 0, len(item.Students))
+			for _, student := range item.Students {
+				if student.Name != nil {
+					names = append(names, *student.Name)
+				}
+			}
+
+			table.Rows = append(table.Rows, []any{
+				item.BillingPeriod,
+				item.PaidAt,
+				item.StudentIdentifier,
+				item.StudentName,
+				strings.Join(names, ", "),
+				item.ProgramName,
+				item.LecturerName,
+				item.MarketerName,
+				decimal.NewFromFloat(item.ProgramFee),
+				decimalPtr(item.AdministrationFee),
+				decimalPtr(item.FLFee),
+				decimalPtr(item.NLFee),
+				decimalPtr(item.OverpaymentFee),
+				decimal.NewFromFloat(item.MarketerCommissionFee),
+				decimal.NewFromFloat(item.MarketerGiftsFee),
+				decimal.NewFromFloat(item.HRFee),
+				decimalPtr(item.HRFeeForMentor),
+				decimalPtr(item.HRFeeForHR),
+				decimalPtr(item.ClosingFeeForOffice),
+				decimalPtr(item.ClosingFeeForReward),
+				decimal.NewFromFloat(item.Profit),
+				decimal.NewFromFloat(item.TotalBill),
+				decimal.NewFromFloat(item.TotalPaid),
+				decimal.NewFromFloat(item.OutstandingBalance),
+				paymentStatusLabels[item.PaymentStatus],
+				approvalStatusLabels[item.ApprovalStatus],
+				item.Notes,
+			})
+		}
+
+		if pageReq.Page >= resp.Meta.TotalPage {
+			break
+		}
+		pageReq.Page++
+	}
+
+	file, err := table.render(req.Format, "registrasi")
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::ExportRegistrations - failed to render export")
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *reportService) ExportSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.ExportFile, error) {
+	resp, err := s.GetSummaries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Groups) > 0 {
+		return exportSummaryGroups(req, resp)
+	}
+
+	table := exportTable{
+		Name:    "Ringkasan",
+		Headers: []string{"Keterangan", "Nilai"},
+		Rows:    make([][]any, 0),
+	}
+
+	if resp.BillingPeriodFrom != "" {
+		table.Rows = append(table.Rows,
+			[]any{"Periode tagihan dari", resp.BillingPeriodFrom},
+			[]any{"Periode tagihan sampai", resp.BillingPeriodTo},
+		)
+	} else {
+		table.Rows = append(table.Rows,
+			[]any{"Tanggal bayar dari", resp.PaidAtFrom},
+			[]any{"Tanggal bayar sampai", resp.PaidAtTo},
+		)
+	}
+
+	table.Rows = append(table.Rows,
+		[]any{"Total tagihan", resp.TotalBilled},
+		[]any{"Total uang diterima", resp.TotalCashReceived},
+		[]any{"Total sisa tagihan", resp.TotalOutstanding},
+		[]any{"Total HR fee", resp.TotalHrFee},
+		[]any{"Total kelebihan bayar", resp.TotalOverpaymentFee},
+		[]any{"Total komisi marketer", resp.TotalMarketerCommission},
+		[]any{"Total hadiah marketer", resp.TotalMarketerGifts},
+		[]any{"Total closing reward", resp.TotalClosingFeeForReward},
+		[]any{"Total profit", resp.TotalProfit},
+	)
+
+	file, err := table.render(req.Format, "ringkasan-registrasi")
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::ExportSummaries - failed to render export")
+		return nil, err
+	}
+
+	return file, nil
+}
+
+var summaryGroupLabels = map[string]string{
+	entity.SummaryGroupByMarketer:       "Marketer",
+	entity.SummaryGroupByLecturer:       "Pengajar",
+	entity.SummaryGroupByProgram:        "Program",
+	entity.SummaryGroupByStudentManager: "Student Manager",
+	entity.SummaryGroupByMonth:          "Bulan",
+}
+
+// exportSummaryGroups writes one row per group followed by the grand total
+func exportSummaryGroups(req *entity.GetSummariesReq, resp *entity.GetSummariesResp) (*entity.ExportFile, error) {
+	fields := req.GroupByFields()
+
+	table := exportTable{
+		Name:    "Ringkasan",
+		Headers: make([]string, 0),
+		Rows:    make([][]any, 0, len(resp.Groups)+1),
+	}
+
+	for _, field := range fields {
+		table.Headers = append(table.Headers, summaryGroupLabels[field])
+	}
+	table.Headers = append(table.Headers,
+		"Subtotal", "Total Tagihan", "Total Uang Diterima", "Total Sisa Tagihan", "Total HR Fee",
+		"Total Kelebihan Bayar", "Total Komisi Marketer", "Total Hadiah Marketer", "Total Closing Reward", "Total Profit",
+	)
+
+	totalsRow := func(keys []any, isSubtotal bool, totals entity.SummaryTotals) []any {
+		return append(keys, isSubtotal,
+			totals.TotalBilled, totals.TotalCashReceived, totals.TotalOutstanding, totals.TotalHrFee,
+			totals.TotalOverpaymentFee, totals.TotalMarketerCommission, totals.TotalMarketerGifts,
+			totals.TotalClosingFeeForReward, totals.TotalProfit,
+		)
+	}
+
+	for _, group := range resp.Groups {
+		keys := make([]any, len(fields))
+		for i, field := range fields {
+			if key, ok := group.Group[field]; ok {
+				keys[i] = key.Name
+			}
+		}
+
+		table.Rows = append(table.Rows, totalsRow(keys, group.IsSubtotal, group.SummaryTotals))
+	}
+
+	keys := make([]any, len(fields))
+	keys[0] = "Total"
+	table.Rows = append(table.Rows, totalsRow(keys, true, resp.SummaryTotals))
+
+	file, err := table.render(req.Format, "ringkasan-registrasi")
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::exportSummaryGroups - failed to render export")
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// ExportRegistrationsPerLecturer pivots the months of the year into columns
+func (s *reportService) ExportRegistrationsPerLecturer(ctx context.Context, req *entity.GetRegistrationListPerLecturerReq) (*entity.ExportFile, error) {
+	table := exportTable{
+		Name:    "Per Pengajar " + strconv.Itoa(req.Year),
+		Headers: []string{"Pengajar", "Santri", "Program", "Bahasa Asing", "Kelas Malam"},
+		Rows:    make([][]any, 0),
+	}
+
+	for _, month := range indonesianMonths {
+		table.Headers = append(table.Headers, month+" Fee Pengajar", month+" Terpakai")
+	}
+	table.Headers = append(table.Headers, "Total Fee Pengajar", "Total Terpakai", "Total Sisa")
+
+	pageReq := *req
+	pageReq.Page = 1
+	pageReq.Paginate = exportPageSize
+
+	for {
+		resp, err := s.repo.GetRegistrationsPerLecturer(ctx, &pageReq)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range resp.Items {
+			var (
+				row       = []any{item.LecturerName, item.StudentName, item.ProgramName, item.IsFL, item.IsNL}
+				totalFee  = decimal.Zero
+				totalUsed = decimal.Zero
+			)
+
+			// the repository always returns January to December in order
+			for _, month := range item.Registrations {
+				row = append(row, month.HRFeeLecturer, month.UsedAmount)
+
+				if month.HRFeeLecturer != nil {
+					totalFee = totalFee.Add(*month.HRFeeLecturer)
+				}
+				if month.UsedAmount != nil {
+					totalUsed = totalUsed.Add(*month.UsedAmount)
+				}
+			}
+
+			table.Rows = append(table.Rows, append(row, totalFee, totalUsed, totalFee.Sub(totalUsed)))
+		}
+
+		if pageReq.Page >= resp.Meta.TotalPage {
+			break
+		}
+		pageReq.Page++
+	}
+
+	file, err := table.render(req.Format, "registrasi-per-pengajar-"+strconv.Itoa(req.Year))
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::ExportRegistrationsPerLecturer - failed to render export")
+		return nil, err
+	}
+
+	return file, nil
+}
@@ -26,6 +26,14 @@ func (s *reportService) UpdateTemplate(ctx context.Context, req *entity.UpdateTe
 	return s.repo.UpdateTemplate(ctx, req)
 }
 
+func (s *reportService) ArchiveTemplate(ctx context.Context, req *entity.ArchiveTemplateReq) error {
+	return s.repo.ArchiveTemplate(ctx, req)
+}
+
+func (s *reportService) UnarchiveTemplate(ctx context.Context, req *entity.UnarchiveTemplateReq) error {
+	return s.repo.UnarchiveTemplate(ctx, req)
+}
+
 func (s *reportService) GetTemplates(ctx context.Context, req *entity.GetTemplatesReq) (*entity.GetTemplatesResp, error) {
 	return s.repo.GetTemplates(ctx, req)
 }
@@ -34,28 +42,111 @@ func (s *reportService) GetTemplate(ctx context.Context, req *entity.GetTemplate
 	return s.repo.GetTemplate(ctx, req)
 }
 
-func (s *reportService) CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) error {
+func (s *reportService) CreateRegistrations(ctx context.Context, req *entity.CreateRegistrationsReq) (*entity.RegistrationBatchResp, error) {
 	return s.repo.CreateRegistrations(ctx, req)
 }
 
-func (s *reportService) CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) error {
+func (s *reportService) CopyRegistrations(ctx context.Context, req *entity.CopyRegistrationsReq) (*entity.RegistrationBatchResp, error) {
 	return s.repo.CopyRegistrations(ctx, req)
 }
 
+func (s *reportService) GenerateRegistrations(ctx context.Context, req *entity.GenerateRegistrationsReq) (*entity.GenerateRegistrationsResp, error) {
+	return s.repo.GenerateRegistrations(ctx, req)
+}
+
 func (s *reportService) UpdateRegistration(ctx context.Context, req *entity.UpdateRegistrationReq) (*entity.UpdateRegistrationResp, error) {
 	return s.repo.UpdateRegistration(ctx, req)
 }
 
+func (s *reportService) DeleteRegistration(ctx context.Context, req *entity.DeleteRegistrationReq) error {
+	return s.repo.DeleteRegistration(ctx, req)
+}
+
+func (s *reportService) RestoreRegistration(ctx context.Context, req *entity.RestoreRegistrationReq) error {
+	return s.repo.RestoreRegistration(ctx, req)
+}
+
+func (s *reportService) GetDeletedRegistrations(ctx context.Context, req *entity.GetDeletedRegistrationsReq) (*entity.GetDeletedRegistrationsResp, error) {
+	return s.repo.GetDeletedRegistrations(ctx, req)
+}
+
+func (s *reportService) CreateRegistrationPayment(ctx context.Context, req *entity.CreateRegistrationPaymentReq) (*entity.CreateRegistrationPaymentResp, error) {
+	return s.repo.CreateRegistrationPayment(ctx, req)
+}
+
+func (s *reportService) GetRegistrationPayments(ctx context.Context, req *entity.GetRegistrationPaymentsReq) (*entity.GetRegistrationPaymentsResp, error) {
+	return s.repo.GetRegistrationPayments(ctx, req)
+}
+
+func (s *reportService) DeleteRegistrationPayment(ctx context.Context, req *entity.DeleteRegistrationPaymentReq) error {
+	return s.repo.DeleteRegistrationPayment(ctx, req)
+}
+
 func (s *reportService) GetRegistrations(ctx context.Context, req *entity.GetRegistrationsReq) (*entity.GetRegistrationsResp, error) {
-	return s.repo.GetRegistrations(ctx, req)
+	resp, err := s.repo.GetRegistrations(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range resp.Items {
+		resp.Items[i].Profit = calculateFees(resp.Items[i].Fees()).Profit.InexactFloat64()
+	}
+
+	return resp, nil
 }
 
 func (s *reportService) GetRegistration(ctx context.Context, req *entity.GetRegistrationReq) (*entity.GetRegistrationResp, error) {
-	return s.repo.GetRegistration(ctx, req)
+	resp, err := s.repo.GetRegistration(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Profit = calculateFees(resp.Fees()).Profit.InexactFloat64()
+
+	return resp, nil
 }
 
 func (s *reportService) GetSummaries(ctx context.Context, req *entity.GetSummariesReq) (*entity.GetSummariesResp, error) {
-	return s.repo.GetSummaries(ctx, req)
+	resp, err := s.repo.GetSummaries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	applySummaries(resp)
+
+	return resp, nil
+}
+
+func (s *reportService) GetArrears(ctx context.Context, req *entity.GetArrearsReq) (*entity.GetArrearsResp, error) {
+	return s.repo.GetArrears(ctx, req)
+}
+
+func (s *reportService) GetTimeseries(ctx context.Context, req *entity.GetTimeseriesReq) (*entity.GetTimeseriesResp, error) {
+	var (
+		resp = &entity.GetTimeseriesResp{
+			From:     req.From,
+			To:       req.To,
+			Interval: req.Interval,
+			Timezone: req.Timezone,
+		}
+		err error
+	)
+
+	resp.Items, err = s.repo.GetTimeseries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	applyTimeseriesFees(resp.Items)
+
+	if req.ComparePreviousYear {
+		resp.PreviousYear, err = s.repo.GetTimeseries(ctx, req.PreviousYear())
+		if err != nil {
+			return nil, err
+		}
+		applyTimeseriesFees(resp.PreviousYear)
+	}
+
+	return resp, nil
 }
 
 func (s *reportService) GetLecturerPrograms(ctx context.Context, req *entity.GetLecturerProgramsReq) (*entity.GetLecturerProgramsResp, error) {
@@ -70,6 +161,14 @@ func (s *reportService) DistributeHRFee(ctx context.Context, req *entity.HRDistr
 	return s.repo.DistributeHRFee(ctx, req)
 }
 
-func (s *reportService) UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) error {
+func (s *reportService) UseHRfeeForLecturer(ctx context.Context, req *entity.UseHRfeeForLecturerReq) (*entity.UseHRfeeForLecturerResp, error) {
 	return s.repo.UseHRfeeForLecturer(ctx, req)
 }
+
+func (s *reportService) GetMentorFeeUsages(ctx context.Context, req *entity.GetMentorFeeUsagesReq) (*entity.GetMentorFeeUsagesResp, error) {
+	return s.repo.GetMentorFeeUsages(ctx, req)
+}
+
+func (s *reportService) ReverseMentorFeeUsage(ctx context.Context, req *entity.ReverseMentorFeeUsageReq) (*entity.ReverseMentorFeeUsageResp, error) {
+	return s.repo.ReverseMentorFeeUsage(ctx, req)
+}
@@ -0,0 +1,339 @@
+package service
+
+import (
+	"bytes"
+	"codebase-app/internal/adapter"
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"context"
+	"encoding/csv"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/xuri/excelize/v2"
+)
+
+func (s *reportService) GetTemplateImportFile(ctx context.Context, req *entity.GetTemplateImportFileReq) (*entity.ExportFile, error) {
+	table := exportTable{
+		Name:    "Template",
+		Headers: entity.TemplateImportHeaders,
+		Rows:    make([][]any, 0),
+	}
+
+	file, err := table.render(req.Format, "template-import")
+	if err != nil {
+		log.Error().Err(err).Any("req", req).Msg("service::GetTemplateImportFile - failed to render file")
+		return nil, err
+	}
+
+	return file, nil
+}
+
+func (s *reportService) ImportTemplates(ctx context.Context, req *entity.ImportTemplatesReq) (*entity.ImportTemplatesResp, error) {
+	records, err := readImportRecords(req.Format, req.Content)
+	if err != nil {
+		log.Warn().Err(err).Str("filename", req.Filename).Msg("service::ImportTemplates - failed to read file")
+		return nil, errmsg.NewCustomErrors(400).SetMessage("File tidak dapat dibaca, pastikan format file sesuai template")
+	}
+
+	req.Rows, err = parseTemplateImportRows(records)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0)
+	for _, row := range req.Rows {
+		for _, header := range []string{"student", "program", "marketer", "lecturer"} {
+			if v := row.Values[header]; v != "" {
+				keys = append(keys, strings.ToLower(v))
+			}
+		}
+		for _, v := range splitImportList(row.Values["additional_students"], ";") {
+			keys = append(keys, strings.ToLower(v))
+		}
+	}
+
+	refs, err := s.repo.GetTemplateImportReferences(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// rows in the same file must not repeat a combination either
+	combinations := make(map[string]int)
+
+	for i := range req.Rows {
+		row := &req.Rows[i]
+		row.Template = buildImportTemplate(row, refs, req.UserId)
+
+		// unresolved references would only repeat themselves as invalid ids
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		if err := adapter.Adapters.Validator.Validate(row.Template); err != nil {
+			addImportRowErrors(row, err)
+		}
+		if err := row.Template.Validate(); err != nil {
+			addImportRowErrors(row, err)
+		}
+
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		key := row.Template.ProgramId + row.Template.MarketerId + row.Template.StudentId
+		if row.Template.LecturerId != nil {
+			key += *row.Template.LecturerId
+		}
+
+		if first, ok := combinations[key]; ok {
+			row.AddError("template", "Kombinasi program, marketer, pengajar, dan santri sama dengan baris "+strconv.Itoa(first))
+			continue
+		}
+		combinations[key] = row.Row
+	}
+
+	return s.repo.ImportTemplates(ctx, req)
+}
+
+// readImportRecords returns every row of the first sheet of an xlsx file or of a csv file
+func readImportRecords(format string, content []byte) ([][]string, error) {
+	if format == entity.ExportFormatCSV {
+		r := csv.NewReader(bytes.NewReader(content))
+		r.FieldsPerRecord = -1
+		r.TrimLeadingSpace = true
+
+		return r.ReadAll()
+	}
+
+	f, err := excelize.OpenReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return f.GetRows(f.GetSheetName(0), excelize.Options{RawCellValue: true})
+}
+
+// parseTemplateImportRows maps the records to TemplateImportHeaders using the header row,
+// empty rows are skipped and Row is the line number in the file
+func parseTemplateImportRows(records [][]string) ([]entity.TemplateImportRow, error) {
+	if len(records) == 0 {
+		return nil, errmsg.NewCustomErrors(400).SetMessage("File kosong, gunakan template import yang tersedia")
+	}
+
+	var (
+		columns = make(map[string]int)
+		errs    = errmsg.NewCustomErrors(400).SetMessage("Header file tidak sesuai template import")
+	)
+
+	for i, header := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(header))] = i
+	}
+
+	for _, header := range []string{"student", "program", "marketer"} {
+		if _, ok := columns[header]; !ok {
+			errs.Add(header, "kolom "+header+" harus ada")
+		}
+	}
+
+	if errs.HasErrors() {
+		return nil, errs
+	}
+
+	rows := make([]entity.TemplateImportRow, 0, len(records)-1)
+	for i, record := range records[1:] {
+		row := entity.TemplateImportRow{
+			Row:    i + 2,
+			Values: make(map[string]string),
+		}
+
+		isEmpty := true
+		for _, header := range entity.TemplateImportHeaders {
+			idx, ok := columns[header]
+			if !ok || idx >= len(record) {
+				continue
+			}
+
+			row.Values[header] = strings.TrimSpace(record[idx])
+			if row.Values[header] != "" {
+				isEmpty = false
+			}
+		}
+
+		if !isEmpty {
+			rows = append(rows, row)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, errmsg.NewCustomErrors(400).SetMessage("File tidak berisi data template")
+	}
+
+	return rows, nil
+}
+
+// buildImportTemplate resolves the references of row into a CreateTemplateReq,
+// unresolved values are recorded as row errors
+func buildImportTemplate(row *entity.TemplateImportRow, refs *entity.TemplateImportReferences, userId string) *entity.CreateTemplateReq {
+	req := &entity.CreateTemplateReq{
+		UserId:             userId,
+		AdditionalStudents: make([]entity.AddStudent, 0),
+		Days:               make([]int, 0),
+	}
+
+	if v := row.Values["student"]; v == "" {
+		row.AddError("student", "student harus diisi")
+	} else if id, msg := resolveImportReference(refs.Students, v); msg != "" {
+		row.AddError("student", msg)
+	} else {
+		req.StudentId = id
+	}
+
+	if v := row.Values["program"]; v == "" {
+		row.AddError("program", "program harus diisi")
+	} else if id, msg := resolveImportReference(refs.Programs, v); msg != "" {
+		row.AddError("program", msg)
+	} else {
+		req.ProgramId = id
+	}
+
+	if v := row.Values["marketer"]; v == "" {
+		row.AddError("marketer", "marketer harus diisi")
+	} else if id, msg := resolveImportReference(refs.Marketers, v); msg != "" {
+		row.AddError("marketer", msg)
+	} else {
+		req.MarketerId = id
+	}
+
+	if v := row.Values["lecturer"]; v != "" {
+		if id, msg := resolveImportReference(refs.Lecturers, v); msg != "" {
+			row.AddError("lecturer", msg)
+		} else {
+			req.LecturerId = &id
+		}
+	}
+
+	// additional students are linked when the identifier is known, otherwise kept as a name
+	for _, v := range splitImportList(row.Values["additional_students"], ";") {
+		student := entity.AddStudent{}
+		for _, ref := range refs.Students {
+			if strings.EqualFold(ref.Id, v) || (ref.Identifier != nil && strings.EqualFold(*ref.Identifier, v)) {
+				student.StudentId = &ref.Id
+				break
+			}
+		}
+		if student.StudentId == nil {
+			name := v
+			student.Name = &name
+		}
+		req.AdditionalStudents = append(req.AdditionalStudents, student)
+	}
+
+	for _, v := range splitImportList(row.Values["days"], ",") {
+		day, err := strconv.Atoi(v)
+		if err != nil {
+			row.AddError("days", "hari harus berupa angka 1 sampai 7, dipisahkan koma")
+			break
+		}
+		req.Days = append(req.Days, day)
+	}
+
+	if v := row.Values["notes"]; v != "" {
+		req.Notes = &v
+	}
+
+	req.AdministrationFee = parseImportFee(row, "administration_fee")
+	req.MarketerGiftsFee = parseImportFee(row, "marketer_gifts_fee")
+	req.FLFee = parseImportOptionalFee(row, "foreign_learning_fee")
+	req.NLFee = parseImportOptionalFee(row, "night_learning_fee")
+	req.OverpaymentFee = parseImportOptionalFee(row, "overpayment_fee")
+	req.ClosingFeeForOffice = parseImportOptionalFee(row, "closing_fee_for_office")
+	req.ClosingFeeForReward = parseImportOptionalFee(row, "closing_fee_for_reward")
+
+	return req
+}
+
+// resolveImportReference matches value against the id, identifier, email or phone first
+// and falls back to the name, which must then be unique
+func resolveImportReference(refs []entity.TemplateImportReference, value string) (string, string) {
+	matches := make([]string, 0)
+
+	for _, ref := range refs {
+		if strings.EqualFold(ref.Id, value) ||
+			(ref.Identifier != nil && strings.EqualFold(*ref.Identifier, value)) ||
+			(ref.Email != nil && strings.EqualFold(*ref.Email, value)) ||
+			(ref.Phone != nil && strings.EqualFold(*ref.Phone, value)) {
+			return ref.Id, ""
+		}
+
+		if strings.EqualFold(ref.Name, value) {
+			matches = append(matches, ref.Id)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", "'" + value + "' tidak ditemukan"
+	case 1:
+		return matches[0], ""
+	default:
+		return "", "'" + value + "' cocok dengan lebih dari satu data, gunakan id atau identifier"
+	}
+}
+
+func parseImportFee(row *entity.TemplateImportRow, header string) float64 {
+	fee := parseImportOptionalFee(row, header)
+	if fee == nil {
+		return 0
+	}
+
+	return *fee
+}
+
+func parseImportOptionalFee(row *entity.TemplateImportRow, header string) *float64 {
+	v := row.Values[header]
+	if v == "" {
+		return nil
+	}
+
+	fee, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		row.AddError(header, header+" harus berupa angka")
+		return nil
+	}
+
+	return &fee
+}
+
+func splitImportList(value, sep string) []string {
+	items := make([]string, 0)
+	for _, v := range strings.Split(value, sep) {
+		if v = strings.TrimSpace(v); v != "" {
+			items = append(items, v)
+		}
+	}
+
+	return items
+}
+
+// addImportRowErrors copies validation errors of the row template into the row
+func addImportRowErrors(row *entity.TemplateImportRow, err error) {
+	_, errs := errmsg.Errors(err, row.Template)
+
+	fields, ok := errs.(map[string][]string)
+	if customErr, isCustom := errs.(*errmsg.CustomError); isCustom {
+		fields, ok = customErr.Errors, true
+	}
+	if !ok {
+		row.AddError("template", err.Error())
+		return
+	}
+
+	for field, msgs := range fields {
+		for _, msg := range msgs {
+			row.AddError(field, msg)
+		}
+	}
+}
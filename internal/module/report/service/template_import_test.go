@@ -0,0 +1,179 @@
+package service
+
+import (
+	"codebase-app/internal/module/report/entity"
+	"codebase-app/pkg/errmsg"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func strPtr(v string) *string {
+	return &v
+}
+
+func TestReadImportRecordsCSV(t *testing.T) {
+	content := []byte("student,program,marketer\nANI-01, Matematika,Budi\nBUDI-02,Fisika\n")
+
+	records, err := readImportRecords(entity.ExportFormatCSV, content)
+
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"student", "program", "marketer"},
+		{"ANI-01", "Matematika", "Budi"},
+		{"BUDI-02", "Fisika"},
+	}, records)
+}
+
+func TestParseTemplateImportRows(t *testing.T) {
+	records := [][]string{
+		{" Student ", "PROGRAM", "marketer", "days", "unknown"},
+		{"ANI-01", " Matematika ", "Budi", "1,3", "ignored"},
+		{"", "", "", ""},
+		{"BUDI-02", "Fisika"},
+	}
+
+	rows, err := parseTemplateImportRows(records)
+
+	assert.NoError(t, err)
+	if assert.Len(t, rows, 2) {
+		assert.Equal(t, 2, rows[0].Row)
+		assert.Equal(t, map[string]string{
+			"student":  "ANI-01",
+			"program":  "Matematika",
+			"marketer": "Budi",
+			"days":     "1,3",
+		}, rows[0].Values)
+
+		assert.Equal(t, 4, rows[1].Row, "the row number counts the skipped empty row")
+		assert.Equal(t, map[string]string{"student": "BUDI-02", "program": "Fisika"}, rows[1].Values)
+	}
+}
+
+func TestParseTemplateImportRowsErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    [][]string
+		wantFields []string
+	}{
+		{
+			name:    "empty file",
+			records: [][]string{},
+		},
+		{
+			name:       "missing required headers",
+			records:    [][]string{{"student", "lecturer"}, {"ANI-01", "Budi"}},
+			wantFields: []string{"program", "marketer"},
+		},
+		{
+			name:    "header only",
+			records: [][]string{{"student", "program", "marketer"}, {"", " ", ""}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := parseTemplateImportRows(tt.records)
+
+			assert.Nil(t, rows)
+			cerr, ok := err.(*errmsg.CustomError)
+			if assert.True(t, ok) {
+				assert.Equal(t, 400, cerr.Code)
+				assert.Len(t, cerr.Errors, len(tt.wantFields))
+				for _, field := range tt.wantFields {
+					assert.Contains(t, cerr.Errors, field)
+				}
+			}
+		})
+	}
+}
+
+func TestResolveImportReference(t *testing.T) {
+	refs := []entity.TemplateImportReference{
+		{Id: "01HANI", Name: "Ani", Identifier: strPtr("ANI-01"), Email: strPtr("ani@example.com"), Phone: strPtr("08123")},
+		{Id: "01HBUDI", Name: "Budi"},
+		{Id: "01HBUDI2", Name: "budi"},
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		wantId  string
+		wantErr bool
+	}{
+		{"id", "01hani", "01HANI", false},
+		{"identifier", "ani-01", "01HANI", false},
+		{"email", "ANI@example.com", "01HANI", false},
+		{"phone", "08123", "01HANI", false},
+		{"unique name", "ANI", "01HANI", false},
+		{"ambiguous name", "Budi", "", true},
+		{"identifier wins over an ambiguous name", "01HBUDI", "01HBUDI", false},
+		{"not found", "Citra", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			id, msg := resolveImportReference(refs, tt.value)
+
+			assert.Equal(t, tt.wantId, id)
+			assert.Equal(t, tt.wantErr, msg != "")
+		})
+	}
+}
+
+func TestBuildImportTemplate(t *testing.T) {
+	refs := &entity.TemplateImportReferences{
+		Students:  []entity.TemplateImportReference{{Id: "01HANI", Name: "Ani", Identifier: strPtr("ANI-01")}, {Id: "01HCITRA", Name: "Citra", Identifier: strPtr("CITRA-03")}},
+		Programs:  []entity.TemplateImportReference{{Id: "01HMATH", Name: "Matematika"}},
+		Marketers: []entity.TemplateImportReference{{Id: "01HBUDI", Name: "Budi"}},
+		Lecturers: []entity.TemplateImportReference{{Id: "01HDEWI", Name: "Dewi"}},
+	}
+
+	t.Run("valid row", func(t *testing.T) {
+		row := &entity.TemplateImportRow{Row: 2, Values: map[string]string{
+			"student":             "ANI-01",
+			"program":             "Matematika",
+			"marketer":            "Budi",
+			"lecturer":            "Dewi",
+			"additional_students": "citra-03; Eko ;",
+			"days":                "1, 3,5",
+			"notes":               "Les privat",
+			"administration_fee":  "50000",
+			"night_learning_fee":  "25000.5",
+		}}
+
+		req := buildImportTemplate(row, refs, "01HUSER")
+
+		assert.Empty(t, row.Errors)
+		assert.Equal(t, "01HUSER", req.UserId)
+		assert.Equal(t, "01HANI", req.StudentId)
+		assert.Equal(t, "01HMATH", req.ProgramId)
+		assert.Equal(t, "01HBUDI", req.MarketerId)
+		assert.Equal(t, strPtr("01HDEWI"), req.LecturerId)
+		assert.Equal(t, []entity.AddStudent{{StudentId: strPtr("01HCITRA")}, {Name: strPtr("Eko")}}, req.AdditionalStudents)
+		assert.Equal(t, []int{1, 3, 5}, req.Days)
+		assert.Equal(t, strPtr("Les privat"), req.Notes)
+		assert.Equal(t, float64(50000), req.AdministrationFee)
+		assert.Equal(t, float64(0), req.MarketerGiftsFee)
+		assert.Nil(t, req.FLFee)
+		if assert.NotNil(t, req.NLFee) {
+			assert.Equal(t, 25000.5, *req.NLFee)
+		}
+	})
+
+	t.Run("invalid row", func(t *testing.T) {
+		row := &entity.TemplateImportRow{Row: 3, Values: map[string]string{
+			"program":            "Fisika",
+			"marketer":           "Budi",
+			"days":               "senin",
+			"administration_fee": "lima puluh ribu",
+		}}
+
+		buildImportTemplate(row, refs, "01HUSER")
+
+		assert.Len(t, row.Errors, 4)
+		for _, field := range []string{"student", "program", "days", "administration_fee"} {
+			assert.Contains(t, row.Errors, field)
+		}
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/rs/zerolog/log"
 
 	m "codebase-app/internal/middleware"
+	auditHandler "codebase-app/internal/module/audit/handler"
 	masterHandler "codebase-app/internal/module/master/handler"
 	reportHandler "codebase-app/internal/module/report/handler"
 	userHandler "codebase-app/internal/module/user/handler"
@@ -25,6 +26,7 @@ func SetupRoutes(app *fiber.App) {
 	userHandler.NewUserHandler().Register(app.Group("/users"))
 	reportHandler.NewReportHandler().Register(app.Group("/reports"))
 	masterHandler.NewMasterHandler().Register(app.Group("/masters"))
+	auditHandler.NewAuditHandler().Register(app.Group("/audit-logs"))
 
 	// db := adapter.Adapters.Postgres
 
@@ -102,6 +104,7 @@ func storageFile(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(response.Error(err.Error()))
 	}
 
+	c.Type(filepath.Ext(fileName))
 	return c.Send(fileBytes)
 }
 
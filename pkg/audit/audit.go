@@ -0,0 +1,360 @@
+// Package audit records who created, changed or deleted a row and what changed.
+//
+// A change is tracked by snapshotting the rows before it and saving the trail
+// after it, in the same transaction as the change:
+//
+//	trail, err := audit.Track(ctx, tx, audit.Program, req.Id)
+//	...
+//	err = trail.Save(ctx, tx)
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/oklog/ulid/v2"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+)
+
+// the actor is read from the request context, middleware.AuthBearer stores these
+// keys as fiber locals which the fasthttp request context exposes as values
+const (
+	ctxUserIdKey = "user_id"
+	ctxIPKey     = "ip"
+)
+
+// ignoredColumns change on every write and would show up in every diff
+var ignoredColumns = map[string]bool{
+	"updated_at": true,
+}
+
+// Entity is an audited table, Name is how it is filtered in the audit log.
+// Extra is an optional jsonb expression on the row aliased as t merged into its
+// snapshot, for child rows that are edited together with it.
+type Entity struct {
+	Name  string
+	Table string
+	Extra string
+}
+
+var (
+	Program        = Entity{Name: "program", Table: "programs"}
+	ProgramPrice   = Entity{Name: "program_price", Table: "program_prices"}
+	Lecturer       = Entity{Name: "lecturer", Table: "lecturers"}
+	Marketer       = Entity{Name: "marketer", Table: "marketers"}
+	Student        = Entity{Name: "student", Table: "students"}
+	StudentManager = Entity{Name: "student_manager", Table: "student_managers"}
+
+	Template = Entity{
+		Name:  "template",
+		Table: "program_registration_templates",
+		Extra: studentsSQL("additional_students", "prt_additional_students", "prt_id"),
+	}
+	Registration = Entity{
+		Name:  "registration",
+		Table: "program_registrations",
+		Extra: studentsSQL("additional_students", "pr_additional_students", "pr_id"),
+	}
+	Payment        = Entity{Name: "registration_payment", Table: "registration_payments"}
+	Receipt        = Entity{Name: "receipt", Table: "receipts"}
+	MentorFeeUsage = Entity{Name: "mentor_fee_usage", Table: "mentor_fee_usages"}
+	HRFeeSplitRule = Entity{Name: "hr_fee_split_rule", Table: "hr_fee_split_rules"}
+
+	LecturerPayoutStatement = Entity{Name: "lecturer_payout_statement", Table: "lecturer_payout_statements"}
+	LecturerPayoutBatch     = Entity{Name: "lecturer_payout_batch", Table: "lecturer_payout_batches"}
+	AccountingPeriod        = Entity{Name: "accounting_period", Table: "accounting_periods"}
+	TemplateFeeChange       = Entity{Name: "template_fee_change", Table: "template_fee_changes"}
+
+	CommissionPayoutBatch = Entity{
+		Name:  "commission_payout_batch",
+		Table: "commission_payout_batches",
+		Extra: `JSONB_BUILD_OBJECT('items', (
+			SELECT
+				COALESCE(JSONB_AGG(JSONB_BUILD_OBJECT('registration_id', cpi.registration_id, 'amount', cpi.amount, 'unlocked_at', cpi.unlocked_at) ORDER BY cpi.registration_id), '[]')
+			FROM
+				commission_payout_items cpi
+			WHERE
+				cpi.batch_id = t.id
+		))`,
+	}
+	RegistrationMeeting = Entity{
+		Name:  "registration_meeting",
+		Table: "registration_meetings",
+		Extra: studentsSQL("attendees", "registration_meeting_attendees", "meeting_id"),
+	}
+)
+
+// studentsSQL lists the student_id and name of the child rows of t under key
+func studentsSQL(key, table, fk string) string {
+	return `JSONB_BUILD_OBJECT('` + key + `', (
+		SELECT
+			COALESCE(JSONB_AGG(JSONB_BUILD_OBJECT('student_id', adds.student_id, 'name', adds.name) ORDER BY adds.student_id, adds.name), '[]')
+		FROM
+			` + table + ` adds
+		WHERE
+			adds.` + fk + ` = t.id
+	))`
+}
+
+// Entities are the names accepted when filtering the audit log
+var Entities = []string{
+	Program.Name, ProgramPrice.Name, Lecturer.Name, Marketer.Name, Student.Name, StudentManager.Name,
+	Template.Name, Registration.Name, Payment.Name, Receipt.Name, MentorFeeUsage.Name, HRFeeSplitRule.Name,
+	LecturerPayoutStatement.Name, LecturerPayoutBatch.Name, AccountingPeriod.Name, TemplateFeeChange.Name, RegistrationMeeting.Name,
+	CommissionPayoutBatch.Name,
+}
+
+// Trail is a set of rows of one entity as they were before a change
+type Trail struct {
+	entity Entity
+	ids    []string
+	before map[string]json.RawMessage
+}
+
+// Track snapshots the rows of entity with the given ids, ids of rows that do not
+// exist yet are recorded as created when the trail is saved
+func Track(ctx context.Context, q sqlx.ExtContext, entity Entity, ids ...string) (*Trail, error) {
+	t := &Trail{
+		entity: entity,
+		before: make(map[string]json.RawMessage),
+	}
+
+	if err := t.Add(ctx, q, ids...); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Created records rows inserted earlier in the same transaction as created
+func Created(ctx context.Context, q sqlx.ExtContext, entity Entity, ids ...string) error {
+	t := &Trail{
+		entity: entity,
+		ids:    ids,
+		before: make(map[string]json.RawMessage),
+	}
+
+	return t.Save(ctx, q)
+}
+
+// Add snapshots more rows into the trail, rows that are already tracked keep
+// their first snapshot
+func (t *Trail) Add(ctx context.Context, q sqlx.ExtContext, ids ...string) error {
+	pending := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if t.has(id) {
+			continue
+		}
+		t.ids = append(t.ids, id)
+		pending = append(pending, id)
+	}
+
+	rows, err := snapshot(ctx, q, t.entity, pending)
+	if err != nil {
+		return err
+	}
+
+	for id, row := range rows {
+		t.before[id] = row
+	}
+
+	return nil
+}
+
+// Save records one log per tracked row that was created, deleted or changed
+func (t *Trail) Save(ctx context.Context, q sqlx.ExtContext) error {
+	if len(t.ids) == 0 {
+		return nil
+	}
+
+	after, err := snapshot(ctx, q, t.entity, t.ids)
+	if err != nil {
+		return err
+	}
+
+	var (
+		userId, _ = ctx.Value(ctxUserIdKey).(string)
+		ip, _     = ctx.Value(ctxIPKey).(string)
+		values    = make([]string, 0, len(t.ids))
+		args      = make([]any, 0, len(t.ids)*8)
+	)
+
+	for _, id := range t.ids {
+		action, before, changed, err := diff(t.before[id], after[id])
+		if err != nil {
+			log.Error().Err(err).Str("entity", t.entity.Name).Str("id", id).Msg("audit::Save - failed to diff rows")
+			return err
+		}
+
+		if action == "" {
+			continue
+		}
+
+		values = append(values, `(?, ?, ?, ?, ?, ?, ?::JSONB, ?::JSONB)`)
+		args = append(args, ulid.Make().String(), nullable(userId), nullable(ip), t.entity.Name, id, action, nullableJSON(before), nullableJSON(changed))
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO audit_logs (
+			id,
+			user_id,
+			ip,
+			entity,
+			entity_id,
+			action,
+			before,
+			after
+		) VALUES ` + strings.Join(values, ", ")
+
+	if _, err = q.ExecContext(ctx, q.Rebind(query), args...); err != nil {
+		log.Error().Err(err).Str("entity", t.entity.Name).Strs("ids", t.ids).Msg("audit::Save - failed to insert audit logs")
+		return err
+	}
+
+	return nil
+}
+
+func (t *Trail) has(id string) bool {
+	for _, tracked := range t.ids {
+		if tracked == id {
+			return true
+		}
+	}
+	return false
+}
+
+func snapshot(ctx context.Context, q sqlx.ExtContext, entity Entity, ids []string) (map[string]json.RawMessage, error) {
+	rows := make(map[string]json.RawMessage, len(ids))
+	if len(ids) == 0 {
+		return rows, nil
+	}
+
+	// table names come from the Entity variables above, never from a request
+	data := `TO_JSONB(t)`
+	if entity.Extra != "" {
+		data += ` || ` + entity.Extra
+	}
+
+	query, args, err := sqlx.In(`SELECT t.id, `+data+` AS data FROM `+entity.Table+` t WHERE t.id IN (?)`, ids)
+	if err != nil {
+		log.Error().Err(err).Str("entity", entity.Name).Msg("audit::snapshot - failed to build query")
+		return nil, err
+	}
+
+	var result []struct {
+		Id   string          `db:"id"`
+		Data json.RawMessage `db:"data"`
+	}
+	if err = sqlx.SelectContext(ctx, q, &result, q.Rebind(query), args...); err != nil {
+		log.Error().Err(err).Str("entity", entity.Name).Strs("ids", ids).Msg("audit::snapshot - failed to fetch rows")
+		return nil, err
+	}
+
+	for _, row := range result {
+		rows[strings.TrimSpace(row.Id)] = row.Data
+	}
+
+	return rows, nil
+}
+
+// diff returns the action that turned before into after and the columns that
+// changed, with their old and new values. A creation keeps the whole new row
+// and a hard deletion the whole old row. The action is empty when nothing changed.
+func diff(beforeRow, afterRow json.RawMessage) (action string, before, after []byte, err error) {
+	var old, cur map[string]any
+
+	if beforeRow != nil {
+		if old, err = decode(beforeRow); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if afterRow != nil {
+		if cur, err = decode(afterRow); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	switch {
+	case old == nil && cur == nil:
+		return "", nil, nil, nil
+	case old == nil:
+		after, err = json.Marshal(cur)
+		return ActionCreate, nil, after, err
+	case cur == nil:
+		before, err = json.Marshal(old)
+		return ActionDelete, before, nil, err
+	}
+
+	oldChanged := make(map[string]any)
+	curChanged := make(map[string]any)
+	for key, value := range cur {
+		if ignoredColumns[key] || reflect.DeepEqual(old[key], value) {
+			continue
+		}
+		oldChanged[key] = old[key]
+		curChanged[key] = value
+	}
+
+	if len(curChanged) == 0 {
+		return "", nil, nil, nil
+	}
+
+	action = ActionUpdate
+	if deletedAt, ok := curChanged["deleted_at"]; ok {
+		if deletedAt != nil {
+			action = ActionDelete
+		} else {
+			action = ActionRestore
+		}
+	}
+
+	if before, err = json.Marshal(oldChanged); err != nil {
+		return "", nil, nil, err
+	}
+	if after, err = json.Marshal(curChanged); err != nil {
+		return "", nil, nil, err
+	}
+
+	return action, before, after, nil
+}
+
+func decode(row json.RawMessage) (map[string]any, error) {
+	var m map[string]any
+
+	dec := json.NewDecoder(strings.NewReader(string(row)))
+	dec.UseNumber() // keeps decimals exactly as postgres wrote them
+	if err := dec.Decode(&m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// nullableJSON passes json as text, a []byte would be sent as bytea
+func nullableJSON(b []byte) *string {
+	if b == nil {
+		return nil
+	}
+	return nullable(string(b))
+}
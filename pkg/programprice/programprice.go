@@ -0,0 +1,33 @@
+// Package programprice holds the SQL that looks up the price of a program in
+// effect in a billing period, shared by the modules that read program prices
+package programprice
+
+const (
+	// JoinSQL joins as pp the price of the program aliased as p in effect in the
+	// billing period bound to its placeholder. It is the last price effective
+	// from that period or before, or the initial price for periods before the
+	// program was priced.
+	JoinSQL = `
+		LEFT JOIN LATERAL (
+			SELECT
+				pp.id,
+				pp.price,
+				pp.commission_fee,
+				pp.lecturer_fee
+			FROM
+				program_prices pp,
+				(SELECT TO_DATE(?, 'YYYY-MM') AS period) bp
+			WHERE
+				pp.program_id = p.id
+			ORDER BY
+				pp.effective_from > bp.period,
+				ABS(pp.effective_from - bp.period)
+			LIMIT 1
+		) pp ON TRUE
+	`
+
+	// the fees of the program in effect, they need JoinSQL
+	PriceSQL         = `COALESCE(pp.price, p.price)`
+	CommissionFeeSQL = `COALESCE(pp.commission_fee, p.commission_fee)`
+	LecturerFeeSQL   = `COALESCE(pp.lecturer_fee, p.lecturer_fee)`
+)
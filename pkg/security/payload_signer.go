@@ -0,0 +1,34 @@
+package security
+
+import (
+	"codebase-app/internal/infrastructure/config"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// SignPayload returns the hex HMAC-SHA256 of payload using the same key as
+// the signed URLs, used for documents that must be verifiable later.
+func SignPayload(payload string) string {
+	return SignPayloadWithKey(config.Envs.Guard.JwtPrivateKey, payload)
+}
+
+// VerifyPayload reports whether signature is the signature of payload
+func VerifyPayload(payload, signature string) bool {
+	return VerifyPayloadWithKey(config.Envs.Guard.JwtPrivateKey, payload, signature)
+}
+
+// SignPayloadWithKey returns the hex HMAC-SHA256 of payload using key, for
+// links that must be revocable without rotating the key of the signed URLs
+func SignPayloadWithKey(key, payload string) string {
+	h := hmac.New(sha256.New, []byte(key))
+	h.Write([]byte(payload))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// VerifyPayloadWithKey reports whether signature is the signature of payload
+// using key
+func VerifyPayloadWithKey(key, payload, signature string) bool {
+	return hmac.Equal([]byte(SignPayloadWithKey(key, payload)), []byte(signature))
+}
@@ -0,0 +1,12 @@
+package types
+
+// Role names the code checks, the roles themselves are rows of the roles table
+const (
+	// RoleAdmin is the role of the accounts that run billing, payouts and the
+	// accounting periods
+	RoleAdmin = "admin"
+
+	// RoleLecturer is the role of lecturer accounts, bound to their lecturer by
+	// users.lecturer_id
+	RoleLecturer = "lecturer"
+)